@@ -0,0 +1,26 @@
+// Package api holds the interface definitions of the service: the OpenAPI document
+// of the HTTP API and the protobuf schema of the gRPC API.
+package api
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/pkg/errors"
+)
+
+//go:embed openapi.yaml
+var openAPI []byte
+
+// LoadSpec parses and validates the OpenAPI document.
+func LoadSpec() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(openAPI)
+	if err != nil {
+		return nil, errors.Wrap(err, "load openapi spec")
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, errors.Wrap(err, "validate openapi spec")
+	}
+	return doc, nil
+}
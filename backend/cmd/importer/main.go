@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/GkadyrG/L0/backend/config"
+	"github.com/GkadyrG/L0/backend/internal/importer"
+	"github.com/GkadyrG/L0/backend/internal/logger"
+	"github.com/GkadyrG/L0/backend/internal/repository"
+	"github.com/GkadyrG/L0/backend/internal/storage"
+)
+
+func main() {
+	if err := run(); err != nil {
+		slog.Error("import failed", slog.Any("err", err))
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	file := flag.String("file", "", "NDJSON or JSON array file with orders")
+	batch := flag.Int("batch", 500, "orders per COPY batch")
+	checkpointPath := flag.String("checkpoint", "", "checkpoint file (default <file>.checkpoint)")
+	reportPath := flag.String("report", "", "rejected records report (default <file>.errors.ndjson)")
+	opts := config.Flags(flag.CommandLine)
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		return fmt.Errorf("-file is required")
+	}
+	if *checkpointPath == "" {
+		*checkpointPath = *file + ".checkpoint"
+	}
+	if *reportPath == "" {
+		*reportPath = *file + ".errors.ndjson"
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	cfg, err := config.Load(*opts)
+	if err != nil {
+		return err
+	}
+	log := logger.SetupLogger(cfg.Log)
+
+	in, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	checkpoint, err := importer.LoadCheckpoint(*checkpointPath, fmt.Sprintf("%s:%d", *file, info.Size()))
+	if err != nil {
+		return err
+	}
+
+	// a fresh run starts a new report, a resumed one keeps the rejections the
+	// checkpoint covers and drops those of records it is about to process again
+	report, err := os.OpenFile(*reportPath, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := report.Truncate(checkpoint.Report); err != nil {
+		report.Close()
+		return err
+	}
+	if _, err := report.Seek(0, io.SeekEnd); err != nil {
+		report.Close()
+		return err
+	}
+	defer report.Close()
+
+	conn, err := storage.GetConnect(cfg.GetConnStr())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	im := importer.New(repository.New(conn, cfg.Postgres.StatementTimeout), log, importer.Options{
+		BatchSize:  *batch,
+		Checkpoint: checkpoint,
+		Report:     report,
+	})
+
+	res, err := im.Import(ctx, in)
+	if err != nil {
+		log.Error("import interrupted, rerun the same command to resume", "checkpoint", *checkpointPath)
+		return err
+	}
+
+	if err := checkpoint.Remove(); err != nil {
+		return err
+	}
+
+	log.Info("import finished",
+		"records", res.Records,
+		"imported", res.Imported,
+		"duplicates", res.Duplicates,
+		"rejected", res.Rejected,
+		"report", *reportPath,
+	)
+
+	return nil
+}
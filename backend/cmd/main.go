@@ -1,15 +1,47 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log/slog"
 	"os"
 
+	"github.com/GkadyrG/L0/backend/config"
 	"github.com/GkadyrG/L0/backend/internal/app"
 )
 
 func main() {
-	if err := app.Run(); err != nil {
+	if err := run(os.Args[1:]); err != nil {
 		slog.Error("app run", slog.Any("err", err))
 		os.Exit(1)
 	}
 }
+
+// run starts the service, or with "config print" shows the settings it would use.
+func run(args []string) error {
+	if len(args) > 0 && args[0] == "config" {
+		return configCommand(args[1:])
+	}
+
+	fs := flag.NewFlagSet("order-service", flag.ExitOnError)
+	opts := config.Flags(fs)
+	_ = fs.Parse(args)
+
+	return app.Run(*opts)
+}
+
+func configCommand(args []string) error {
+	if len(args) == 0 || args[0] != "print" {
+		return fmt.Errorf("usage: order-service config print [flags]")
+	}
+
+	fs := flag.NewFlagSet("config print", flag.ExitOnError)
+	opts := config.Flags(fs)
+	_ = fs.Parse(args[1:])
+
+	cfg, err := config.Load(*opts)
+	if err != nil {
+		return err
+	}
+	return cfg.Print(os.Stdout)
+}
@@ -2,61 +2,152 @@ package config
 
 import (
 	"fmt"
-	"log"
 	"time"
-
-	"github.com/ilyakaznacheev/cleanenv"
 )
 
 type PostgresConfig struct {
 	UserName string `env:"POSTGRES_USER" env-required:"true"`
-	Password string `env:"POSTGRES_PASSWORD" env-required:"true"`
+	Password string `env:"POSTGRES_PASSWORD" env-required:"true" env-secret:"true"`
 	Host     string `env:"POSTGRES_HOST" env-required:"true"`
 	Port     string `env:"POSTGRES_PORT" env-required:"true"`
 	DBName   string `env:"POSTGRES_DB" env-required:"true"`
+	// StatementTimeout is the deadline of the repository calls that serve requests and
+	// events, also sent to Postgres as statement_timeout of their transactions;
+	// exports and analytics are not bound by it, imports only per batch. 0 disables it.
+	StatementTimeout time.Duration `env:"POSTGRES_STATEMENT_TIMEOUT" env-default:"5s"`
 }
 
 type AppConfig struct {
 	Port     string `env:"APP_PORT" env-required:"true"`
 	Address  string `env:"APP_ADDRESS" env-required:"true"`
-	LogLevel string `env:"APP_LOG_LEVEL" env-required:"true"`
+	GRPCPort string `env:"GRPC_PORT" env-default:"9090"`
+	// AdminToken guards /api/admin; the admin API is disabled when it is empty.
+	AdminToken string `env:"ADMIN_TOKEN" env-secret:"true"`
+}
+
+// LogConfig sets up the service logger. With SampleInfo above 1 only every
+// SampleInfo-th record of each debug or info message is written.
+type LogConfig struct {
+	Level      string `env:"APP_LOG_LEVEL" env-required:"true"`
+	Format     string `env:"APP_LOG_FORMAT" env-default:"json"`
+	SampleInfo int    `env:"APP_LOG_SAMPLE_INFO" env-default:"0"`
+}
+
+type HTTPConfig struct {
+	ReadHeaderTimeout time.Duration `env:"HTTP_READ_HEADER_TIMEOUT" env-default:"5s"`
+	ReadTimeout       time.Duration `env:"HTTP_READ_TIMEOUT" env-default:"15s"`
+	WriteTimeout      time.Duration `env:"HTTP_WRITE_TIMEOUT" env-default:"30s"`
+	IdleTimeout       time.Duration `env:"HTTP_IDLE_TIMEOUT" env-default:"2m"`
+	// HandlerTimeout is the deadline of an API request; exports get ExportTimeout
+	// instead, streams and WebSockets have none.
+	HandlerTimeout time.Duration `env:"HTTP_HANDLER_TIMEOUT" env-default:"10s"`
+	ExportTimeout  time.Duration `env:"HTTP_EXPORT_TIMEOUT" env-default:"5m"`
+	// DrainDelay is how long /readyz reports not ready before the listeners close
+	// on shutdown.
+	DrainDelay        time.Duration `env:"HTTP_DRAIN_DELAY" env-default:"3s"`
+	ReadyCheckTimeout time.Duration `env:"HTTP_READY_CHECK_TIMEOUT" env-default:"2s"`
 }
 
 type CacheConfig struct {
 	TTL             time.Duration `env:"CACHE_TTL" env-required:"true"`
 	CleanupInterval time.Duration `env:"CACHE_CLEANUP_INTERVAL" env-required:"true"`
+	AnalyticsTTL    time.Duration `env:"CACHE_ANALYTICS_TTL" env-default:"1m"`
 }
 
 type KafkaConfig struct {
-	KafkaBroker  string `env:"KAFKA_BROKERS" env-required:"true"`
-	KafkaTopic   string `env:"KAFKA_TOPIC" env-required:"true"`
-	KafkaGroupID string `env:"KAFKA_GROUP_ID" env-required:"true"`
+	KafkaBrokers     []string `env:"KAFKA_BROKERS" env-separator:"," env-required:"true"`
+	KafkaTopic       string   `env:"KAFKA_TOPIC" env-required:"true"`
+	KafkaStatusTopic string   `env:"KAFKA_STATUS_TOPIC" env-default:"order-status-events"`
+	KafkaGroupID     string   `env:"KAFKA_GROUP_ID" env-required:"true"`
+	// BatchSize and BatchTimeout bound how many orders the consumer accumulates before writing them in one transaction.
+	BatchSize    int           `env:"KAFKA_BATCH_SIZE" env-default:"100"`
+	BatchTimeout time.Duration `env:"KAFKA_BATCH_TIMEOUT" env-default:"200ms"`
+	// Workers is the number of goroutines processing orders of one partition; messages with the same key go to the same worker.
+	Workers int `env:"KAFKA_WORKERS" env-default:"4"`
+}
+
+type OutboxConfig struct {
+	Topic        string        `env:"OUTBOX_TOPIC" env-default:"order-stored-events"`
+	PollInterval time.Duration `env:"OUTBOX_POLL_INTERVAL" env-default:"1s"`
+	BatchSize    int           `env:"OUTBOX_BATCH_SIZE" env-default:"100"`
+	// Lease is how long a claimed batch is hidden from other relays; it must outlast a publish.
+	Lease time.Duration `env:"OUTBOX_LEASE" env-default:"1m"`
+}
+
+type WebhookConfig struct {
+	PollInterval time.Duration `env:"WEBHOOK_POLL_INTERVAL" env-default:"1s"`
+	BatchSize    int           `env:"WEBHOOK_BATCH_SIZE" env-default:"50"`
+	Timeout      time.Duration `env:"WEBHOOK_TIMEOUT" env-default:"5s"`
+	// MaxAttempts bounds retries of one delivery; the delay doubles from Backoff up to MaxBackoff.
+	MaxAttempts int           `env:"WEBHOOK_MAX_ATTEMPTS" env-default:"8"`
+	Backoff     time.Duration `env:"WEBHOOK_BACKOFF" env-default:"5s"`
+	MaxBackoff  time.Duration `env:"WEBHOOK_MAX_BACKOFF" env-default:"1h"`
+	// DisableAfter consecutive failed attempts, regardless of delivery, disable the webhook.
+	DisableAfter int `env:"WEBHOOK_DISABLE_AFTER" env-default:"20"`
+	// SecretKey is a hex encoded AES-256 key for webhook secrets at rest; without it they are stored in plaintext.
+	SecretKey string `env:"WEBHOOK_SECRET_KEY" env-secret:"true"`
+}
+
+type FeedConfig struct {
+	// BufferSize is how many recent orders are kept for clients resuming with Last-Event-ID.
+	BufferSize int           `env:"FEED_BUFFER_SIZE" env-default:"1000"`
+	Heartbeat  time.Duration `env:"FEED_HEARTBEAT" env-default:"15s"`
+}
+
+type WSConfig struct {
+	// SendBuffer is how many messages may wait for a slow client before it is disconnected.
+	SendBuffer       int           `env:"WS_SEND_BUFFER" env-default:"16"`
+	MaxSubscriptions int           `env:"WS_MAX_SUBSCRIPTIONS" env-default:"20"`
+	PingInterval     time.Duration `env:"WS_PING_INTERVAL" env-default:"30s"`
+}
+
+// APIConfig holds the deprecation schedule of API v1, announced in its response headers.
+type APIConfig struct {
+	V1DeprecatedAt time.Time `env:"API_V1_DEPRECATED_AT" env-layout:"2006-01-02" env-default:"2026-11-01"`
+	V1Sunset       time.Time `env:"API_V1_SUNSET" env-layout:"2006-01-02" env-default:"2027-05-01"`
+}
+
+// TracingConfig selects where spans go: "none" keeps only trace context propagation,
+// "stdout" prints spans for local debugging and "otlp" sends them to a collector.
+type TracingConfig struct {
+	Exporter     string  `env:"TRACING_EXPORTER" env-default:"none"`
+	OTLPEndpoint string  `env:"TRACING_OTLP_ENDPOINT" env-default:"localhost:4317"`
+	OTLPInsecure bool    `env:"TRACING_OTLP_INSECURE" env-default:"true"`
+	ServiceName  string  `env:"TRACING_SERVICE_NAME" env-default:"order-service"`
+	SampleRatio  float64 `env:"TRACING_SAMPLE_RATIO" env-default:"1"`
+}
+
+// RateLimitConfig limits API requests per client IP to RPS with bursts of up to
+// Burst requests; an RPS of 0 turns the limit off.
+type RateLimitConfig struct {
+	RPS   float64 `env:"RATE_LIMIT_RPS" env-default:"0"`
+	Burst int     `env:"RATE_LIMIT_BURST" env-default:"20"`
 }
 
 type CorsConfig struct {
 	Enabled        bool     `env:"CORS_ENABLED" env-default:"false"`
 	AllowedOrigins []string `env:"CORS_ALLOWED_ORIGINS" env-separator:","`
-	AllowedMethods []string `env:"CORS_ALLOWED_METHODS" env-separator:"," env-default:"GET,OPTIONS"`
+	AllowedMethods []string `env:"CORS_ALLOWED_METHODS" env-separator:"," env-default:"GET,POST,OPTIONS"`
 	AllowedHeaders []string `env:"CORS_ALLOWED_HEADERS" env-separator:"," env-default:"*"`
 }
 
 type Config struct {
 	Postgres         PostgresConfig
 	App              AppConfig
+	Log              LogConfig
+	HTTP             HTTPConfig
 	Cache            CacheConfig
 	Kafka            KafkaConfig
+	Outbox           OutboxConfig
+	Webhook          WebhookConfig
+	Feed             FeedConfig
+	WS               WSConfig
 	MigratePath      string `env:"MIGRATE_PATH" env-required:"true"`
 	EmulatorMessages int    `env:"EMULATOR_MESSAGES" env-default:"50"`
 	Cors             CorsConfig
-}
-
-func LoadConfig() *Config {
-	cfg := &Config{}
-	err := cleanenv.ReadConfig("./.env", cfg)
-	if err != nil {
-		log.Fatalf("error reading config: %s", err.Error())
-	}
-	return cfg
+	RateLimit        RateLimitConfig
+	API              APIConfig
+	Tracing          TracingConfig
 }
 
 func (c *Config) GetConnStr() string {
@@ -70,9 +161,9 @@ func (c *Config) GetDSN() string {
 }
 
 func (c *Config) GetKafkaBrokers() []string {
-	return []string{c.Kafka.KafkaBroker}
+	return c.Kafka.KafkaBrokers
 }
 
 func (c *Config) GetKafkeTopics() []string {
-	return []string{c.Kafka.KafkaTopic}
+	return []string{c.Kafka.KafkaTopic, c.Kafka.KafkaStatusTopic}
 }
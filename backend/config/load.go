@@ -0,0 +1,328 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/joho/godotenv"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile is read when no file is given and it exists.
+const DefaultFile = "./.env"
+
+// fileSuffix marks a setting whose value is read from the named file, such as
+// POSTGRES_PASSWORD_FILE=/run/secrets/postgres_password.
+const fileSuffix = "_FILE"
+
+// Options selects where settings come from besides defaults and the environment.
+type Options struct {
+	// File is a .env, .yaml/.yml or .toml file; DefaultFile is tried when it is empty.
+	File string
+	// Overrides map setting names to values and win over every other source; the
+	// command line flags end up here.
+	Overrides map[string]string
+}
+
+// setting is one leaf of Config, named after its env tag. cleanenv parses the
+// values; the tags are also read here for the required check, Print and Flags.
+type setting struct {
+	name     string
+	def      string
+	hasDef   bool
+	sep      string
+	layout   string
+	required bool
+	secret   bool
+	value    reflect.Value
+}
+
+// settings lists the fields of cfg in declaration order.
+func settings(cfg *Config) []setting {
+	var out []setting
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		for i := range v.NumField() {
+			field, tag := v.Field(i), v.Type().Field(i).Tag
+			name, ok := tag.Lookup("env")
+			if !ok {
+				if field.Kind() == reflect.Struct {
+					walk(field)
+				}
+				continue
+			}
+			def, hasDef := tag.Lookup("env-default")
+			out = append(out, setting{
+				name:     name,
+				def:      def,
+				hasDef:   hasDef,
+				sep:      tag.Get("env-separator"),
+				layout:   tag.Get("env-layout"),
+				required: tag.Get("env-required") == "true",
+				secret:   tag.Get("env-secret") == "true",
+				value:    field,
+			})
+		}
+	}
+	walk(reflect.ValueOf(cfg).Elem())
+	return out
+}
+
+// source returns the raw value of a setting, if it has one.
+type source func(name string) (string, bool, error)
+
+// envMu serialises Load, which hands the merged settings to cleanenv through the
+// process environment.
+var envMu sync.Mutex
+
+// Load builds the config from, in increasing priority: defaults, the file, the
+// environment and the overrides. A setting NAME may also be given as NAME_FILE
+// holding the path of a file with the value, which suits mounted secrets. Missing
+// required settings and failed checks are reported in one *ValidationError.
+func Load(opts Options) (*Config, error) {
+	file, err := readFile(opts.File)
+	if err != nil {
+		return nil, err
+	}
+	sources := []source{
+		fromMap(opts.Overrides, false),
+		fromEnv,
+		fromMap(file, true),
+	}
+
+	cfg := &Config{}
+	values := make(map[string]string)
+	verr := &ValidationError{}
+	for _, s := range settings(cfg) {
+		raw, ok, err := lookup(sources, s.name)
+		if err != nil {
+			verr.add("%s: %s", s.name, err)
+			continue
+		}
+		if s.required && raw == "" {
+			verr.add("%s is required", s.name)
+			continue
+		}
+		if ok {
+			values[s.name] = raw
+		}
+	}
+	if !verr.empty() {
+		return nil, verr
+	}
+
+	if err := readEnv(cfg, values); err != nil {
+		verr.add("%s", err)
+		return nil, verr
+	}
+	trimLists(cfg)
+
+	cfg.validate(verr)
+	if !verr.empty() {
+		return nil, verr
+	}
+	return cfg, nil
+}
+
+// readEnv fills cfg with cleanenv, exporting values to the process environment for
+// the duration of the call only. cleanenv's own file parsing is not used: it
+// exports .env files over the environment, while the environment must win.
+func readEnv(cfg *Config, values map[string]string) error {
+	envMu.Lock()
+	defer envMu.Unlock()
+
+	for name, value := range values {
+		prev, had := os.LookupEnv(name)
+		if err := os.Setenv(name, value); err != nil {
+			return errors.Wrapf(err, "failed to set %s", name)
+		}
+		defer func() {
+			if had {
+				_ = os.Setenv(name, prev)
+			} else {
+				_ = os.Unsetenv(name)
+			}
+		}()
+	}
+	return cleanenv.ReadEnv(cfg)
+}
+
+// trimLists drops the blanks cleanenv keeps in lists, so "a, b" is two origins and
+// an empty value is no list at all.
+func trimLists(cfg *Config) {
+	for _, s := range settings(cfg) {
+		list, ok := s.value.Interface().([]string)
+		if !ok {
+			continue
+		}
+		var items []string
+		for _, item := range list {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		s.value.Set(reflect.ValueOf(items))
+	}
+}
+
+func lookup(sources []source, name string) (string, bool, error) {
+	for _, src := range sources {
+		if raw, ok, err := src(name); err != nil || ok {
+			return raw, ok, err
+		}
+	}
+	return "", false, nil
+}
+
+func fromEnv(name string) (string, bool, error) {
+	if raw, ok := os.LookupEnv(name); ok {
+		return raw, true, nil
+	}
+	if path, ok := os.LookupEnv(name + fileSuffix); ok {
+		return readSecret(path)
+	}
+	return "", false, nil
+}
+
+func fromMap(values map[string]string, secretFiles bool) source {
+	return func(name string) (string, bool, error) {
+		if raw, ok := values[name]; ok {
+			return raw, true, nil
+		}
+		if path, ok := values[name+fileSuffix]; ok && secretFiles {
+			return readSecret(path)
+		}
+		return "", false, nil
+	}
+}
+
+func readSecret(path string) (string, bool, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, errors.Wrap(err, "failed to read secret file")
+	}
+	return strings.TrimRight(string(b), "\r\n"), true, nil
+}
+
+// readFile returns the settings of a config file keyed by setting name. Sections of
+// YAML and TOML files join their keys with underscores, so "cache: {ttl: 2s}" is
+// CACHE_TTL.
+func readFile(path string) (map[string]string, error) {
+	if path == "" {
+		if _, err := os.Stat(DefaultFile); os.IsNotExist(err) {
+			return nil, nil
+		}
+		path = DefaultFile
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read config file")
+	}
+
+	var tree map[string]any
+	switch ext := filepath.Ext(path); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &tree)
+	case ".toml":
+		err = toml.Unmarshal(b, &tree)
+	case ".env", "":
+		values, err := godotenv.UnmarshalBytes(b)
+		return values, errors.Wrapf(err, "failed to parse %s", path)
+	default:
+		return nil, errors.Errorf("unsupported config file %s, want .env, .yaml or .toml", path)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", path)
+	}
+
+	values := make(map[string]string)
+	flatten(values, "", tree)
+	return values, checkKnown(path, values)
+}
+
+func flatten(out map[string]string, prefix string, tree map[string]any) {
+	for key, v := range tree {
+		name := strings.ToUpper(key)
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+
+		switch v := v.(type) {
+		case map[string]any:
+			flatten(out, name, v)
+		case []any:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = scalar(item)
+			}
+			out[name] = strings.Join(items, ",")
+		default:
+			out[name] = scalar(v)
+		}
+	}
+}
+
+func scalar(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		// TOML dates arrive parsed, while the date settings are plain dates
+		if h, m, sec := v.Clock(); h == 0 && m == 0 && sec == 0 && v.Nanosecond() == 0 {
+			return v.Format(time.DateOnly)
+		}
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// checkKnown rejects keys that are not settings, which are most likely typos.
+func checkKnown(path string, values map[string]string) error {
+	known := make(map[string]bool)
+	for _, s := range settings(&Config{}) {
+		known[s.name] = true
+		known[s.name+fileSuffix] = true
+	}
+
+	var unknown []string
+	for name := range values {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+		return errors.Errorf("unknown settings in %s: %s", path, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// String formats the field so that Load parses it back.
+func (s setting) String() string {
+	switch v := s.value.Interface().(type) {
+	case time.Time:
+		return v.Format(s.layout)
+	case []string:
+		return strings.Join(v, s.separator())
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (s setting) separator() string {
+	if s.sep == "" {
+		return ","
+	}
+	return s.sep
+}
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testYAML = `
+postgres:
+  user: orders_user
+  password: orders_pass
+  host: localhost
+  port: 5432
+  db: orders_db
+app:
+  port: 8080
+  address: 0.0.0.0
+  log:
+    level: info
+cache:
+  ttl: 2m
+  cleanup_interval: 1m
+kafka:
+  brokers: [kafka-1:9092, kafka-2:9092]
+  topic: order-events
+  group_id: orders
+migrate_path: database/migrations
+api:
+  v1_deprecated_at: 2026-11-01
+`
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_Layers(t *testing.T) {
+	path := writeFile(t, "config.yaml", testYAML)
+	t.Setenv("CACHE_TTL", "5m")
+	t.Setenv("APP_PORT", "8081")
+
+	cfg, err := Load(Options{File: path, Overrides: map[string]string{"APP_PORT": "9000"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "localhost", cfg.Postgres.Host, "file")
+	assert.Equal(t, 5*time.Minute, cfg.Cache.TTL, "env wins over the file")
+	assert.Equal(t, "9000", cfg.App.Port, "overrides win over env")
+	assert.Equal(t, 100, cfg.Kafka.BatchSize, "default")
+	assert.Equal(t, []string{"kafka-1:9092", "kafka-2:9092"}, cfg.Kafka.KafkaBrokers)
+	assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), cfg.API.V1DeprecatedAt)
+}
+
+func TestLoad_EnvWinsOverEnvFile(t *testing.T) {
+	path := writeFile(t, ".env", "POSTGRES_USER=u\nPOSTGRES_PASSWORD=p\nPOSTGRES_HOST=db\nPOSTGRES_PORT=5432\nPOSTGRES_DB=orders\n"+
+		"APP_PORT=8080\nAPP_ADDRESS=0.0.0.0\nAPP_LOG_LEVEL=info\nCACHE_TTL=1m\nCACHE_CLEANUP_INTERVAL=1m\n"+
+		"KAFKA_BROKERS=kafka:9092\nKAFKA_TOPIC=orders\nKAFKA_GROUP_ID=g\nMIGRATE_PATH=migrations\nCORS_ALLOWED_ORIGINS=https://a.example, https://b.example\n")
+	t.Setenv("CACHE_TTL", "5m")
+
+	cfg, err := Load(Options{File: path})
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, cfg.Cache.TTL)
+	assert.Equal(t, []string{"https://a.example", "https://b.example"}, cfg.Cors.AllowedOrigins)
+
+	assert.Equal(t, "5m", os.Getenv("CACHE_TTL"), "the environment is left as it was")
+	_, leaked := os.LookupEnv("POSTGRES_HOST")
+	assert.False(t, leaked, "file settings are not exported")
+}
+
+func TestLoad_Files(t *testing.T) {
+	secret := writeFile(t, "postgres_password", "s3cr3t\n")
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{
+		{
+			name: "env file with secret file",
+			file: ".env",
+			content: "POSTGRES_USER=u\nPOSTGRES_PASSWORD_FILE=" + secret + "\nPOSTGRES_HOST=db\nPOSTGRES_PORT=5432\nPOSTGRES_DB=orders\n" +
+				"APP_PORT=8080\nAPP_ADDRESS=0.0.0.0\nAPP_LOG_LEVEL=info\nCACHE_TTL=1m\nCACHE_CLEANUP_INTERVAL=1m\n" +
+				"KAFKA_BROKERS=kafka:9092\nKAFKA_TOPIC=orders\nKAFKA_GROUP_ID=g\nMIGRATE_PATH=migrations\n",
+		},
+		{
+			name: "toml",
+			file: "config.toml",
+			content: `migrate_path = "migrations"
+[postgres]
+user = "u"
+password_file = "` + secret + `"
+host = "db"
+port = "5432"
+db = "orders"
+[app]
+port = "8080"
+address = "0.0.0.0"
+log = { level = "info" }
+[cache]
+ttl = "1m"
+cleanup_interval = "1m"
+[kafka]
+brokers = ["kafka:9092"]
+topic = "orders"
+group_id = "g"
+`,
+		},
+		{
+			name:    "unknown key",
+			file:    "config.yaml",
+			content: testYAML + "cache_tll: 1m\n",
+			wantErr: "unknown settings in",
+		},
+		{
+			name:    "unsupported format",
+			file:    "config.json",
+			content: "{}",
+			wantErr: "unsupported config file",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := Load(Options{File: writeFile(t, tc.file, tc.content)})
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "s3cr3t", cfg.Postgres.Password)
+			assert.Equal(t, []string{"kafka:9092"}, cfg.GetKafkaBrokers())
+		})
+	}
+}
+
+func TestLoad_Validation(t *testing.T) {
+	path := writeFile(t, "config.yaml", testYAML)
+
+	tests := []struct {
+		name      string
+		overrides map[string]string
+		want      []string
+	}{
+		{
+			name:      "missing",
+			overrides: map[string]string{"POSTGRES_HOST": "", "APP_ADDRESS": ""},
+			want:      []string{"POSTGRES_HOST is required", "APP_ADDRESS is required"},
+		},
+		{
+			name:      "unparsable",
+			overrides: map[string]string{"KAFKA_WORKERS": "four"},
+			want:      []string{`parsing field Workers env KAFKA_WORKERS: strconv.ParseInt: parsing "four": invalid syntax`},
+		},
+		{
+			name: "semantic",
+			overrides: map[string]string{
+				"CACHE_CLEANUP_INTERVAL": "0s",
+				"KAFKA_BROKERS":          "kafka",
+				"APP_LOG_LEVEL":          "trace",
+				"API_V1_SUNSET":          "2026-01-01",
+			},
+			want: []string{
+				`KAFKA_BROKERS: invalid broker address "kafka", want host:port`,
+				`APP_LOG_LEVEL must be one of debug, info, warn, error, got "trace"`,
+				"CACHE_CLEANUP_INTERVAL must be positive",
+				"API_V1_SUNSET must be after API_V1_DEPRECATED_AT",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Load(Options{File: path, Overrides: tc.overrides})
+
+			var verr *ValidationError
+			require.ErrorAs(t, err, &verr)
+			assert.Equal(t, tc.want, verr.Problems)
+		})
+	}
+}
+
+func TestLoad_Required(t *testing.T) {
+	_, err := Load(Options{File: writeFile(t, ".env", "POSTGRES_USER=u\n")})
+
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Contains(t, verr.Problems, "POSTGRES_PASSWORD is required")
+	assert.NotContains(t, verr.Problems, "POSTGRES_USER is required")
+}
+
+func TestConfig_Print(t *testing.T) {
+	cfg, err := Load(Options{File: writeFile(t, "config.yaml", testYAML)})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, cfg.Print(&buf))
+
+	out := buf.String()
+	assert.Contains(t, out, "POSTGRES_PASSWORD=[REDACTED]\n")
+	assert.Contains(t, out, "ADMIN_TOKEN=\n", "an unset secret is shown as empty")
+	assert.Contains(t, out, "KAFKA_BROKERS=kafka-1:9092,kafka-2:9092\n")
+	assert.Contains(t, out, "API_V1_DEPRECATED_AT=2026-11-01\n")
+	assert.NotContains(t, out, "orders_pass")
+
+	// the printed settings load back to the same config
+	again, err := Load(Options{File: writeFile(t, ".env", out), Overrides: map[string]string{"POSTGRES_PASSWORD": "orders_pass"}})
+	require.NoError(t, err)
+	assert.Equal(t, cfg, again)
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// Print writes the settings in .env format, so the output can be used as a config
+// file. Secrets are replaced with a placeholder unless they are empty.
+func (c *Config) Print(w io.Writer) error {
+	for _, s := range settings(c) {
+		value := s.String()
+		if s.secret && value != "" {
+			value = redacted
+		}
+		if _, err := fmt.Fprintf(w, "%s=%s\n", s.name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flags registers -config and a flag per setting on fs, named after the setting in
+// lower case with dashes: -cache-ttl=5m sets CACHE_TTL. The returned options are
+// filled in by fs.Parse.
+func Flags(fs *flag.FlagSet) *Options {
+	opts := &Options{Overrides: make(map[string]string)}
+	fs.StringVar(&opts.File, "config", "", "config file: .env, .yaml or .toml (default "+DefaultFile+" if it exists)")
+
+	for _, s := range settings(&Config{}) {
+		usage := "sets " + s.name
+		if s.hasDef {
+			usage += fmt.Sprintf(" (default %q)", s.def)
+		}
+		fs.Func(flagName(s.name), usage, func(value string) error {
+			opts.Overrides[s.name] = value
+			return nil
+		})
+	}
+	return opts
+}
+
+func flagName(setting string) string {
+	return strings.ToLower(strings.ReplaceAll(setting, "_", "-"))
+}
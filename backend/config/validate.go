@@ -0,0 +1,119 @@
+package config
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	logLevels       = []string{"debug", "info", "warn", "error"}
+	logFormats      = []string{"json", "text"}
+	tracingExporter = []string{"none", "stdout", "otlp"}
+)
+
+// ValidationError lists every problem found in the settings.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid config: " + strings.Join(e.Problems, "; ")
+}
+
+func (e *ValidationError) add(format string, args ...any) {
+	e.Problems = append(e.Problems, fmt.Sprintf(format, args...))
+}
+
+func (e *ValidationError) empty() bool {
+	return len(e.Problems) == 0
+}
+
+// validate checks the values that parse but make no sense together or for the
+// service.
+func (c *Config) validate(e *ValidationError) {
+	port(e, "POSTGRES_PORT", c.Postgres.Port)
+	port(e, "APP_PORT", c.App.Port)
+	port(e, "GRPC_PORT", c.App.GRPCPort)
+	for _, broker := range c.Kafka.KafkaBrokers {
+		if _, p, err := net.SplitHostPort(broker); err != nil || !validPort(p) {
+			e.add("KAFKA_BROKERS: invalid broker address %q, want host:port", broker)
+		}
+	}
+
+	oneOf(e, "APP_LOG_LEVEL", c.Log.Level, logLevels)
+	oneOf(e, "APP_LOG_FORMAT", c.Log.Format, logFormats)
+	oneOf(e, "TRACING_EXPORTER", c.Tracing.Exporter, tracingExporter)
+	if c.Log.SampleInfo < 0 {
+		e.add("APP_LOG_SAMPLE_INFO must not be negative")
+	}
+	if r := c.Tracing.SampleRatio; r < 0 || r > 1 {
+		e.add("TRACING_SAMPLE_RATIO must be between 0 and 1")
+	}
+
+	positive(e, "HTTP_HANDLER_TIMEOUT", int64(c.HTTP.HandlerTimeout))
+	positive(e, "HTTP_EXPORT_TIMEOUT", int64(c.HTTP.ExportTimeout))
+	positive(e, "HTTP_READY_CHECK_TIMEOUT", int64(c.HTTP.ReadyCheckTimeout))
+	positive(e, "CACHE_TTL", int64(c.Cache.TTL))
+	positive(e, "CACHE_CLEANUP_INTERVAL", int64(c.Cache.CleanupInterval))
+	positive(e, "KAFKA_BATCH_SIZE", int64(c.Kafka.BatchSize))
+	positive(e, "KAFKA_BATCH_TIMEOUT", int64(c.Kafka.BatchTimeout))
+	positive(e, "KAFKA_WORKERS", int64(c.Kafka.Workers))
+	positive(e, "OUTBOX_POLL_INTERVAL", int64(c.Outbox.PollInterval))
+	positive(e, "OUTBOX_BATCH_SIZE", int64(c.Outbox.BatchSize))
+	positive(e, "OUTBOX_LEASE", int64(c.Outbox.Lease))
+	positive(e, "WEBHOOK_POLL_INTERVAL", int64(c.Webhook.PollInterval))
+	positive(e, "WEBHOOK_BATCH_SIZE", int64(c.Webhook.BatchSize))
+	positive(e, "WEBHOOK_TIMEOUT", int64(c.Webhook.Timeout))
+	positive(e, "WEBHOOK_MAX_ATTEMPTS", int64(c.Webhook.MaxAttempts))
+	positive(e, "WEBHOOK_BACKOFF", int64(c.Webhook.Backoff))
+	positive(e, "FEED_BUFFER_SIZE", int64(c.Feed.BufferSize))
+	positive(e, "FEED_HEARTBEAT", int64(c.Feed.Heartbeat))
+	positive(e, "WS_SEND_BUFFER", int64(c.WS.SendBuffer))
+	positive(e, "WS_MAX_SUBSCRIPTIONS", int64(c.WS.MaxSubscriptions))
+	positive(e, "WS_PING_INTERVAL", int64(c.WS.PingInterval))
+	if key := c.Webhook.SecretKey; key != "" {
+		if b, err := hex.DecodeString(key); err != nil || len(b) != 32 {
+			e.add("WEBHOOK_SECRET_KEY must be 32 hex encoded bytes")
+		}
+	}
+	if c.Webhook.MaxBackoff < c.Webhook.Backoff {
+		e.add("WEBHOOK_MAX_BACKOFF must not be less than WEBHOOK_BACKOFF")
+	}
+
+	if c.RateLimit.RPS < 0 {
+		e.add("RATE_LIMIT_RPS must not be negative")
+	}
+	if c.RateLimit.RPS > 0 {
+		positive(e, "RATE_LIMIT_BURST", int64(c.RateLimit.Burst))
+	}
+	if !c.API.V1Sunset.After(c.API.V1DeprecatedAt) {
+		e.add("API_V1_SUNSET must be after API_V1_DEPRECATED_AT")
+	}
+}
+
+func port(e *ValidationError, name, value string) {
+	if !validPort(value) {
+		e.add("%s: invalid port %q", name, value)
+	}
+}
+
+func validPort(value string) bool {
+	n, err := strconv.Atoi(value)
+	return err == nil && n > 0 && n < 65536
+}
+
+func oneOf(e *ValidationError, name, value string, allowed []string) {
+	if !slices.Contains(allowed, value) {
+		e.add("%s must be one of %s, got %q", name, strings.Join(allowed, ", "), value)
+	}
+}
+
+func positive(e *ValidationError, name string, value int64) {
+	if value <= 0 {
+		e.add("%s must be positive", name)
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"os"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
@@ -55,3 +57,28 @@ func RunMigrations(dsn string, migrationsPath string, logger *slog.Logger) error
 
 	return nil
 }
+
+// LatestVersion returns the newest migration version found in migrationsPath, the
+// version the schema is at after RunMigrations.
+func LatestVersion(migrationsPath string) (uint, error) {
+	src, err := source.Open("file://" + migrationsPath)
+	if err != nil {
+		return 0, fmt.Errorf("source.Open: %w", err)
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if err != nil {
+		return 0, fmt.Errorf("first migration: %w", err)
+	}
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, os.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("next migration: %w", err)
+		}
+		version = next
+	}
+}
@@ -0,0 +1,22 @@
+package migrate
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestVersion(t *testing.T) {
+	// migrations are numbered 1, 2, ... without gaps
+	ups, err := filepath.Glob("migrations/*.up.sql")
+	require.NoError(t, err)
+
+	version, err := LatestVersion("migrations")
+	require.NoError(t, err)
+	assert.Equal(t, uint(len(ups)), version)
+
+	_, err = LatestVersion(t.TempDir())
+	assert.Error(t, err)
+}
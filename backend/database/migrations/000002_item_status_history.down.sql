@@ -0,0 +1,5 @@
+-- +migrate Down
+DROP INDEX IF EXISTS idx_items_order_uid_rid;
+DROP INDEX IF EXISTS idx_item_status_history_order_uid;
+
+DROP TABLE IF EXISTS item_status_history;
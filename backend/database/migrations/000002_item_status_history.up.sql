@@ -0,0 +1,15 @@
+CREATE TABLE item_status_history (
+  id          BIGSERIAL PRIMARY KEY,
+  order_uid   TEXT REFERENCES orders(order_uid) ON DELETE CASCADE,
+  rid         TEXT NOT NULL,
+  status      INTEGER NOT NULL,
+  changed_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_item_status_history_order_uid ON item_status_history(order_uid);
+CREATE INDEX idx_items_order_uid_rid ON items(order_uid, rid);
+
+INSERT INTO item_status_history (order_uid, rid, status, changed_at)
+SELECT i.order_uid, i.rid, i.status, COALESCE(o.date_created, o.created_at, now())
+FROM items i
+JOIN orders o ON o.order_uid = i.order_uid;
@@ -0,0 +1,3 @@
+-- +migrate Down
+DROP INDEX IF EXISTS idx_orders_customer_id_date_created;
+DROP INDEX IF EXISTS idx_orders_track_number;
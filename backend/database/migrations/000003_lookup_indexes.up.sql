@@ -0,0 +1,2 @@
+CREATE INDEX idx_orders_track_number ON orders(track_number);
+CREATE INDEX idx_orders_customer_id_date_created ON orders(customer_id, date_created DESC);
@@ -0,0 +1 @@
+CREATE INDEX idx_orders_date_created ON orders(date_created);
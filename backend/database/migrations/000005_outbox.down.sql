@@ -0,0 +1,4 @@
+-- +migrate Down
+DROP INDEX IF EXISTS idx_outbox_unpublished;
+
+DROP TABLE IF EXISTS outbox;
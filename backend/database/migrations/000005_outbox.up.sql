@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS outbox (
+  id            BIGSERIAL PRIMARY KEY,
+  event_id      TEXT NOT NULL UNIQUE,
+  event_type    TEXT NOT NULL,
+  aggregate_id  TEXT NOT NULL,
+  payload       JSONB NOT NULL,
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+  published_at  TIMESTAMPTZ,
+  attempts      INTEGER NOT NULL DEFAULT 0,
+  last_error    TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished ON outbox(id) WHERE published_at IS NULL;
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS webhooks (
+  id                    BIGSERIAL PRIMARY KEY,
+  url                   TEXT NOT NULL,
+  secret                TEXT NOT NULL,
+  event_types           TEXT[] NOT NULL DEFAULT '{}',
+  enabled               BOOLEAN NOT NULL DEFAULT true,
+  consecutive_failures  INTEGER NOT NULL DEFAULT 0,
+  disabled_at           TIMESTAMPTZ,
+  created_at            TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id                BIGSERIAL PRIMARY KEY,
+  webhook_id        BIGINT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+  event_id          TEXT NOT NULL,
+  event_type        TEXT NOT NULL,
+  payload           JSONB NOT NULL,
+  status            TEXT NOT NULL DEFAULT 'pending',
+  attempts          INTEGER NOT NULL DEFAULT 0,
+  last_status_code  INTEGER,
+  last_error        TEXT,
+  next_attempt_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+  created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+  delivered_at      TIMESTAMPTZ,
+  UNIQUE (webhook_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, id DESC);
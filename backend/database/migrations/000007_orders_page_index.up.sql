@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_orders_date_created_order_uid ON orders(date_created DESC, order_uid DESC);
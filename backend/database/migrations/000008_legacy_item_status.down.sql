@@ -0,0 +1,3 @@
+-- +migrate Down
+-- The original codes are not kept, so the mapping cannot be undone.
+SELECT 1;
@@ -0,0 +1,5 @@
+-- Before statuses were an enum, items kept the raw code sent by the producer,
+-- 202 in practice. Those items are treated as just created; any other code
+-- outside the enum has no known meaning and is mapped the same way.
+UPDATE items SET status = 1 WHERE status NOT BETWEEN 1 AND 7;
+UPDATE item_status_history SET status = 1 WHERE status NOT BETWEEN 1 AND 7;
@@ -0,0 +1,2 @@
+-- +migrate Down
+ALTER TABLE outbox DROP COLUMN IF EXISTS locked_until;
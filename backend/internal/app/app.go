@@ -8,26 +8,55 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/GkadyrG/L0/backend/config"
 	migrate "github.com/GkadyrG/L0/backend/database"
 	"github.com/GkadyrG/L0/backend/internal/cache"
+	"github.com/GkadyrG/L0/backend/internal/feed"
 	order "github.com/GkadyrG/L0/backend/internal/handler"
 	"github.com/GkadyrG/L0/backend/internal/kafka/consumer"
 	"github.com/GkadyrG/L0/backend/internal/logger"
+	"github.com/GkadyrG/L0/backend/internal/metrics"
+	"github.com/GkadyrG/L0/backend/internal/middleware"
+	"github.com/GkadyrG/L0/backend/internal/origin"
+	"github.com/GkadyrG/L0/backend/internal/outbox"
 	"github.com/GkadyrG/L0/backend/internal/repository"
+	"github.com/GkadyrG/L0/backend/internal/rpc"
+	"github.com/GkadyrG/L0/backend/internal/secret"
 	"github.com/GkadyrG/L0/backend/internal/server"
 	"github.com/GkadyrG/L0/backend/internal/storage"
+	"github.com/GkadyrG/L0/backend/internal/tracing"
 	"github.com/GkadyrG/L0/backend/internal/usecase"
+	"github.com/GkadyrG/L0/backend/internal/webhook"
+	"github.com/GkadyrG/L0/backend/internal/ws"
 )
 
-func Run() error {
+// Run starts the service with the config built from opts and blocks until it is
+// stopped by SIGINT or SIGTERM.
+func Run(opts config.Options) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	cfg := config.LoadConfig()
+	cfg, err := config.Load(opts)
+	if err != nil {
+		return err
+	}
 
-	logger := logger.SetupLogger(cfg.App.LogLevel)
+	logger := logger.SetupLogger(cfg.Log)
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
+	if err != nil {
+		logger.Error("tracing.Setup", slog.Any("err", err))
+		return err
+	}
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			logger.Error("failed to flush spans", slog.Any("err", err))
+		}
+	}()
 
 	if err := migrate.RunMigrations(cfg.GetDSN(), cfg.MigratePath, logger); err != nil {
 		logger.Error("migrate", slog.Any("err", err))
@@ -40,29 +69,94 @@ func Run() error {
 		return err
 	}
 
-	repo := repository.New(conn)
-	cacheDecorator, err := cache.New(ctx, cfg, repo)
+	metrics.WatchPool(conn)
+
+	repo := repository.New(conn, cfg.Postgres.StatementTimeout)
+	hub := feed.NewHub(cfg.Feed.BufferSize)
+	orderRepo := metrics.NewOrderRepository(repo)
+	liveHub := ws.NewHub(cfg, orderRepo, logger)
+	cacheDecorator, err := cache.New(ctx, cfg, orderRepo, hub, liveHub)
 	if err != nil {
 		logger.Error("cache.New", slog.Any("err", err))
 		return err
 	}
+	metrics.WatchCache(cacheDecorator.Len)
 
-	uc := usecase.New(cacheDecorator)
+	uc := metrics.NewOrderProvider(usecase.New(cacheDecorator))
 	handler := order.New(uc, logger)
-	router := GetRouter(cfg, handler)
 
-	srv := &http.Server{
-		Addr:    fmt.Sprintf("%s:%s", cfg.App.Address, cfg.App.Port),
-		Handler: router,
+	analytics := usecase.NewAnalytics(cache.NewAnalytics(cfg, metrics.NewAnalyticsRepository(repo)))
+	analyticsHandler := order.NewAnalytics(analytics, logger)
+
+	publisher, err := outbox.NewKafkaPublisher(cfg.GetKafkaBrokers(), cfg.Outbox.Topic)
+	if err != nil {
+		logger.Error("outbox.NewKafkaPublisher", slog.Any("err", err))
+		return err
 	}
+	defer publisher.Close()
+
+	relay := outbox.NewRelay(cfg, metrics.NewOutboxRepository(repo), publisher, logger)
+	go relay.Run(ctx)
 
-	cons, err := consumer.NewConsumer(cfg.GetKafkaBrokers(), cfg.Kafka.KafkaGroupID, uc, logger)
+	secrets, err := secret.NewBox(cfg.Webhook.SecretKey)
+	if err != nil {
+		logger.Error("secret.NewBox", slog.Any("err", err))
+		return err
+	}
+
+	webhookRepo := metrics.NewWebhookRepository(repo)
+	dispatcher := webhook.NewDispatcher(cfg, webhookRepo, secrets, logger)
+	go dispatcher.Run(ctx)
+
+	adminHandler := order.NewAdmin(relay, usecase.NewWebhooks(webhookRepo, secrets), logger)
+
+	feedHandler := order.NewFeed(hub, cfg.Feed.Heartbeat, logger)
+
+	origins := origin.NewAllowlist(cfg.Cors.AllowedOrigins)
+	limiter := middleware.NewRateLimiter(cfg.RateLimit.RPS, cfg.RateLimit.Burst)
+
+	liveHandler := order.NewLive(cfg, origins, liveHub, logger)
+
+	query := usecase.NewQuery(metrics.NewQueryRepository(repo))
+	v2Handler := order.NewV2(uc, query, logger)
+	graphQLHandler := order.NewGraphQL(query, logger)
+
+	docsHandler, err := order.NewDocs()
+	if err != nil {
+		logger.Error("order.NewDocs", slog.Any("err", err))
+		return err
+	}
+
+	cons, err := consumer.NewConsumer(cfg, uc, logger)
 	if err != nil {
 		logger.Error("consumer.NewConsumer", slog.Any("err", err))
 		return err
 	}
 
-	server := server.NewServer(srv, cons, logger)
+	checker, err := newHealthChecker(cfg, repo, cons)
+	if err != nil {
+		logger.Error("newHealthChecker", slog.Any("err", err))
+		return err
+	}
+	healthHandler := order.NewHealth(checker)
+
+	router := GetRouter(cfg, logger, origins, limiter, handler, v2Handler, analyticsHandler, adminHandler, feedHandler, liveHandler, graphQLHandler, docsHandler, healthHandler)
+
+	srv := &http.Server{
+		Addr:              fmt.Sprintf("%s:%s", cfg.App.Address, cfg.App.Port),
+		Handler:           router,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+	}
+	// streams never finish on their own, end them so Shutdown does not wait for its timeout
+	srv.RegisterOnShutdown(hub.Close)
+
+	grpcSrv := rpc.NewServer(rpc.New(uc, query, hub, logger))
+	grpcAddr := fmt.Sprintf("%s:%s", cfg.App.Address, cfg.App.GRPCPort)
+
+	server := server.NewServer(srv, grpcSrv, grpcAddr, cons, liveHub, checker, cfg.HTTP.DrainDelay, logger)
 
 	go func() {
 		if err := RunEmulator(ctx, cfg, logger, EmulatorOptions{Num: cfg.EmulatorMessages}); err != nil {
@@ -70,6 +164,10 @@ func Run() error {
 		}
 	}()
 
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go newReloader(opts, cfg, cacheDecorator, origins, limiter, logger).Watch(ctx, reload)
+
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, syscall.SIGINT, syscall.SIGTERM)
 
@@ -10,9 +10,14 @@ import (
 	"time"
 
 	"github.com/GkadyrG/L0/backend/config"
+	"github.com/GkadyrG/L0/backend/internal/metrics"
 	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/tracing"
 	"github.com/IBM/sarama"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type EmulatorOptions struct {
@@ -38,7 +43,9 @@ func RunEmulator(ctx context.Context, cfg *config.Config, log *slog.Logger, opts
 	for i := 0; i < opts.Num; i++ {
 		order := randomizeOrder(*baseOrder, rnd, i)
 
-		if err := sendOrder(producer, cfg.Kafka.KafkaTopic, &order, log); err != nil {
+		err := sendOrder(ctx, producer, cfg.Kafka.KafkaTopic, &order, log)
+		metrics.EmulatorSent(err)
+		if err != nil {
 			return errors.Wrap(err, "send order")
 		}
 
@@ -64,7 +71,16 @@ func newSyncProducer(brokers []string) (sarama.SyncProducer, error) {
 	return sarama.NewSyncProducer(brokers, cfg)
 }
 
-func sendOrder(p sarama.SyncProducer, topic string, order *model.Order, log *slog.Logger) error {
+// sendOrder starts the trace of an order: the consumer continues it from the
+// message headers.
+func sendOrder(ctx context.Context, p sarama.SyncProducer, topic string, order *model.Order, log *slog.Logger) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "send "+topic, trace.WithSpanKind(trace.SpanKindProducer), trace.WithAttributes(
+		semconv.MessagingSystemKafka,
+		semconv.MessagingDestinationName(topic),
+		attribute.String("order.uid", order.OrderUID),
+	))
+	defer func() { tracing.End(span, err) }()
+
 	b, err := json.Marshal(order)
 	if err != nil {
 		return errors.Wrap(err, "marshal order")
@@ -74,6 +90,7 @@ func sendOrder(p sarama.SyncProducer, topic string, order *model.Order, log *slo
 		Key:   sarama.StringEncoder(order.OrderUID),
 		Value: sarama.ByteEncoder(b),
 	}
+	tracing.Inject(ctx, msg)
 	part, off, err := p.SendMessage(msg)
 	if err != nil {
 		return errors.Wrap(err, "send")
@@ -144,7 +161,7 @@ func defaultOrder() *model.Order {
 				TotalPrice:  317,
 				NmID:        2389212,
 				Brand:       "Vivienne Sabo",
-				Status:      202,
+				Status:      model.ItemStatusCreated,
 			},
 		},
 	}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GkadyrG/L0/backend/config"
+	migrate "github.com/GkadyrG/L0/backend/database"
+	"github.com/GkadyrG/L0/backend/internal/health"
+	"github.com/GkadyrG/L0/backend/internal/kafka/consumer"
+	"github.com/GkadyrG/L0/backend/internal/repository"
+)
+
+// newHealthChecker registers the dependencies /readyz reports on. A rebalancing
+// consumer group only delays new orders, and a schema at another version is what a
+// rolling deploy looks like once the newer replica has migrated, so neither takes
+// the API out of rotation: both only degrade the report.
+func newHealthChecker(cfg *config.Config, repo *repository.Repo, cons *consumer.Consumer) (*health.Checker, error) {
+	want, err := migrate.LatestVersion(cfg.MigratePath)
+	if err != nil {
+		return nil, err
+	}
+
+	checker := health.New(cfg.HTTP.ReadyCheckTimeout)
+	checker.Add("postgres", repo.Ping)
+	checker.Add("migrations", func(ctx context.Context) error {
+		version, dirty, err := repo.MigrationVersion(ctx)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("schema is dirty at version %d", version)
+		}
+		if version != want {
+			return health.Degraded(fmt.Errorf("schema is at version %d, want %d", version, want))
+		}
+		return nil
+	})
+	checker.Add("kafka", func(ctx context.Context) error {
+		if err := cons.Ready(ctx); err != nil {
+			return health.Degraded(err)
+		}
+		return nil
+	})
+	return checker, nil
+}
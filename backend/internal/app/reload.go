@@ -0,0 +1,129 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/config"
+	"github.com/GkadyrG/L0/backend/internal/logger"
+	"github.com/GkadyrG/L0/backend/internal/metrics"
+	"github.com/GkadyrG/L0/backend/internal/middleware"
+	"github.com/GkadyrG/L0/backend/internal/origin"
+	"github.com/pkg/errors"
+)
+
+type ttlSetter interface {
+	SetTTL(ttl time.Duration)
+}
+
+// reloader rebuilds the config from its sources on SIGHUP and applies the settings
+// that can change while the service runs: the log level, the cache TTL, the CORS
+// origins and the rate limit. Other settings only take effect after a restart.
+type reloader struct {
+	opts    config.Options
+	cfg     config.Config
+	cache   ttlSetter
+	origins *origin.Allowlist
+	limiter *middleware.RateLimiter
+	logger  *slog.Logger
+}
+
+func newReloader(opts config.Options, cfg *config.Config, cache ttlSetter, origins *origin.Allowlist, limiter *middleware.RateLimiter, logger *slog.Logger) *reloader {
+	return &reloader{opts: opts, cfg: *cfg, cache: cache, origins: origins, limiter: limiter, logger: logger}
+}
+
+// Watch reloads the config on every signal from sig until ctx is done.
+func (r *reloader) Watch(ctx context.Context, sig <-chan os.Signal) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sig:
+			changed, err := r.Reload()
+			if err != nil {
+				r.logger.Error("config reload failed", slog.Any("err", err))
+				continue
+			}
+			r.logger.Info("config reloaded", "changed", changed)
+		}
+	}
+}
+
+// Reload applies the hot-reloadable settings and returns the names of those that
+// changed. A config that fails validation is not applied at all.
+func (r *reloader) Reload() ([]string, error) {
+	next, err := config.Load(r.opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load config")
+	}
+	return r.apply(next), nil
+}
+
+func (r *reloader) apply(next *config.Config) []string {
+	var changed []string
+	set := func(name string, from, to any, apply func() error) {
+		if err := apply(); err != nil {
+			r.logger.Error("setting not changed", "setting", name, slog.Any("err", err))
+			return
+		}
+		r.logger.Info("setting changed", "setting", name, "from", from, "to", to)
+		metrics.ConfigReloaded(name)
+		changed = append(changed, name)
+	}
+
+	// The level may also have been changed through the admin API; a reload only
+	// overrides it when the configured level itself changed.
+	if next.Log.Level != r.cfg.Log.Level {
+		set("APP_LOG_LEVEL", logger.Level(), next.Log.Level, func() error {
+			if err := logger.SetLevel(next.Log.Level); err != nil {
+				return err
+			}
+			r.cfg.Log.Level = next.Log.Level
+			return nil
+		})
+	}
+
+	if next.Cache.TTL != r.cfg.Cache.TTL {
+		set("CACHE_TTL", r.cfg.Cache.TTL, next.Cache.TTL, func() error {
+			if next.Cache.TTL <= 0 {
+				return errors.New("CACHE_TTL must be positive")
+			}
+			r.cache.SetTTL(next.Cache.TTL)
+			r.cfg.Cache.TTL = next.Cache.TTL
+			return nil
+		})
+	}
+
+	if !slices.Equal(next.Cors.AllowedOrigins, r.cfg.Cors.AllowedOrigins) {
+		set("CORS_ALLOWED_ORIGINS", r.cfg.Cors.AllowedOrigins, next.Cors.AllowedOrigins, func() error {
+			// Without CORS the allowlist is never consulted, and enabling it
+			// needs a restart.
+			if !r.cfg.Cors.Enabled {
+				return errors.New("CORS is disabled, restart with CORS_ENABLED=true")
+			}
+			r.origins.Set(next.Cors.AllowedOrigins)
+			r.cfg.Cors.AllowedOrigins = next.Cors.AllowedOrigins
+			return nil
+		})
+	}
+
+	if next.RateLimit.RPS != r.cfg.RateLimit.RPS {
+		set("RATE_LIMIT_RPS", r.cfg.RateLimit.RPS, next.RateLimit.RPS, func() error {
+			r.cfg.RateLimit.RPS = next.RateLimit.RPS
+			r.limiter.SetLimit(r.cfg.RateLimit.RPS, r.cfg.RateLimit.Burst)
+			return nil
+		})
+	}
+	if next.RateLimit.Burst != r.cfg.RateLimit.Burst {
+		set("RATE_LIMIT_BURST", r.cfg.RateLimit.Burst, next.RateLimit.Burst, func() error {
+			r.cfg.RateLimit.Burst = next.RateLimit.Burst
+			r.limiter.SetLimit(r.cfg.RateLimit.RPS, r.cfg.RateLimit.Burst)
+			return nil
+		})
+	}
+
+	return changed
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/config"
+	"github.com/GkadyrG/L0/backend/internal/logger"
+	"github.com/GkadyrG/L0/backend/internal/middleware"
+	"github.com/GkadyrG/L0/backend/internal/origin"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubCache struct {
+	ttl time.Duration
+}
+
+func (c *stubCache) SetTTL(ttl time.Duration) { c.ttl = ttl }
+
+func TestReloader_Apply(t *testing.T) {
+	prev := logger.Level()
+	t.Cleanup(func() { _ = logger.SetLevel(prev) })
+	_ = logger.SetLevel("info")
+
+	cfg := &config.Config{
+		Log:       config.LogConfig{Level: "info"},
+		Cache:     config.CacheConfig{TTL: time.Minute, CleanupInterval: time.Second},
+		Cors:      config.CorsConfig{Enabled: true, AllowedOrigins: []string{"https://shop.example"}},
+		RateLimit: config.RateLimitConfig{RPS: 10, Burst: 20},
+	}
+	cache := &stubCache{ttl: cfg.Cache.TTL}
+	origins := origin.NewAllowlist(cfg.Cors.AllowedOrigins)
+	r := newReloader(config.Options{}, cfg, cache, origins, middleware.NewRateLimiter(10, 20), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	next := *cfg
+	next.Cache.CleanupInterval = time.Hour // needs a restart
+	assert.Empty(t, r.apply(&next))
+
+	next.Log.Level = "debug"
+	next.Cache.TTL = 5 * time.Minute
+	next.Cors.AllowedOrigins = []string{"https://shop.example", "https://admin.example"}
+	next.RateLimit.Burst = 50
+	assert.Equal(t, []string{"APP_LOG_LEVEL", "CACHE_TTL", "CORS_ALLOWED_ORIGINS", "RATE_LIMIT_BURST"}, r.apply(&next))
+
+	assert.Equal(t, "debug", logger.Level())
+	assert.Equal(t, 5*time.Minute, cache.ttl)
+	assert.True(t, origins.Allowed("https://admin.example"))
+	assert.Empty(t, r.apply(&next), "applied settings are not reported again")
+
+	next.Log.Level = "verbose"
+	next.Cache.TTL = 0
+	assert.Empty(t, r.apply(&next), "invalid values are skipped")
+	assert.Equal(t, "debug", logger.Level())
+	assert.Equal(t, 5*time.Minute, cache.ttl)
+
+	_ = logger.SetLevel("warn") // through the admin API
+	next.Log.Level = "debug"
+	next.Cache.TTL = 5 * time.Minute
+	assert.Empty(t, r.apply(&next), "an unchanged file keeps the level set at runtime")
+	assert.Equal(t, "warn", logger.Level())
+}
+
+func TestReloader_ApplyCorsDisabled(t *testing.T) {
+	cfg := &config.Config{Cors: config.CorsConfig{AllowedOrigins: []string{"https://shop.example"}}}
+	origins := origin.NewAllowlist(cfg.Cors.AllowedOrigins)
+	r := newReloader(config.Options{}, cfg, &stubCache{}, origins, middleware.NewRateLimiter(10, 20), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	next := *cfg
+	next.Cors.AllowedOrigins = []string{"https://admin.example"}
+	assert.Empty(t, r.apply(&next))
+	assert.False(t, origins.Allowed("https://admin.example"))
+}
@@ -1,17 +1,80 @@
 package app
 
 import (
+	"log/slog"
+	"net/http"
+
 	"github.com/GkadyrG/L0/backend/config"
 	order "github.com/GkadyrG/L0/backend/internal/handler"
+	"github.com/GkadyrG/L0/backend/internal/metrics"
 	"github.com/GkadyrG/L0/backend/internal/middleware"
+	"github.com/GkadyrG/L0/backend/internal/origin"
 	"github.com/go-chi/chi/v5"
 )
 
-func GetRouter(cfg *config.Config, h *order.Handler) *chi.Mux {
+func GetRouter(cfg *config.Config, logger *slog.Logger, origins *origin.Allowlist, limiter *middleware.RateLimiter, h *order.Handler, v2 *order.V2Handler, ah *order.AnalyticsHandler, admin *order.AdminHandler, fh *order.FeedHandler, lh *order.LiveHandler, gh *order.GraphQLHandler, dh *order.DocsHandler, hh *order.HealthHandler) *chi.Mux {
 	router := chi.NewRouter()
-	router.Use(middleware.CORS(cfg))
-	router.Get("/api/order/{id}", h.GetByID())
-	router.Get("/api/orders", h.GetAll())
+	router.Use(middleware.Tracing)
+	router.Use(middleware.RequestID)
+	router.Use(middleware.Metrics)
+	router.Use(middleware.CORS(cfg, origins))
+
+	router.Get("/healthz", hh.Live())
+	router.Get("/readyz", hh.Ready())
+	router.Method(http.MethodGet, "/metrics", metrics.Handler())
+
+	// Streams and WebSockets run for as long as the client stays connected.
+	router.Get("/api/orders/stream", fh.Stream())
+	router.Get("/api/orders/ws", lh.Subscribe())
+	router.With(limiter.Handler, middleware.Timeout(cfg.HTTP.ExportTimeout, logger)).Get("/api/orders/export", h.Export())
+
+	router.Group(func(r chi.Router) {
+		r.Use(limiter.Handler)
+		r.Use(middleware.Timeout(cfg.HTTP.HandlerTimeout, logger))
+
+		// v1 is also served at the unversioned paths used by the frontend.
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Deprecated(cfg))
+			v1Routes(r, "/api", h)
+			v1Routes(r, "/api/v1", h)
+		})
+		r.Route("/api/v2", func(r chi.Router) {
+			r.Get("/orders", v2.List())
+			r.Get("/orders/{id}", v2.GetByID())
+			r.Get("/orders/{id}/timeline", v2.GetTimeline())
+			r.Get("/orders/by-track/{track}", v2.GetByTrackNumber())
+			r.Get("/customers/{id}/orders", v2.ListByCustomer())
+		})
+
+		// Like the export and the streams, these are not versioned on purpose.
+		r.Get("/api/analytics/orders", ah.OrderStats())
+		r.Get("/api/analytics/basket", ah.BasketStats())
+		r.Get("/api/reports/top", ah.TopReport())
+		r.Post("/api/graphql", gh.Query())
+		r.Get("/api/openapi.json", dh.Spec())
+		r.Get("/api/docs", dh.Page())
+
+		r.Route("/api/admin", func(r chi.Router) {
+			r.Use(middleware.AdminAuth(cfg))
+			r.Get("/outbox", admin.OutboxState())
+			r.Get("/log-level", admin.LogLevel())
+			r.Put("/log-level", admin.SetLogLevel())
+			r.Post("/webhooks", admin.CreateWebhook())
+			r.Get("/webhooks", admin.ListWebhooks())
+			r.Get("/webhooks/{id}", admin.GetWebhook())
+			r.Patch("/webhooks/{id}", admin.UpdateWebhook())
+			r.Delete("/webhooks/{id}", admin.DeleteWebhook())
+			r.Get("/webhooks/{id}/deliveries", admin.ListDeliveries())
+		})
+	})
 
 	return router
 }
+
+func v1Routes(r chi.Router, prefix string, h *order.Handler) {
+	r.Get(prefix+"/order/{id}", h.GetByID())
+	r.Get(prefix+"/order/{id}/timeline", h.GetTimeline())
+	r.Get(prefix+"/orders", h.GetAll())
+	r.Get(prefix+"/orders/by-track/{track}", h.GetByTrackNumber())
+	r.Get(prefix+"/customers/{id}/orders", h.ListByCustomer())
+}
@@ -0,0 +1,316 @@
+package app
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/api"
+	"github.com/GkadyrG/L0/backend/config"
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/feed"
+	order "github.com/GkadyrG/L0/backend/internal/handler"
+	"github.com/GkadyrG/L0/backend/internal/health"
+	"github.com/GkadyrG/L0/backend/internal/middleware"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/origin"
+	"github.com/GkadyrG/L0/backend/internal/problem"
+	"github.com/GkadyrG/L0/backend/internal/repository/mocks"
+	"github.com/GkadyrG/L0/backend/internal/usecase"
+	"github.com/GkadyrG/L0/backend/internal/ws"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/go-chi/chi/v5"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testAdminToken = "secret"
+
+type stubOutbox struct{}
+
+func (stubOutbox) State(context.Context) (*model.OutboxState, error) {
+	return &model.OutboxState{Topic: "order-stored-events", Relay: model.RelayStats{Running: true, Published: 3}}, nil
+}
+
+// newTestRouter wires GetRouter to repository mocks that answer every call made by
+// the conformance cases.
+func newTestRouter(t *testing.T) *chi.Mux {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{
+		App:  config.AppConfig{AdminToken: testAdminToken},
+		HTTP: config.HTTPConfig{HandlerTimeout: time.Second, ExportTimeout: time.Minute},
+		API: config.APIConfig{
+			V1DeprecatedAt: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+			V1Sunset:       time.Date(2027, 5, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	full := &model.OrderResponse{
+		OrderUID:        "order-1",
+		TrackNumber:     "TRK123",
+		CustomerID:      "cust-1",
+		DeliveryService: "meest",
+		DateCreated:     created,
+		Delivery:        model.DeliveryResponse{Name: "John Doe", Phone: "+1234567", City: "City", Address: "Street 1", Email: "john@example.com"},
+		Payment:         model.PaymentResponse{Transaction: "tx-1", Currency: "USD", Amount: 1817, PaymentDT: created.Unix()},
+		Items: []model.ItemResponse{{
+			RID: "rid-1", Name: "Mascaras", Price: 453, Brand: "Vivienne Sabo",
+			Status: model.ItemStatusShipped, StatusName: "shipped",
+			History: []model.StatusChange{
+				model.NewStatusChange(model.ItemStatusCreated, created),
+				model.NewStatusChange(model.ItemStatusShipped, created.Add(time.Hour)),
+			},
+		}},
+	}
+	details := &model.OrderDetails{
+		Order: model.Order{
+			OrderUID: "order-1", TrackNumber: "TRK123", Entry: "WBIL", Locale: "en", CustomerID: "cust-1",
+			DeliveryService: "meest", ShardKey: "9", SmID: 99, DateCreated: created, OofShard: "1", CreatedAt: created,
+			Delivery: model.Delivery{Name: "John Doe", Phone: "+1234567", Zip: "2639809", City: "City", Address: "Street 1", Region: "Kraiot", Email: "john@example.com"},
+			Payment: model.Payment{
+				Transaction: "tx-1", Currency: "USD", Provider: "wbpay", Amount: 1817, PaymentDT: created.Unix(),
+				Bank: "alpha", DeliveryCost: 1500, GoodsTotal: 317,
+			},
+			Items: []model.Item{{
+				ChrtID: 9934930, TrackNumber: "TRK123", Price: 453, RID: "rid-1", Name: "Mascaras", Sale: 30,
+				Size: "0", TotalPrice: 317, NmID: 2389212, Brand: "Vivienne Sabo", Status: model.ItemStatusShipped,
+			}},
+		},
+		History: map[string][]model.StatusChange{"rid-1": full.Items[0].History},
+	}
+	preview := &model.OrderPreview{OrderUID: "order-1", TrackNumber: "TRK123", CustomerID: "cust-1", DateCreated: created}
+	webhook := &model.Webhook{ID: 1, URL: "https://partner.example/hooks", EventTypes: []model.EventType{model.EventOrderStored}, Enabled: true, CreatedAt: created}
+
+	orders := mocks.NewOrderRepository(t)
+	orders.On("GetByID", mock.Anything, "order-1").Return(full, nil).Maybe()
+	orders.On("GetByID", mock.Anything, "missing").Return(nil, errors.Wrap(apperr.ErrNotFound, "not found")).Maybe()
+	orders.On("GetByTrackNumber", mock.Anything, "TRK123").Return(full, nil).Maybe()
+	orders.On("GetAll", mock.Anything, mock.Anything).Return([]*model.OrderPreview{preview}, nil).Maybe()
+	orders.On("ListByCustomer", mock.Anything, "cust-1", 5, 0).Return([]*model.OrderPreview{preview}, nil).Maybe()
+	orders.On("ExportOrders", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	analytics := mocks.NewAnalyticsRepository(t)
+	analytics.On("OrderStats", mock.Anything, mock.Anything).Return([]model.StatsBucket{{Key: "meest", Orders: 3, Revenue: 4500}}, nil).Maybe()
+	analytics.On("BasketStats", mock.Anything, mock.Anything).Return(&model.BasketStats{Orders: 3, AvgBasket: 1500, AvgItemCount: 1.5, ItemCounts: []model.ItemCountBucket{{Items: 1, Orders: 3}}}, nil).Maybe()
+	analytics.On("TopBrands", mock.Anything, mock.Anything).Return([]model.BrandTop{{Brand: "Acme", Units: 4, Revenue: 1200}}, nil).Maybe()
+	analytics.On("TopProducts", mock.Anything, mock.Anything).Return([]model.ProductTop{{NmID: 2389212, Name: "Mascaras", Brand: "Acme", Units: 4, Revenue: 1200}}, nil).Maybe()
+
+	webhooks := mocks.NewWebhookRepository(t)
+	webhooks.On("CreateWebhook", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&model.Webhook{ID: 1, URL: webhook.URL, EventTypes: webhook.EventTypes, Enabled: true, Secret: "s3cr3t", CreatedAt: created}, nil).Maybe()
+	webhooks.On("ListWebhooks", mock.Anything).Return([]*model.Webhook{webhook}, nil).Maybe()
+	webhooks.On("GetWebhook", mock.Anything, int64(1)).Return(webhook, nil).Maybe()
+	webhooks.On("GetWebhook", mock.Anything, int64(2)).Return(nil, errors.Wrap(apperr.ErrNotFound, "webhook not found")).Maybe()
+	webhooks.On("UpdateWebhook", mock.Anything, int64(1), mock.Anything).Return(webhook, nil).Maybe()
+	webhooks.On("DeleteWebhook", mock.Anything, int64(1)).Return(nil).Maybe()
+	webhooks.On("ListDeliveries", mock.Anything, int64(1), 20, 0).Return([]*model.WebhookDelivery{{
+		ID: 7, WebhookID: 1, EventID: "order.stored:order-1", EventType: model.EventOrderStored,
+		Status: model.DeliveryDelivered, Attempts: 1, NextAttemptAt: created, CreatedAt: created,
+	}}, nil).Maybe()
+
+	query := mocks.NewQueryRepository(t)
+	query.On("GetOrdersByUIDs", mock.Anything, []string{"order-1"}).Return([]*model.OrderResponse{full}, nil).Maybe()
+	query.On("GetOrderDetails", mock.Anything, "order-1").Return(details, nil).Maybe()
+	query.On("GetOrderDetails", mock.Anything, "missing").Return(nil, errors.Wrap(apperr.ErrNotFound, "not found")).Maybe()
+	query.On("GetOrderDetailsByTrack", mock.Anything, "TRK123").Return(details, nil).Maybe()
+	query.On("ListOrderPage", mock.Anything, mock.Anything, mock.Anything, 2).Return([]*model.OrderPreview{preview, preview}, nil).Maybe()
+	query.On("ListOrderPage", mock.Anything, mock.Anything, mock.Anything, 21).Return([]*model.OrderPreview{preview}, nil).Maybe()
+
+	docs, err := order.NewDocs()
+	require.NoError(t, err)
+
+	checker := health.New(time.Second)
+	checker.Add("postgres", func(context.Context) error { return nil })
+
+	origins := origin.NewAllowlist(nil)
+	return GetRouter(cfg, logger, origins, middleware.NewRateLimiter(0, 0),
+		order.New(usecase.New(orders), logger),
+		order.NewV2(usecase.New(orders), usecase.NewQuery(query), logger),
+		order.NewAnalytics(usecase.NewAnalytics(analytics), logger),
+		order.NewAdmin(stubOutbox{}, usecase.NewWebhooks(webhooks, nil), logger),
+		order.NewFeed(feed.NewHub(10), time.Second, logger),
+		order.NewLive(cfg, origins, ws.NewHub(cfg, orders, logger), logger),
+		order.NewGraphQL(usecase.NewQuery(query), logger),
+		docs,
+		order.NewHealth(checker),
+	)
+}
+
+func TestRouter_RoutesMatchSpec(t *testing.T) {
+	doc, err := api.LoadSpec()
+	require.NoError(t, err)
+
+	var documented []string
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			documented = append(documented, method+" "+path)
+		}
+	}
+
+	var routed []string
+	err = chi.Walk(newTestRouter(t), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		routed = append(routed, method+" "+strings.TrimSuffix(route, "/"))
+		return nil
+	})
+	require.NoError(t, err)
+
+	sort.Strings(documented)
+	sort.Strings(routed)
+	assert.Equal(t, documented, routed)
+}
+
+// Streaming endpoints (/api/orders/stream, /api/orders/ws) never complete and are
+// only covered by TestRouter_RoutesMatchSpec.
+func TestRouter_ResponsesConformToSpec(t *testing.T) {
+	type testCase struct {
+		method   string
+		target   string
+		body     string
+		admin    bool
+		wantCode int
+		// invalid marks requests that violate the spec on purpose to exercise error responses.
+		invalid bool
+	}
+
+	tests := []testCase{
+		{method: http.MethodGet, target: "/api/order/order-1", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/order/missing", wantCode: http.StatusNotFound},
+		{method: http.MethodGet, target: "/api/order/order-1/timeline", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/orders?customer_id=cust-1&from=2024-01-01", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/orders?from=yesterday", wantCode: http.StatusBadRequest},
+		{method: http.MethodGet, target: "/api/orders/export?format=ndjson", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/orders/export?format=pdf", wantCode: http.StatusBadRequest, invalid: true},
+		{method: http.MethodGet, target: "/api/orders/by-track/TRK123", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/customers/cust-1/orders?limit=5", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/customers/cust-1/orders?limit=500", wantCode: http.StatusBadRequest, invalid: true},
+		{method: http.MethodGet, target: "/api/v1/order/order-1", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/order/order-1/timeline", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/orders", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/orders/by-track/TRK123", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/customers/cust-1/orders?limit=5", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v2/orders/order-1", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v2/orders/missing", wantCode: http.StatusNotFound},
+		{method: http.MethodGet, target: "/api/v2/orders/order-1/timeline", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v2/orders?limit=1&delivery_service=meest", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v2/orders?cursor=%21", wantCode: http.StatusBadRequest},
+		{method: http.MethodGet, target: "/api/v2/orders/by-track/TRK123", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v2/customers/cust-1/orders", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/analytics/orders?group_by=delivery_service", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/analytics/basket?from=2024-01-01&to=2024-02-01", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/reports/top?by=revenue", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/reports/top?format=csv", wantCode: http.StatusOK},
+		{method: http.MethodPost, target: "/api/graphql", body: `{"query": "{ order(uid: \"order-1\") { trackNumber } }"}`, wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/openapi.json", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/docs", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/healthz", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/readyz", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/metrics", wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/admin/outbox", admin: true, wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/admin/outbox", wantCode: http.StatusUnauthorized},
+		{method: http.MethodGet, target: "/api/admin/log-level", admin: true, wantCode: http.StatusOK},
+		{method: http.MethodPut, target: "/api/admin/log-level", admin: true, body: `{"level": "info"}`, wantCode: http.StatusOK},
+		{method: http.MethodPut, target: "/api/admin/log-level", admin: true, body: `{"level": "trace"}`, wantCode: http.StatusBadRequest, invalid: true},
+		{method: http.MethodPost, target: "/api/admin/webhooks", admin: true, body: `{"url": "https://partner.example/hooks", "event_types": ["order.stored"]}`, wantCode: http.StatusCreated},
+		{method: http.MethodPost, target: "/api/admin/webhooks", admin: true, body: `{"url": "/hooks"}`, wantCode: http.StatusBadRequest},
+		{method: http.MethodGet, target: "/api/admin/webhooks", admin: true, wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/admin/webhooks/1", admin: true, wantCode: http.StatusOK},
+		{method: http.MethodGet, target: "/api/admin/webhooks/2", admin: true, wantCode: http.StatusNotFound},
+		{method: http.MethodPatch, target: "/api/admin/webhooks/1", admin: true, body: `{"enabled": true}`, wantCode: http.StatusOK},
+		{method: http.MethodDelete, target: "/api/admin/webhooks/1", admin: true, wantCode: http.StatusNoContent},
+		{method: http.MethodGet, target: "/api/admin/webhooks/1/deliveries", admin: true, wantCode: http.StatusOK},
+	}
+
+	doc, err := api.LoadSpec()
+	require.NoError(t, err)
+	specRouter, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+	router := newTestRouter(t)
+
+	for _, tc := range tests {
+		t.Run(tc.method+" "+tc.target, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			if tc.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			if tc.admin {
+				req.Header.Set("Authorization", "Bearer "+testAdminToken)
+			}
+
+			route, pathParams, err := specRouter.FindRoute(req)
+			require.NoError(t, err, "route is not documented")
+
+			input := &openapi3filter.RequestValidationInput{
+				Request:    req,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc},
+			}
+			if err := openapi3filter.ValidateRequest(context.Background(), input); tc.invalid {
+				require.Error(t, err, "request was expected to violate the spec")
+			} else {
+				require.NoError(t, err)
+			}
+			// ValidateRequest consumed the body.
+			req.Body = io.NopCloser(strings.NewReader(tc.body))
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			require.Equal(t, tc.wantCode, rec.Code, rec.Body.String())
+
+			err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: input,
+				Status:                 rec.Code,
+				Header:                 rec.Header(),
+				Body:                   io.NopCloser(rec.Body),
+				Options:                &openapi3filter.Options{ExcludeResponseBody: !isJSON(rec.Header())},
+			})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRouter_V1Deprecated(t *testing.T) {
+	router := newTestRouter(t)
+
+	tests := []struct {
+		target     string
+		deprecated bool
+	}{
+		{target: "/api/order/order-1", deprecated: true},
+		{target: "/api/v1/order/order-1", deprecated: true},
+		{target: "/api/v1/orders", deprecated: true},
+		{target: "/api/v2/orders/order-1", deprecated: false},
+		{target: "/api/analytics/basket", deprecated: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.target, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.target, nil))
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			if !tc.deprecated {
+				assert.Empty(t, rec.Header().Get("Deprecation"))
+				assert.Empty(t, rec.Header().Get("Sunset"))
+				return
+			}
+			assert.Equal(t, "@1793491200", rec.Header().Get("Deprecation"))
+			assert.Equal(t, "Sat, 01 May 2027 00:00:00 GMT", rec.Header().Get("Sunset"))
+			assert.Contains(t, rec.Header().Get("Link"), `rel="deprecation"`)
+		})
+	}
+}
+
+func isJSON(h http.Header) bool {
+	ct := h.Get("Content-Type")
+	return strings.HasPrefix(ct, "application/json") || strings.HasPrefix(ct, problem.ContentType)
+}
@@ -1,7 +1,117 @@
 package apperr
 
 import (
+	"context"
 	"errors"
 )
 
-var ErrNotFound = errors.New("not found")
+// Error kinds. Every failure is classified by the first kind found in its chain;
+// anything else is an internal error.
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrInvalidArgument = errors.New("invalid argument")
+	ErrConflict        = errors.New("conflict")
+	ErrUnavailable     = errors.New("unavailable")
+	ErrTimeout         = errors.New("timeout")
+	ErrRateLimited     = errors.New("rate limited")
+	ErrUnauthorized    = errors.New("unauthorized")
+	ErrForbidden       = errors.New("forbidden")
+)
+
+var (
+	// ErrInvalidData marks records the storage rejects; retrying them can never succeed.
+	ErrInvalidData = New(ErrInvalidArgument, "invalid data")
+	// ErrInvalidTransition is a status change the item state machine does not allow.
+	ErrInvalidTransition = New(ErrConflict, "invalid status transition")
+)
+
+// Error is a failure of a known kind with a message that is safe to show to clients.
+type Error struct {
+	Kind error
+	Msg  string
+}
+
+func New(kind error, msg string) error {
+	return &Error{Kind: kind, Msg: msg}
+}
+
+func (e *Error) Error() string { return e.Msg }
+
+func (e *Error) Unwrap() error { return e.Kind }
+
+// Message returns the client-facing message of err: the message of the outermost
+// Error in its chain, or the text of its kind. It is empty for internal errors.
+func Message(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Msg
+	}
+	code := CodeOf(err)
+	if code == CodeInternal {
+		return ""
+	}
+	return code.kind().Error()
+}
+
+// Code is the stable, machine-readable name of an error kind.
+type Code string
+
+const (
+	CodeNotFound        Code = "not_found"
+	CodeInvalidArgument Code = "invalid_argument"
+	CodeConflict        Code = "conflict"
+	CodeUnavailable     Code = "unavailable"
+	CodeTimeout         Code = "timeout"
+	CodeRateLimited     Code = "rate_limited"
+	CodeUnauthorized    Code = "unauthorized"
+	CodeForbidden       Code = "forbidden"
+	CodeInternal        Code = "internal"
+)
+
+var kinds = []struct {
+	code Code
+	kind error
+}{
+	{CodeNotFound, ErrNotFound},
+	{CodeInvalidArgument, ErrInvalidArgument},
+	{CodeConflict, ErrConflict},
+	{CodeUnavailable, ErrUnavailable},
+	{CodeTimeout, ErrTimeout},
+	{CodeRateLimited, ErrRateLimited},
+	{CodeUnauthorized, ErrUnauthorized},
+	{CodeForbidden, ErrForbidden},
+}
+
+// CodeOf classifies err. A deadline exceeded anywhere in the chain is a timeout.
+func CodeOf(err error) Code {
+	for _, k := range kinds {
+		if errors.Is(err, k.kind) {
+			return k.code
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return CodeTimeout
+	}
+	return CodeInternal
+}
+
+func (c Code) kind() error {
+	for _, k := range kinds {
+		if k.code == c {
+			return k.kind
+		}
+	}
+	return nil
+}
+
+// Retryable reports whether the same operation may succeed if attempted again.
+// Internal errors are not: they are failures nobody classified, usually bugs, and
+// repeating them would stall a consumer on one message forever.
+func (c Code) Retryable() bool {
+	switch c {
+	case CodeUnavailable, CodeTimeout, CodeRateLimited:
+		return true
+	default:
+		return false
+	}
+}
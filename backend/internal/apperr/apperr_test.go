@@ -0,0 +1,37 @@
+package apperr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    Code
+		wantMessage string
+		retryable   bool
+	}{
+		{name: "wrapped not found", err: pkgerrors.Wrap(New(ErrNotFound, "order not found"), "get order"), wantCode: CodeNotFound, wantMessage: "order not found"},
+		{name: "bare kind", err: ErrConflict, wantCode: CodeConflict, wantMessage: "conflict"},
+		{name: "invalid data", err: fmt.Errorf("%w: duplicate key", ErrInvalidData), wantCode: CodeInvalidArgument, wantMessage: "invalid data"},
+		{name: "invalid transition", err: pkgerrors.Wrapf(ErrInvalidTransition, "paid -> created"), wantCode: CodeConflict, wantMessage: "invalid status transition"},
+		{name: "deadline", err: pkgerrors.Wrap(context.DeadlineExceeded, "query"), wantCode: CodeTimeout, wantMessage: "timeout", retryable: true},
+		{name: "unavailable", err: fmt.Errorf("%w: connection refused", ErrUnavailable), wantCode: CodeUnavailable, wantMessage: "unavailable", retryable: true},
+		{name: "unknown", err: errors.New("boom"), wantCode: CodeInternal, wantMessage: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantCode, CodeOf(tc.err))
+			assert.Equal(t, tc.wantMessage, Message(tc.err))
+			assert.Equal(t, tc.retryable, CodeOf(tc.err).Retryable())
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/config"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/repository"
+)
+
+type wrapStats struct {
+	value     any
+	expiresAt time.Time
+}
+
+// AnalyticsDecorator caches aggregate results per query for CACHE_ANALYTICS_TTL.
+type AnalyticsDecorator struct {
+	repo repository.AnalyticsRepository
+	ttl  time.Duration
+
+	mu    sync.RWMutex
+	stats map[string]wrapStats
+}
+
+func NewAnalytics(cfg *config.Config, repo repository.AnalyticsRepository) *AnalyticsDecorator {
+	cache := &AnalyticsDecorator{
+		repo:  repo,
+		ttl:   cfg.Cache.AnalyticsTTL,
+		stats: make(map[string]wrapStats),
+	}
+
+	cache.cleanExpired(cfg.Cache.CleanupInterval)
+
+	return cache
+}
+
+func (c *AnalyticsDecorator) get(key string) (any, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	wrap, ok := c.stats[key]
+	if !ok || time.Now().After(wrap.expiresAt) {
+		return nil, false
+	}
+	return wrap.value, true
+}
+
+func (c *AnalyticsDecorator) set(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats[key] = wrapStats{
+		value:     value,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+}
+
+func (c *AnalyticsDecorator) cleanExpired(cleanupInterval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(cleanupInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			c.mu.Lock()
+			for key, value := range c.stats {
+				if time.Now().After(value.expiresAt) {
+					delete(c.stats, key)
+				}
+			}
+			c.mu.Unlock()
+		}
+	}()
+}
+
+func rangeKey(tr model.TimeRange) string {
+	return fmt.Sprintf("%d:%d", tr.From.UnixNano(), tr.To.UnixNano())
+}
+
+func (c *AnalyticsDecorator) OrderStats(ctx context.Context, q model.StatsQuery) ([]model.StatsBucket, error) {
+	key := "orders:" + string(q.GroupBy) + ":" + rangeKey(q.TimeRange)
+	if cached, ok := c.get(key); ok {
+		return cached.([]model.StatsBucket), nil
+	}
+
+	buckets, err := c.repo.OrderStats(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	c.set(key, buckets)
+	return buckets, nil
+}
+
+func topKey(prefix string, q model.TopQuery) string {
+	return fmt.Sprintf("%s:%s:%d:%s", prefix, q.By, q.Limit, rangeKey(q.TimeRange))
+}
+
+func (c *AnalyticsDecorator) BasketStats(ctx context.Context, tr model.TimeRange) (*model.BasketStats, error) {
+	key := "basket:" + rangeKey(tr)
+	if cached, ok := c.get(key); ok {
+		return cached.(*model.BasketStats), nil
+	}
+
+	stats, err := c.repo.BasketStats(ctx, tr)
+	if err != nil {
+		return nil, err
+	}
+
+	c.set(key, stats)
+	return stats, nil
+}
+
+func (c *AnalyticsDecorator) TopBrands(ctx context.Context, q model.TopQuery) ([]model.BrandTop, error) {
+	key := topKey("brands", q)
+	if cached, ok := c.get(key); ok {
+		return cached.([]model.BrandTop), nil
+	}
+
+	brands, err := c.repo.TopBrands(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	c.set(key, brands)
+	return brands, nil
+}
+
+func (c *AnalyticsDecorator) TopProducts(ctx context.Context, q model.TopQuery) ([]model.ProductTop, error) {
+	key := topKey("products", q)
+	if cached, ok := c.get(key); ok {
+		return cached.([]model.ProductTop), nil
+	}
+
+	products, err := c.repo.TopProducts(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	c.set(key, products)
+	return products, nil
+}
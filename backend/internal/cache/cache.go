@@ -3,9 +3,11 @@ package cache
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/GkadyrG/L0/backend/config"
+	"github.com/GkadyrG/L0/backend/internal/metrics"
 	"github.com/GkadyrG/L0/backend/internal/model"
 	"github.com/GkadyrG/L0/backend/internal/repository"
 	"github.com/pkg/errors"
@@ -18,24 +20,37 @@ type wrapOrder struct {
 	updatedAt time.Time
 }
 
+// Listener is notified after an order has been stored or changed through the cache.
+type Listener interface {
+	OrderSaved(order *model.OrderResponse)
+	OrderUpdated(order *model.OrderResponse)
+}
+
 type CacheDecorator struct {
-	repo repository.OrderRepository
+	repo      repository.OrderRepository
+	listeners []Listener
 
 	mu     sync.RWMutex
 	orders map[string]wrapOrder
+	tracks map[string]string
+
+	ttl atomic.Int64
 }
 
-func New(ctx context.Context, cfg *config.Config, orderRepo repository.OrderRepository) (*CacheDecorator, error) {
+func New(ctx context.Context, cfg *config.Config, orderRepo repository.OrderRepository, listeners ...Listener) (*CacheDecorator, error) {
 	cache := &CacheDecorator{
-		orders: make(map[string]wrapOrder),
-		repo:   orderRepo,
+		orders:    make(map[string]wrapOrder),
+		tracks:    make(map[string]string),
+		repo:      orderRepo,
+		listeners: listeners,
 	}
+	cache.SetTTL(cfg.Cache.TTL)
 
 	if err := cache.initializeCache(ctx); err != nil {
 		return nil, err
 	}
 
-	cache.сleanCash(cfg.Cache.CleanupInterval, cfg.Cache.TTL)
+	cache.сleanCash(cfg.Cache.CleanupInterval)
 
 	return cache, nil
 }
@@ -52,13 +67,22 @@ func (c *CacheDecorator) initializeCache(ctx context.Context) error {
 	return nil
 }
 
+// set caches order. A track number is indexed to the newest cached order with it,
+// the one the repository returns for the track.
 func (c *CacheDecorator) set(order *model.OrderResponse) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	if prev, ok := c.orders[order.OrderUID]; ok && prev.order.TrackNumber != order.TrackNumber &&
+		c.tracks[prev.order.TrackNumber] == order.OrderUID {
+		delete(c.tracks, prev.order.TrackNumber)
+	}
 	c.orders[order.OrderUID] = wrapOrder{
 		order:     order,
 		updatedAt: time.Now(),
 	}
+	if cur, ok := c.orders[c.tracks[order.TrackNumber]]; !ok || !cur.order.DateCreated.After(order.DateCreated) {
+		c.tracks[order.TrackNumber] = order.OrderUID
+	}
 }
 
 func (c *CacheDecorator) get(id string) (*model.OrderResponse, bool) {
@@ -68,16 +92,62 @@ func (c *CacheDecorator) get(id string) (*model.OrderResponse, bool) {
 	return wrap.order, ok
 }
 
-func (c *CacheDecorator) сleanCash(cleanupInterval time.Duration, timeToLive time.Duration) {
+func (c *CacheDecorator) getByTrack(track string) (*model.OrderResponse, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	id, ok := c.tracks[track]
+	if !ok {
+		return nil, false
+	}
+	wrap, ok := c.orders[id]
+	return wrap.order, ok
+}
+
+// Len returns the number of cached orders.
+func (c *CacheDecorator) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.orders)
+}
+
+func (c *CacheDecorator) delete(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deleteLocked(id)
+}
+
+// deleteLocked must be called with c.mu held.
+func (c *CacheDecorator) deleteLocked(id string) {
+	wrap, ok := c.orders[id]
+	if !ok {
+		return
+	}
+	if c.tracks[wrap.order.TrackNumber] == id {
+		delete(c.tracks, wrap.order.TrackNumber)
+	}
+	delete(c.orders, id)
+}
+
+// SetTTL changes how long orders stay cached; it applies from the next cleanup on.
+func (c *CacheDecorator) SetTTL(ttl time.Duration) {
+	c.ttl.Store(int64(ttl))
+}
+
+func (c *CacheDecorator) TTL() time.Duration {
+	return time.Duration(c.ttl.Load())
+}
+
+func (c *CacheDecorator) сleanCash(cleanupInterval time.Duration) {
 	go func() {
 		ticker := time.NewTicker(cleanupInterval)
 		defer ticker.Stop()
 
 		for range ticker.C {
+			timeToLive := c.TTL()
 			c.mu.Lock()
 			for key, value := range c.orders {
 				if time.Now().After(value.updatedAt.Add(timeToLive)) {
-					delete(c.orders, key)
+					c.deleteLocked(key)
 				}
 			}
 			c.mu.Unlock()
@@ -85,16 +155,46 @@ func (c *CacheDecorator) сleanCash(cleanupInterval time.Duration, timeToLive ti
 	}()
 }
 
-func (c *CacheDecorator) Save(ctx context.Context, order *model.Order) error {
-	if err := c.repo.Save(ctx, order); err != nil {
-		return err
+// Save caches and announces the order only if the repository stored it: a
+// redelivered order may be older than the stored one, whose statuses have moved on.
+func (c *CacheDecorator) Save(ctx context.Context, order *model.Order) (bool, error) {
+	stored, err := c.repo.Save(ctx, order)
+	if err != nil || !stored {
+		return stored, err
+	}
+	c.saved(order.ToResponse())
+	return true, nil
+}
+
+// SaveBatch caches and announces only the orders the repository stored.
+func (c *CacheDecorator) SaveBatch(ctx context.Context, orders []*model.Order) ([]string, error) {
+	stored, err := c.repo.SaveBatch(ctx, orders)
+	if err != nil {
+		return nil, err
+	}
+	byUID := make(map[string]*model.Order, len(orders))
+	for _, order := range orders {
+		// the repository stores the first of repeated orders
+		if _, ok := byUID[order.OrderUID]; !ok {
+			byUID[order.OrderUID] = order
+		}
+	}
+	for _, uid := range stored {
+		c.saved(byUID[uid].ToResponse())
+	}
+	return stored, nil
+}
+
+func (c *CacheDecorator) saved(order *model.OrderResponse) {
+	c.set(order)
+	for _, l := range c.listeners {
+		l.OrderSaved(order)
 	}
-	c.set(order.ToResponse())
-	return nil
 }
 
 func (c *CacheDecorator) GetByID(ctx context.Context, id string) (*model.OrderResponse, error) {
 	order, exists := c.get(id)
+	metrics.CacheLookup("id", exists)
 	if exists {
 		return order, nil
 	}
@@ -108,10 +208,54 @@ func (c *CacheDecorator) GetByID(ctx context.Context, id string) (*model.OrderRe
 	return order, nil
 }
 
-func (c *CacheDecorator) GetAll(ctx context.Context) ([]*model.OrderPreview, error) {
-	return c.repo.GetAll(ctx)
+func (c *CacheDecorator) GetByTrackNumber(ctx context.Context, track string) (*model.OrderResponse, error) {
+	order, exists := c.getByTrack(track)
+	metrics.CacheLookup("track", exists)
+	if exists {
+		return order, nil
+	}
+
+	order, err := c.repo.GetByTrackNumber(ctx, track)
+	if err != nil {
+		return nil, err
+	}
+
+	c.set(order)
+	return order, nil
+}
+
+func (c *CacheDecorator) ListByCustomer(ctx context.Context, customerID string, limit, offset int) ([]*model.OrderPreview, error) {
+	return c.repo.ListByCustomer(ctx, customerID, limit, offset)
+}
+
+func (c *CacheDecorator) GetAll(ctx context.Context, filter model.OrderFilter) ([]*model.OrderPreview, error) {
+	return c.repo.GetAll(ctx, filter)
 }
 
 func (c *CacheDecorator) GetAllFull(ctx context.Context, limit int) ([]*model.OrderResponse, error) {
 	return c.repo.GetAllFull(ctx, limit)
 }
+
+func (c *CacheDecorator) UpdateItemStatus(ctx context.Context, event *model.ItemStatusEvent) error {
+	if err := c.repo.UpdateItemStatus(ctx, event); err != nil {
+		return err
+	}
+	c.delete(event.OrderUID)
+
+	if len(c.listeners) == 0 {
+		return nil
+	}
+	// The update is already committed; if the reload fails listeners just miss it.
+	order, err := c.GetByID(ctx, event.OrderUID)
+	if err != nil {
+		return nil
+	}
+	for _, l := range c.listeners {
+		l.OrderUpdated(order)
+	}
+	return nil
+}
+
+func (c *CacheDecorator) ExportOrders(ctx context.Context, filter model.OrderFilter, fn func(*model.ExportRow) error) error {
+	return c.repo.ExportOrders(ctx, filter, fn)
+}
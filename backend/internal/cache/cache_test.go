@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeListener struct {
+	saved []string
+}
+
+func (l *fakeListener) OrderSaved(order *model.OrderResponse) {
+	l.saved = append(l.saved, order.OrderUID)
+}
+func (l *fakeListener) OrderUpdated(*model.OrderResponse) {}
+
+func newTestCache(repo *mocks.OrderRepository, l Listener) *CacheDecorator {
+	return &CacheDecorator{repo: repo, listeners: []Listener{l}, orders: make(map[string]wrapOrder), tracks: make(map[string]string)}
+}
+
+func TestCacheDecorator_TrackIndex(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	order := func(uid, track string, created time.Time) *model.OrderResponse {
+		return &model.OrderResponse{OrderUID: uid, TrackNumber: track, DateCreated: created}
+	}
+
+	tests := []struct {
+		name   string
+		orders []*model.OrderResponse
+		track  string
+		want   string
+	}{
+		{
+			name:   "newest order wins",
+			orders: []*model.OrderResponse{order("new", "TRK", day.Add(time.Hour)), order("old", "TRK", day)},
+			track:  "TRK",
+			want:   "new",
+		},
+		{
+			name:   "newer order replaces",
+			orders: []*model.OrderResponse{order("old", "TRK", day), order("new", "TRK", day.Add(time.Hour))},
+			track:  "TRK",
+			want:   "new",
+		},
+		{
+			name: "moving an order keeps the other owner of its old track",
+			orders: []*model.OrderResponse{
+				order("a", "TRK", day.Add(time.Hour)),
+				order("b", "TRK", day),
+				order("b", "OTHER", day),
+			},
+			track: "TRK",
+			want:  "a",
+		},
+		{
+			name:   "moved order is found by its new track",
+			orders: []*model.OrderResponse{order("a", "TRK", day), order("a", "OTHER", day)},
+			track:  "OTHER",
+			want:   "a",
+		},
+		{
+			name:   "old track of a moved order is dropped",
+			orders: []*model.OrderResponse{order("a", "TRK", day), order("a", "OTHER", day)},
+			track:  "TRK",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &CacheDecorator{orders: make(map[string]wrapOrder), tracks: make(map[string]string)}
+			for _, o := range tc.orders {
+				c.set(o)
+			}
+
+			got, ok := c.getByTrack(tc.track)
+			if tc.want == "" {
+				assert.False(t, ok)
+				return
+			}
+			assert.True(t, ok)
+			assert.Equal(t, tc.want, got.OrderUID)
+		})
+	}
+}
+
+func TestCacheDecorator_Save(t *testing.T) {
+	cached := &model.OrderResponse{OrderUID: "dup", Items: []model.ItemResponse{{Status: model.ItemStatusShipped}}}
+
+	repo := mocks.NewOrderRepository(t)
+	repo.On("Save", mock.Anything, mock.MatchedBy(func(o *model.Order) bool { return o.OrderUID == "new" })).Return(true, nil)
+	repo.On("Save", mock.Anything, mock.MatchedBy(func(o *model.Order) bool { return o.OrderUID == "dup" })).Return(false, nil)
+	l := &fakeListener{}
+	c := newTestCache(repo, l)
+	c.set(cached)
+
+	for _, uid := range []string{"new", "dup"} {
+		_, err := c.Save(context.Background(), &model.Order{OrderUID: uid, Items: []model.Item{{Status: model.ItemStatusCreated}}})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"new"}, l.saved)
+	got, ok := c.get("dup")
+	require.True(t, ok)
+	assert.Same(t, cached, got, "a duplicate does not replace the cached order")
+}
+
+func TestCacheDecorator_SaveBatch(t *testing.T) {
+	orders := []*model.Order{{OrderUID: "new"}, {OrderUID: "dup"}, {OrderUID: "other"}}
+
+	repo := mocks.NewOrderRepository(t)
+	repo.On("SaveBatch", mock.Anything, orders).Return([]string{"new", "other"}, nil)
+	l := &fakeListener{}
+	c := newTestCache(repo, l)
+
+	stored, err := c.SaveBatch(context.Background(), orders)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"new", "other"}, stored)
+	assert.Equal(t, []string{"new", "other"}, l.saved)
+	_, ok := c.get("dup")
+	assert.False(t, ok, "a skipped duplicate is not cached")
+}
@@ -0,0 +1,238 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/pkg/errors"
+	"github.com/xuri/excelize/v2"
+)
+
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+	FormatXLSX   Format = "xlsx"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case FormatCSV, FormatNDJSON, FormatXLSX:
+		return f, nil
+	case "":
+		return FormatCSV, nil
+	}
+	return "", errors.Errorf("unsupported export format %q", s)
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// Writer receives export rows in cursor order and renders them in one format.
+// Close must be called to flush buffered output; a failed export calls Abort
+// instead to release what the writer holds. Abort does nothing after Close.
+type Writer interface {
+	Write(row *model.ExportRow) error
+	Close() error
+	Abort()
+}
+
+func NewWriter(f Format, w io.Writer) (Writer, error) {
+	switch f {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatNDJSON:
+		return &ndjsonWriter{enc: json.NewEncoder(w)}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	}
+	return nil, errors.Errorf("unsupported export format %q", f)
+}
+
+var columns = []string{
+	"order_uid", "track_number", "entry", "locale", "customer_id", "delivery_service",
+	"shardkey", "sm_id", "date_created", "oof_shard",
+	"delivery_name", "delivery_phone", "delivery_zip", "delivery_city",
+	"delivery_address", "delivery_region", "delivery_email",
+	"payment_transaction", "payment_currency", "payment_provider", "payment_amount",
+	"payment_dt", "payment_bank", "payment_delivery_cost", "payment_goods_total", "payment_custom_fee",
+	"item_chrt_id", "item_rid", "item_name", "item_brand", "item_size", "item_price",
+	"item_sale", "item_total_price", "item_nm_id", "item_status",
+}
+
+// values flattens a row in the order of columns. Item columns are empty for orders without items.
+func values(row *model.ExportRow) []any {
+	o := row.Order
+	v := []any{
+		o.OrderUID, o.TrackNumber, o.Entry, o.Locale, o.CustomerID, o.DeliveryService,
+		o.ShardKey, o.SmID, o.DateCreated.UTC().Format(time.RFC3339), o.OofShard,
+		o.Delivery.Name, o.Delivery.Phone, o.Delivery.Zip, o.Delivery.City,
+		o.Delivery.Address, o.Delivery.Region, o.Delivery.Email,
+		o.Payment.Transaction, o.Payment.Currency, o.Payment.Provider, o.Payment.Amount,
+		o.Payment.PaymentDT, o.Payment.Bank, o.Payment.DeliveryCost, o.Payment.GoodsTotal, o.Payment.CustomFee,
+	}
+
+	if i := row.Item; i != nil {
+		return append(v, i.ChrtID, i.RID, i.Name, i.Brand, i.Size, i.Price,
+			i.Sale, i.TotalPrice, i.NmID, i.Status.String())
+	}
+	return append(v, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return nil, errors.Wrap(err, "write csv header")
+	}
+	return &csvWriter{w: cw}, nil
+}
+
+func (c *csvWriter) Write(row *model.ExportRow) error {
+	vals := values(row)
+	record := make([]string, len(vals))
+	for i, v := range vals {
+		if v != nil {
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Abort() {}
+
+// ndjsonWriter emits one order per line in the same shape the consumer accepts,
+// folding consecutive rows of an order into its items.
+type ndjsonWriter struct {
+	enc     *json.Encoder
+	current *model.Order
+}
+
+func (n *ndjsonWriter) Write(row *model.ExportRow) error {
+	if n.current != nil && n.current.OrderUID != row.Order.OrderUID {
+		if err := n.flush(); err != nil {
+			return err
+		}
+	}
+
+	if n.current == nil {
+		order := row.Order
+		order.Items = []model.Item{}
+		n.current = &order
+	}
+
+	if row.Item != nil {
+		n.current.Items = append(n.current.Items, *row.Item)
+	}
+	return nil
+}
+
+func (n *ndjsonWriter) flush() error {
+	if n.current == nil {
+		return nil
+	}
+	err := n.enc.Encode(n.current)
+	n.current = nil
+	return err
+}
+
+func (n *ndjsonWriter) Close() error {
+	return n.flush()
+}
+
+func (n *ndjsonWriter) Abort() {
+	n.current = nil
+}
+
+// xlsxWriter is not streamed: the workbook is assembled with excelize's stream
+// writer, which spills rows to a temporary file, and written out only on Close.
+// A sheet holds at most excelize.TotalRows rows including the header.
+type xlsxWriter struct {
+	out     io.Writer
+	file    *excelize.File
+	stream  *excelize.StreamWriter
+	row     int
+	maxRows int
+	closed  bool
+}
+
+const xlsxSheet = "Sheet1"
+
+// ErrTooManyRows is returned for rows that do not fit into an XLSX sheet.
+var ErrTooManyRows = apperr.New(apperr.ErrInvalidArgument,
+	fmt.Sprintf("xlsx export is limited to %d rows, narrow the filter or use csv", excelize.TotalRows-1))
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	f := excelize.NewFile()
+	sw, err := f.NewStreamWriter(xlsxSheet)
+	if err != nil {
+		f.Close()
+		return nil, errors.Wrap(err, "new stream writer")
+	}
+
+	header := make([]any, len(columns))
+	for i, c := range columns {
+		header[i] = c
+	}
+	if err := sw.SetRow("A1", header); err != nil {
+		f.Close()
+		return nil, errors.Wrap(err, "write xlsx header")
+	}
+
+	return &xlsxWriter{out: w, file: f, stream: sw, row: 1, maxRows: excelize.TotalRows}, nil
+}
+
+func (x *xlsxWriter) Write(row *model.ExportRow) error {
+	if x.row >= x.maxRows {
+		return ErrTooManyRows
+	}
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	return x.stream.SetRow(cell, values(row))
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.Abort()
+
+	if err := x.stream.Flush(); err != nil {
+		return errors.Wrap(err, "flush xlsx stream")
+	}
+	if _, err := x.file.WriteTo(x.out); err != nil {
+		return errors.Wrap(err, "write xlsx")
+	}
+	return nil
+}
+
+// Abort removes the temporary files the stream writer spilled rows to.
+func (x *xlsxWriter) Abort() {
+	if x.closed {
+		return
+	}
+	x.closed = true
+	x.file.Close()
+}
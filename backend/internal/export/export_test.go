@@ -0,0 +1,124 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func testRows() []*model.ExportRow {
+	created := time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC)
+	first := model.Order{OrderUID: "order-1", TrackNumber: "TRK1", DateCreated: created}
+	second := model.Order{OrderUID: "order-2", TrackNumber: "TRK2", DateCreated: created}
+
+	return []*model.ExportRow{
+		{Order: first, Item: &model.Item{RID: "rid-1", Name: "Mascaras", Status: model.ItemStatusCreated}},
+		{Order: first, Item: &model.Item{RID: "rid-2", Name: "Lipstick", Status: model.ItemStatusPaid}},
+		{Order: second},
+	}
+}
+
+func writeAll(t *testing.T, f Format, rows []*model.ExportRow) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w, err := NewWriter(f, &buf)
+	require.NoError(t, err)
+	for _, row := range rows {
+		require.NoError(t, w.Write(row))
+	}
+	require.NoError(t, w.Close())
+
+	return &buf
+}
+
+func TestCSVWriter_OneRowPerItem(t *testing.T) {
+	buf := writeAll(t, FormatCSV, testRows())
+
+	records, err := csv.NewReader(buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Equal(t, columns, records[0])
+
+	rid := indexOf(columns, "item_rid")
+	assert.Equal(t, "rid-1", records[1][rid])
+	assert.Equal(t, "rid-2", records[2][rid])
+	assert.Equal(t, "", records[3][rid])
+	assert.Equal(t, "paid", records[2][indexOf(columns, "item_status")])
+}
+
+func TestNDJSONWriter_GroupsItemsByOrder(t *testing.T) {
+	buf := writeAll(t, FormatNDJSON, testRows())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var first, second model.Order
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+
+	assert.Equal(t, "order-1", first.OrderUID)
+	assert.Len(t, first.Items, 2)
+	assert.Equal(t, "order-2", second.OrderUID)
+	assert.Empty(t, second.Items)
+}
+
+func TestXLSXWriter(t *testing.T) {
+	buf := writeAll(t, FormatXLSX, testRows())
+
+	f, err := excelize.OpenReader(buf)
+	require.NoError(t, err)
+	defer f.Close()
+
+	rows, err := f.GetRows(xlsxSheet)
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+	assert.Equal(t, "order_uid", rows[0][0])
+	assert.Equal(t, "order-2", rows[3][0])
+}
+
+func TestXLSXWriter_RowLimit(t *testing.T) {
+	w, err := NewWriter(FormatXLSX, &bytes.Buffer{})
+	require.NoError(t, err)
+	defer w.Abort()
+	w.(*xlsxWriter).maxRows = 3
+
+	rows := testRows()
+	require.NoError(t, w.Write(rows[0]))
+	require.NoError(t, w.Write(rows[1]))
+	assert.ErrorIs(t, w.Write(rows[2]), ErrTooManyRows)
+}
+
+func TestXLSXWriter_Abort(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatXLSX, &buf)
+	require.NoError(t, err)
+	require.NoError(t, w.Write(testRows()[0]))
+
+	w.Abort()
+	w.Abort()
+	assert.Zero(t, buf.Len(), "an aborted export writes nothing")
+
+	w, err = NewWriter(FormatXLSX, &buf)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	w.Abort()
+	assert.NotZero(t, buf.Len())
+}
+
+func indexOf(s []string, v string) int {
+	for i := range s {
+		if s[i] == v {
+			return i
+		}
+	}
+	return -1
+}
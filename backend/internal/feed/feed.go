@@ -0,0 +1,170 @@
+package feed
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/model"
+)
+
+const subscriberBuffer = 64
+
+// Event is a newly saved order. DeliveryService is kept for filtering only, the
+// preview is what subscribers receive.
+type Event struct {
+	ID              uint64
+	Preview         model.OrderPreview
+	DeliveryService string
+}
+
+func (e Event) EventID() string {
+	return strconv.FormatUint(e.ID, 10)
+}
+
+type Filter struct {
+	CustomerID      string
+	DeliveryService string
+}
+
+func (f Filter) Match(e Event) bool {
+	return (f.CustomerID == "" || f.CustomerID == e.Preview.CustomerID) &&
+		(f.DeliveryService == "" || f.DeliveryService == e.DeliveryService)
+}
+
+// Hub fans out saved orders to subscribers and keeps the latest events in a ring
+// buffer so reconnecting clients can catch up from their last seen id.
+type Hub struct {
+	mu     sync.Mutex
+	seq    uint64
+	ring   []Event
+	next   int
+	full   bool
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+// NewHub creates a hub remembering the last size events. Ids start from the current
+// time in microseconds, so ids issued after a restart are larger than any id a client
+// saw before it, and such a client simply gets the whole buffer.
+func NewHub(size int) *Hub {
+	return &Hub{
+		seq:  uint64(time.Now().UnixMicro()),
+		ring: make([]Event, max(size, 1)),
+		subs: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscription receives events on C. C is closed when the subscriber falls more than
+// subscriberBuffer events behind or the hub shuts down; the client then reconnects
+// and resumes from the ring buffer.
+type Subscription struct {
+	C      <-chan Event
+	c      chan Event
+	filter Filter
+	hub    *Hub
+}
+
+// OrderSaved stores the order and delivers it to matching subscribers. It is called
+// only for newly stored orders, redelivered ones never reach it.
+func (h *Hub) OrderSaved(order *model.OrderResponse) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return
+	}
+
+	h.seq++
+	e := Event{
+		ID: h.seq,
+		Preview: model.OrderPreview{
+			OrderUID:    order.OrderUID,
+			TrackNumber: order.TrackNumber,
+			CustomerID:  order.CustomerID,
+			DateCreated: order.DateCreated,
+		},
+		DeliveryService: order.DeliveryService,
+	}
+
+	h.ring[h.next] = e
+	h.next = (h.next + 1) % len(h.ring)
+	if h.next == 0 {
+		h.full = true
+	}
+
+	for s := range h.subs {
+		if !s.filter.Match(e) {
+			continue
+		}
+		select {
+		case s.c <- e:
+		default:
+			h.dropLocked(s)
+		}
+	}
+}
+
+// OrderUpdated is a no-op: the feed lists new orders only.
+func (h *Hub) OrderUpdated(*model.OrderResponse) {}
+
+// Subscribe registers a subscriber. If lastID is set, buffered events after it that
+// match the filter are returned for replay; live events follow on the subscription
+// without gaps.
+func (h *Hub) Subscribe(filter Filter, lastID *uint64) (*Subscription, []Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	c := make(chan Event, subscriberBuffer)
+	s := &Subscription{C: c, c: c, filter: filter, hub: h}
+	if h.closed {
+		close(c)
+		return s, nil
+	}
+	h.subs[s] = struct{}{}
+
+	if lastID == nil {
+		return s, nil
+	}
+
+	var replay []Event
+	for _, e := range h.buffered() {
+		if e.ID > *lastID && filter.Match(e) {
+			replay = append(replay, e)
+		}
+	}
+	return s, replay
+}
+
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.dropLocked(s)
+}
+
+// Close ends every subscription so streaming handlers return during shutdown.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for s := range h.subs {
+		h.dropLocked(s)
+	}
+}
+
+func (h *Hub) dropLocked(s *Subscription) {
+	if _, ok := h.subs[s]; !ok {
+		return
+	}
+	delete(h.subs, s)
+	close(s.c)
+}
+
+// buffered returns the ring buffer contents from oldest to newest.
+func (h *Hub) buffered() []Event {
+	if !h.full {
+		return h.ring[:h.next]
+	}
+	return append(append([]Event(nil), h.ring[h.next:]...), h.ring[:h.next]...)
+}
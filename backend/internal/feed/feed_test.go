@@ -0,0 +1,78 @@
+package feed
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func order(uid, customer, service string) *model.OrderResponse {
+	return &model.OrderResponse{OrderUID: uid, CustomerID: customer, DeliveryService: service}
+}
+
+func uids(events []Event) []string {
+	var s []string
+	for _, e := range events {
+		s = append(s, e.Preview.OrderUID)
+	}
+	return s
+}
+
+func TestHub_ReplaysFromRingBuffer(t *testing.T) {
+	h := NewHub(3)
+	var ids []uint64
+	for _, uid := range []string{"o1", "o2", "o3", "o4"} {
+		h.OrderSaved(order(uid, "c1", "meest"))
+		_, replay := h.Subscribe(Filter{}, new(uint64))
+		ids = append(ids, replay[len(replay)-1].ID)
+	}
+
+	_, replay := h.Subscribe(Filter{}, &ids[1])
+	assert.Equal(t, []string{"o3", "o4"}, uids(replay))
+
+	// o1 fell out of the buffer, so an old id replays what is left.
+	_, replay = h.Subscribe(Filter{}, &ids[0])
+	assert.Equal(t, []string{"o2", "o3", "o4"}, uids(replay))
+
+	sub, replay := h.Subscribe(Filter{}, nil)
+	assert.Empty(t, replay)
+	h.OrderSaved(order("o5", "c1", "meest"))
+	assert.Equal(t, "o5", (<-sub.C).Preview.OrderUID)
+}
+
+func TestHub_Filters(t *testing.T) {
+	h := NewHub(10)
+	sub, _ := h.Subscribe(Filter{CustomerID: "c1", DeliveryService: "meest"}, nil)
+
+	h.OrderSaved(order("o1", "c1", "meest"))
+	h.OrderSaved(order("o2", "c2", "meest"))
+	h.OrderSaved(order("o3", "c1", "dhl"))
+	h.OrderSaved(order("o4", "c1", "meest"))
+
+	assert.Equal(t, "o1", (<-sub.C).Preview.OrderUID)
+	assert.Equal(t, "o4", (<-sub.C).Preview.OrderUID)
+	assert.Empty(t, sub.C)
+}
+
+func TestHub_DropsSlowSubscriber(t *testing.T) {
+	h := NewHub(subscriberBuffer * 2)
+	slow, _ := h.Subscribe(Filter{}, nil)
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		h.OrderSaved(order(fmt.Sprintf("o%d", i), "c1", "meest"))
+	}
+
+	n := 0
+	for range slow.C {
+		n++
+	}
+	assert.Equal(t, subscriberBuffer, n)
+
+	h.Close()
+	sub, _ := h.Subscribe(Filter{}, nil)
+	_, ok := <-sub.C
+	require.False(t, ok)
+}
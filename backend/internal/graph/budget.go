@@ -0,0 +1,24 @@
+package graph
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// budget counts the orders a request has asked for so far. Sibling fields resolve
+// concurrently, hence the atomic counter.
+type budget struct {
+	limit int64
+	spent atomic.Int64
+}
+
+func newBudget(limit int) *budget {
+	return &budget{limit: int64(limit)}
+}
+
+func (b *budget) charge(n int) error {
+	if b.spent.Add(int64(n)) > b.limit {
+		return fmt.Errorf("query is too expensive: it may return more than %d orders", b.limit)
+	}
+	return nil
+}
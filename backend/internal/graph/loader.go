@@ -0,0 +1,132 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/usecase"
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// loaderWait is how long a loader collects keys before running the batch. Resolvers of
+// sibling fields run concurrently, so a short wait is enough to gather a whole list.
+const loaderWait = 2 * time.Millisecond
+
+type loadersKey struct{}
+
+// loaders batch and cache lookups for the duration of one request, so resolving a list
+// of orders costs one query per kind of data instead of one per order.
+type loaders struct {
+	orders         *dataloader.Loader[string, *model.OrderResponse]
+	items          *dataloader.Loader[string, []model.ItemResponse]
+	customerOrders *dataloader.Loader[customerOrdersKey, *model.OrderPage]
+	budget         *budget
+}
+
+// customerOrdersKey identifies one page of a customer's orders. after is the cursor
+// as the client sent it, already validated by the resolver.
+type customerOrdersKey struct {
+	customerID string
+	after      string
+	first      int
+}
+
+// WithLoaders returns a context carrying fresh loaders for one GraphQL request.
+func WithLoaders(ctx context.Context, q usecase.QueryProvider) context.Context {
+	l := &loaders{
+		orders:         dataloader.NewBatchedLoader(ordersBatch(q), dataloader.WithWait[string, *model.OrderResponse](loaderWait)),
+		items:          dataloader.NewBatchedLoader(itemsBatch(q), dataloader.WithWait[string, []model.ItemResponse](loaderWait)),
+		customerOrders: dataloader.NewBatchedLoader(customerOrdersBatch(q), dataloader.WithWait[customerOrdersKey, *model.OrderPage](loaderWait)),
+		budget:         newBudget(maxCost),
+	}
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+func ordersBatch(q usecase.QueryProvider) dataloader.BatchFunc[string, *model.OrderResponse] {
+	return func(ctx context.Context, uids []string) []*dataloader.Result[*model.OrderResponse] {
+		results := make([]*dataloader.Result[*model.OrderResponse], len(uids))
+
+		orders, err := q.OrdersByUIDs(ctx, uids)
+		if err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result[*model.OrderResponse]{Error: err}
+			}
+			return results
+		}
+
+		byUID := make(map[string]*model.OrderResponse, len(orders))
+		for _, o := range orders {
+			byUID[o.OrderUID] = o
+		}
+		for i, uid := range uids {
+			if o, ok := byUID[uid]; ok {
+				results[i] = &dataloader.Result[*model.OrderResponse]{Data: o}
+			} else {
+				results[i] = &dataloader.Result[*model.OrderResponse]{Error: apperr.ErrNotFound}
+			}
+		}
+		return results
+	}
+}
+
+func itemsBatch(q usecase.QueryProvider) dataloader.BatchFunc[string, []model.ItemResponse] {
+	return func(ctx context.Context, uids []string) []*dataloader.Result[[]model.ItemResponse] {
+		results := make([]*dataloader.Result[[]model.ItemResponse], len(uids))
+
+		items, err := q.ItemsByOrderUIDs(ctx, uids)
+		for i, uid := range uids {
+			results[i] = &dataloader.Result[[]model.ItemResponse]{Data: items[uid], Error: err}
+		}
+		return results
+	}
+}
+
+// customerOrdersBatch loads the pages of several customers. The customers of one query
+// normally share first and after, so every distinct pair costs one query.
+func customerOrdersBatch(q usecase.QueryProvider) dataloader.BatchFunc[customerOrdersKey, *model.OrderPage] {
+	type pageArgs struct {
+		after string
+		first int
+	}
+
+	return func(ctx context.Context, keys []customerOrdersKey) []*dataloader.Result[*model.OrderPage] {
+		results := make([]*dataloader.Result[*model.OrderPage], len(keys))
+
+		groups := make(map[pageArgs][]int)
+		for i, k := range keys {
+			args := pageArgs{after: k.after, first: k.first}
+			groups[args] = append(groups[args], i)
+		}
+
+		for args, idx := range groups {
+			var after *model.OrderCursor
+			if args.after != "" {
+				cursor, err := model.ParseOrderCursor(args.after)
+				if err != nil {
+					for _, i := range idx {
+						results[i] = &dataloader.Result[*model.OrderPage]{Error: err}
+					}
+					continue
+				}
+				after = &cursor
+			}
+
+			customerIDs := make([]string, len(idx))
+			for j, i := range idx {
+				customerIDs[j] = keys[i].customerID
+			}
+
+			pages, err := q.CustomerOrders(ctx, customerIDs, after, args.first)
+			for _, i := range idx {
+				results[i] = &dataloader.Result[*model.OrderPage]{Data: pages[keys[i].customerID], Error: err}
+			}
+		}
+		return results
+	}
+}
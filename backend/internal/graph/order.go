@@ -0,0 +1,124 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/GkadyrG/L0/backend/internal/model"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// OrderResolver answers the preview fields directly; delivery, payment and items are
+// fetched through the request loaders only when a query selects them.
+type OrderResolver struct {
+	preview model.OrderPreview
+	root    *Resolver
+}
+
+func (o *OrderResolver) UID() graphql.ID {
+	return graphql.ID(o.preview.OrderUID)
+}
+
+func (o *OrderResolver) TrackNumber() string {
+	return o.preview.TrackNumber
+}
+
+func (o *OrderResolver) DateCreated() graphql.Time {
+	return graphql.Time{Time: o.preview.DateCreated}
+}
+
+func (o *OrderResolver) Customer() *CustomerResolver {
+	return &CustomerResolver{id: o.preview.CustomerID, root: o.root}
+}
+
+func (o *OrderResolver) DeliveryService(ctx context.Context) (string, error) {
+	order, err := o.details(ctx)
+	if err != nil {
+		return "", err
+	}
+	return order.DeliveryService, nil
+}
+
+func (o *OrderResolver) Delivery(ctx context.Context) (*DeliveryResolver, error) {
+	order, err := o.details(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &DeliveryResolver{d: order.Delivery}, nil
+}
+
+func (o *OrderResolver) Payment(ctx context.Context) (*PaymentResolver, error) {
+	order, err := o.details(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &PaymentResolver{p: order.Payment}, nil
+}
+
+func (o *OrderResolver) Items(ctx context.Context) ([]*ItemResolver, error) {
+	items, err := loadersFrom(ctx).items.Load(ctx, o.preview.OrderUID)()
+	if err != nil {
+		return nil, o.root.internal(ctx, "failed to load order items", err)
+	}
+
+	resolvers := make([]*ItemResolver, len(items))
+	for i := range items {
+		resolvers[i] = &ItemResolver{item: items[i]}
+	}
+	return resolvers, nil
+}
+
+func (o *OrderResolver) details(ctx context.Context) (*model.OrderResponse, error) {
+	order, err := loadersFrom(ctx).orders.Load(ctx, o.preview.OrderUID)()
+	if err != nil {
+		return nil, o.root.internal(ctx, "failed to load order", err)
+	}
+	return order, nil
+}
+
+type DeliveryResolver struct {
+	d model.DeliveryResponse
+}
+
+func (r *DeliveryResolver) Name() string    { return r.d.Name }
+func (r *DeliveryResolver) Phone() string   { return r.d.Phone }
+func (r *DeliveryResolver) City() string    { return r.d.City }
+func (r *DeliveryResolver) Address() string { return r.d.Address }
+func (r *DeliveryResolver) Email() string   { return r.d.Email }
+
+type PaymentResolver struct {
+	p model.PaymentResponse
+}
+
+func (r *PaymentResolver) Transaction() string { return r.p.Transaction }
+func (r *PaymentResolver) Currency() string    { return r.p.Currency }
+func (r *PaymentResolver) Amount() Int64       { return Int64(r.p.Amount) }
+func (r *PaymentResolver) PaymentDt() Int64    { return Int64(r.p.PaymentDT) }
+
+type ItemResolver struct {
+	item model.ItemResponse
+}
+
+func (r *ItemResolver) Rid() string        { return r.item.RID }
+func (r *ItemResolver) Name() string       { return r.item.Name }
+func (r *ItemResolver) Price() Int64       { return Int64(r.item.Price) }
+func (r *ItemResolver) Brand() string      { return r.item.Brand }
+func (r *ItemResolver) Status() int32      { return int32(r.item.Status) }
+func (r *ItemResolver) StatusName() string { return r.item.StatusName }
+
+func (r *ItemResolver) History() []*StatusChangeResolver {
+	history := make([]*StatusChangeResolver, len(r.item.History))
+	for i, h := range r.item.History {
+		history[i] = &StatusChangeResolver{c: h}
+	}
+	return history
+}
+
+type StatusChangeResolver struct {
+	c model.StatusChange
+}
+
+func (r *StatusChangeResolver) Status() int32      { return int32(r.c.Status) }
+func (r *StatusChangeResolver) StatusName() string { return r.c.StatusName }
+func (r *StatusChangeResolver) ChangedAt() graphql.Time {
+	return graphql.Time{Time: r.c.ChangedAt}
+}
@@ -0,0 +1,206 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/usecase"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+const maxFirst = 100
+
+var errInternal = errors.New("internal server error")
+
+type Resolver struct {
+	q      usecase.QueryProvider
+	logger *slog.Logger
+}
+
+type orderArgs struct {
+	UID graphql.ID
+}
+
+func (r *Resolver) Order(ctx context.Context, args orderArgs) (*OrderResolver, error) {
+	order, err := loadersFrom(ctx).orders.Load(ctx, string(args.UID))()
+	if errors.Is(err, apperr.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, r.internal(ctx, "failed to get order", err)
+	}
+
+	return &OrderResolver{
+		preview: model.OrderPreview{
+			OrderUID:    order.OrderUID,
+			TrackNumber: order.TrackNumber,
+			CustomerID:  order.CustomerID,
+			DateCreated: order.DateCreated,
+		},
+		root: r,
+	}, nil
+}
+
+type ordersArgs struct {
+	First  int32
+	After  *string
+	Filter *struct {
+		CustomerID      *string
+		DeliveryService *string
+		From            *graphql.Time
+		To              *graphql.Time
+	}
+}
+
+func (r *Resolver) Orders(ctx context.Context, args ordersArgs) (*ConnectionResolver, error) {
+	var filter model.OrderFilter
+	if f := args.Filter; f != nil {
+		if f.CustomerID != nil {
+			filter.CustomerID = *f.CustomerID
+		}
+		if f.DeliveryService != nil {
+			filter.DeliveryService = *f.DeliveryService
+		}
+		if f.From != nil {
+			filter.From = f.From.Time
+		}
+		if f.To != nil {
+			filter.To = f.To.Time
+		}
+	}
+
+	return r.listOrders(ctx, filter, args.First, args.After)
+}
+
+type customerArgs struct {
+	ID graphql.ID
+}
+
+func (r *Resolver) Customer(args customerArgs) *CustomerResolver {
+	return &CustomerResolver{id: string(args.ID), root: r}
+}
+
+func (r *Resolver) listOrders(ctx context.Context, filter model.OrderFilter, first int32, after *string) (*ConnectionResolver, error) {
+	cursor, err := pageArgs(ctx, first, after)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := r.q.ListOrders(ctx, filter, cursor, int(first))
+	if err != nil {
+		return nil, r.internal(ctx, "failed to list orders", err)
+	}
+
+	return &ConnectionResolver{page: page, root: r}, nil
+}
+
+// pageArgs validates connection arguments and charges the request budget for the
+// orders the connection may return.
+func pageArgs(ctx context.Context, first int32, after *string) (*model.OrderCursor, error) {
+	if first < 1 || first > maxFirst {
+		return nil, errors.New("first must be between 1 and 100")
+	}
+
+	var cursor *model.OrderCursor
+	if after != nil {
+		c, err := model.ParseOrderCursor(*after)
+		if err != nil {
+			return nil, err
+		}
+		cursor = &c
+	}
+
+	if err := loadersFrom(ctx).budget.charge(int(first)); err != nil {
+		return nil, err
+	}
+	return cursor, nil
+}
+
+// internal logs err and hides it from the client, like the HTTP handlers do.
+func (r *Resolver) internal(ctx context.Context, msg string, err error) error {
+	r.logger.ErrorContext(ctx, msg, "err", err)
+	return errInternal
+}
+
+type CustomerResolver struct {
+	id   string
+	root *Resolver
+}
+
+func (c *CustomerResolver) ID() graphql.ID {
+	return graphql.ID(c.id)
+}
+
+type customerOrdersArgs struct {
+	First int32
+	After *string
+}
+
+// Orders goes through the request loader: listing orders with their customers' orders
+// reads the pages of all those customers in one query.
+func (c *CustomerResolver) Orders(ctx context.Context, args customerOrdersArgs) (*ConnectionResolver, error) {
+	if _, err := pageArgs(ctx, args.First, args.After); err != nil {
+		return nil, err
+	}
+
+	key := customerOrdersKey{customerID: c.id, first: int(args.First)}
+	if args.After != nil {
+		key.after = *args.After
+	}
+	page, err := loadersFrom(ctx).customerOrders.Load(ctx, key)()
+	if err != nil {
+		return nil, c.root.internal(ctx, "failed to list customer orders", err)
+	}
+
+	return &ConnectionResolver{page: page, root: c.root}, nil
+}
+
+type ConnectionResolver struct {
+	page *model.OrderPage
+	root *Resolver
+}
+
+func (c *ConnectionResolver) Edges() []*EdgeResolver {
+	edges := make([]*EdgeResolver, len(c.page.Orders))
+	for i, p := range c.page.Orders {
+		edges[i] = &EdgeResolver{node: &OrderResolver{preview: *p, root: c.root}}
+	}
+	return edges
+}
+
+func (c *ConnectionResolver) PageInfo() *PageInfoResolver {
+	info := &PageInfoResolver{hasNextPage: c.page.HasNextPage}
+	if n := len(c.page.Orders); n > 0 {
+		cursor := model.CursorOf(c.page.Orders[n-1]).String()
+		info.endCursor = &cursor
+	}
+	return info
+}
+
+type EdgeResolver struct {
+	node *OrderResolver
+}
+
+func (e *EdgeResolver) Cursor() string {
+	return model.CursorOf(&e.node.preview).String()
+}
+
+func (e *EdgeResolver) Node() *OrderResolver {
+	return e.node
+}
+
+type PageInfoResolver struct {
+	hasNextPage bool
+	endCursor   *string
+}
+
+func (p *PageInfoResolver) HasNextPage() bool {
+	return p.hasNextPage
+}
+
+func (p *PageInfoResolver) EndCursor() *string {
+	return p.endCursor
+}
@@ -0,0 +1,224 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/repository/mocks"
+	"github.com/GkadyrG/L0/backend/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func exec(t *testing.T, repo *mocks.QueryRepository, query string, vars map[string]any) (map[string]any, []string) {
+	q := usecase.NewQuery(repo)
+	schema := NewSchema(q, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	resp := schema.Exec(WithLoaders(context.Background(), q), query, "", vars)
+
+	var errs []string
+	for _, e := range resp.Errors {
+		errs = append(errs, e.Message)
+	}
+	var data map[string]any
+	if resp.Data != nil {
+		require.NoError(t, json.Unmarshal(resp.Data, &data))
+	}
+	return data, errs
+}
+
+func sameUIDs(want ...string) any {
+	slices.Sort(want)
+	return mock.MatchedBy(func(got []string) bool {
+		return slices.Equal(want, slices.Sorted(slices.Values(got)))
+	})
+}
+
+func TestOrders_BatchesNestedFields(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	previews := []*model.OrderPreview{
+		{OrderUID: "a", TrackNumber: "TA", CustomerID: "c1", DateCreated: created},
+		{OrderUID: "b", TrackNumber: "TB", CustomerID: "c1", DateCreated: created.Add(-time.Hour)},
+		{OrderUID: "c", TrackNumber: "TC", CustomerID: "c2", DateCreated: created.Add(-2 * time.Hour)},
+	}
+
+	repo := mocks.NewQueryRepository(t)
+	repo.On("ListOrderPage", mock.Anything, model.OrderFilter{DeliveryService: "meest"}, (*model.OrderCursor)(nil), 3).
+		Return(previews, nil).Once()
+	repo.On("GetOrdersByUIDs", mock.Anything, sameUIDs("a", "b")).Return([]*model.OrderResponse{
+		{OrderUID: "a", DeliveryService: "meest", Payment: model.PaymentResponse{Amount: 1817}},
+		{OrderUID: "b", DeliveryService: "meest", Payment: model.PaymentResponse{Amount: 5000000000}},
+	}, nil).Once()
+	repo.On("GetItemsByOrderUIDs", mock.Anything, sameUIDs("a", "b")).Return(map[string][]model.ItemResponse{
+		"a": {{RID: "r1", Status: model.ItemStatusDelivered, StatusName: "delivered"}},
+	}, nil).Once()
+
+	data, errs := exec(t, repo, `query($first: Int) {
+		orders(first: $first, filter: {deliveryService: "meest"}) {
+			edges { node { uid deliveryService payment { amount } items { rid status } } }
+			pageInfo { hasNextPage endCursor }
+		}
+	}`, map[string]any{"first": 2})
+	require.Empty(t, errs)
+
+	var got struct {
+		Orders struct {
+			Edges []struct {
+				Node struct {
+					UID             string
+					DeliveryService string
+					Payment         struct{ Amount int64 }
+					Items           []struct {
+						Rid    string
+						Status int
+					}
+				}
+			}
+			PageInfo struct {
+				HasNextPage bool
+				EndCursor   string
+			}
+		}
+	}
+	raw, _ := json.Marshal(data)
+	require.NoError(t, json.Unmarshal(raw, &got))
+
+	require.Len(t, got.Orders.Edges, 2)
+	assert.Equal(t, "a", got.Orders.Edges[0].Node.UID)
+	assert.Equal(t, "meest", got.Orders.Edges[0].Node.DeliveryService)
+	assert.Equal(t, int64(1817), got.Orders.Edges[0].Node.Payment.Amount)
+	assert.Equal(t, int64(5000000000), got.Orders.Edges[1].Node.Payment.Amount)
+	assert.Equal(t, "r1", got.Orders.Edges[0].Node.Items[0].Rid)
+	assert.Equal(t, int(model.ItemStatusDelivered), got.Orders.Edges[0].Node.Items[0].Status)
+	assert.Empty(t, got.Orders.Edges[1].Node.Items)
+	assert.True(t, got.Orders.PageInfo.HasNextPage)
+	assert.Equal(t, model.CursorOf(previews[1]).String(), got.Orders.PageInfo.EndCursor)
+}
+
+func TestCustomer_OrdersAfterCursor(t *testing.T) {
+	last := &model.OrderPreview{OrderUID: "b", DateCreated: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	cursor := model.CursorOf(last)
+
+	repo := mocks.NewQueryRepository(t)
+	repo.On("ListCustomerOrderPages", mock.Anything, []string{"c1"}, &cursor, 21).
+		Return([]*model.OrderPreview{{OrderUID: "c", CustomerID: "c1"}}, nil)
+
+	data, errs := exec(t, repo, `query($after: String) {
+		customer(id: "c1") { id orders(after: $after) { edges { node { uid customer { id } } } pageInfo { hasNextPage } } }
+	}`, map[string]any{"after": cursor.String()})
+	require.Empty(t, errs)
+
+	customer := data["customer"].(map[string]any)
+	assert.Equal(t, "c1", customer["id"])
+	orders := customer["orders"].(map[string]any)
+	assert.Len(t, orders["edges"], 1)
+	assert.Equal(t, false, orders["pageInfo"].(map[string]any)["hasNextPage"])
+}
+
+func TestOrders_BatchesCustomerOrders(t *testing.T) {
+	previews := []*model.OrderPreview{
+		{OrderUID: "a", CustomerID: "c1"},
+		{OrderUID: "b", CustomerID: "c2"},
+		{OrderUID: "c", CustomerID: "c1"},
+	}
+
+	repo := mocks.NewQueryRepository(t)
+	repo.On("ListOrderPage", mock.Anything, model.OrderFilter{}, (*model.OrderCursor)(nil), 4).Return(previews, nil).Once()
+	// c1 is asked for twice but loaded once, both customers in one query
+	repo.On("ListCustomerOrderPages", mock.Anything, sameUIDs("c1", "c2"), (*model.OrderCursor)(nil), 2).
+		Return([]*model.OrderPreview{
+			{OrderUID: "a", CustomerID: "c1"},
+			{OrderUID: "c", CustomerID: "c1"},
+			{OrderUID: "b", CustomerID: "c2"},
+		}, nil).Once()
+
+	data, errs := exec(t, repo, `{
+		orders(first: 3) { edges { node { uid customer { orders(first: 1) { edges { node { uid } } pageInfo { hasNextPage } } } } } }
+	}`, nil)
+	require.Empty(t, errs)
+
+	var got struct {
+		Orders struct {
+			Edges []struct {
+				Node struct {
+					Customer struct {
+						Orders struct {
+							Edges []struct {
+								Node struct{ UID string }
+							}
+							PageInfo struct{ HasNextPage bool }
+						}
+					}
+				}
+			}
+		}
+	}
+	raw, err := json.Marshal(data)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(raw, &got))
+
+	require.Len(t, got.Orders.Edges, 3)
+	for i, wantUID := range []string{"a", "b", "a"} {
+		orders := got.Orders.Edges[i].Node.Customer.Orders
+		require.Len(t, orders.Edges, 1)
+		assert.Equal(t, wantUID, orders.Edges[0].Node.UID)
+	}
+	assert.True(t, got.Orders.Edges[0].Node.Customer.Orders.PageInfo.HasNextPage)
+	assert.False(t, got.Orders.Edges[1].Node.Customer.Orders.PageInfo.HasNextPage)
+}
+
+func TestOrders_CostLimit(t *testing.T) {
+	previews := make([]*model.OrderPreview, 100)
+	for i := range previews {
+		previews[i] = &model.OrderPreview{OrderUID: fmt.Sprintf("order-%d", i), CustomerID: fmt.Sprintf("c%d", i)}
+	}
+
+	repo := mocks.NewQueryRepository(t)
+	repo.On("ListOrderPage", mock.Anything, model.OrderFilter{}, (*model.OrderCursor)(nil), 101).Return(previews, nil).Once()
+	// only the customers that still fit into the budget are loaded
+	repo.On("ListCustomerOrderPages", mock.Anything, mock.Anything, (*model.OrderCursor)(nil), 101).
+		Return([]*model.OrderPreview{}, nil).Maybe()
+
+	_, errs := exec(t, repo, `{
+		orders(first: 100) { edges { node { customer { orders(first: 100) { pageInfo { hasNextPage } } } } } }
+	}`, nil)
+
+	assert.NotEmpty(t, errs)
+	assert.Contains(t, errs, "query is too expensive: it may return more than 1000 orders")
+}
+
+func TestOrder_NotFound(t *testing.T) {
+	repo := mocks.NewQueryRepository(t)
+	repo.On("GetOrdersByUIDs", mock.Anything, []string{"missing"}).Return([]*model.OrderResponse{}, nil)
+
+	data, errs := exec(t, repo, `{ order(uid: "missing") { uid } }`, nil)
+
+	assert.Empty(t, errs)
+	assert.Nil(t, data["order"])
+}
+
+func TestOrders_InvalidArguments(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "first too large", query: `{ orders(first: 101) { pageInfo { hasNextPage } } }`, want: "first must be between 1 and 100"},
+		{name: "malformed cursor", query: `{ orders(after: "??") { pageInfo { hasNextPage } } }`, want: "malformed cursor"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, errs := exec(t, mocks.NewQueryRepository(t), tc.query, nil)
+			assert.Equal(t, []string{tc.want}, errs)
+		})
+	}
+}
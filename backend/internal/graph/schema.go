@@ -0,0 +1,56 @@
+package graph
+
+import (
+	_ "embed"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/GkadyrG/L0/backend/internal/usecase"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaSource string
+
+// maxDepth rejects queries nesting deeper than any sensible
+// order → customer → orders → items → history chain.
+const maxDepth = 10
+
+// maxCost is how many orders one request may ask for across all its connections. Each
+// connection is charged its first argument before it runs, so nested lists multiply:
+// 100 orders with customer { orders(first: 20) } would cost 2100.
+const maxCost = 1000
+
+// NewSchema parses the schema and binds it to the resolvers. Every request must run
+// with loaders attached by WithLoaders.
+func NewSchema(q usecase.QueryProvider, logger *slog.Logger) *graphql.Schema {
+	return graphql.MustParseSchema(schemaSource, &Resolver{q: q, logger: logger}, graphql.MaxDepth(maxDepth))
+}
+
+// Int64 is the Int64 scalar.
+type Int64 int64
+
+func (Int64) ImplementsGraphQLType(name string) bool {
+	return name == "Int64"
+}
+
+func (i *Int64) UnmarshalGraphQL(input any) error {
+	switch v := input.(type) {
+	case int32:
+		*i = Int64(v)
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid Int64 %q", v)
+		}
+		*i = Int64(n)
+	default:
+		return fmt.Errorf("wrong type for Int64: %T", v)
+	}
+	return nil
+}
+
+func (i Int64) MarshalJSON() ([]byte, error) {
+	return strconv.AppendInt(nil, int64(i), 10), nil
+}
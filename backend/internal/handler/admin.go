@@ -0,0 +1,203 @@
+package order
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/logger"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/problem"
+	"github.com/GkadyrG/L0/backend/internal/usecase"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type AdminHandler struct {
+	outbox   usecase.OutboxProvider
+	webhooks usecase.WebhookProvider
+	logger   *slog.Logger
+}
+
+func NewAdmin(outbox usecase.OutboxProvider, webhooks usecase.WebhookProvider, logger *slog.Logger) *AdminHandler {
+	return &AdminHandler{outbox: outbox, webhooks: webhooks, logger: logger}
+}
+
+func (h *AdminHandler) OutboxState() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		state, err := h.outbox.State(r.Context())
+		if err != nil {
+			h.logger.ErrorContext(r.Context(), "failed to get outbox state", "err", err)
+			problem.Write(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, state)
+	}
+}
+
+func (h *AdminHandler) CreateWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var in model.WebhookInput
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			problem.Write(w, r, apperr.New(apperr.ErrInvalidArgument, "invalid request body"))
+			return
+		}
+
+		webhook, err := h.webhooks.CreateWebhook(r.Context(), in)
+		if err != nil {
+			h.webhookError(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, webhook)
+	}
+}
+
+func (h *AdminHandler) ListWebhooks() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		webhooks, err := h.webhooks.ListWebhooks(r.Context())
+		if err != nil {
+			h.webhookError(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, webhooks)
+	}
+}
+
+func (h *AdminHandler) GetWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := webhookID(w, r)
+		if !ok {
+			return
+		}
+
+		webhook, err := h.webhooks.GetWebhook(r.Context(), id)
+		if err != nil {
+			h.webhookError(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, webhook)
+	}
+}
+
+func (h *AdminHandler) UpdateWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := webhookID(w, r)
+		if !ok {
+			return
+		}
+
+		var in model.WebhookInput
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			problem.Write(w, r, apperr.New(apperr.ErrInvalidArgument, "invalid request body"))
+			return
+		}
+
+		webhook, err := h.webhooks.UpdateWebhook(r.Context(), id, in)
+		if err != nil {
+			h.webhookError(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, webhook)
+	}
+}
+
+func (h *AdminHandler) DeleteWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := webhookID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := h.webhooks.DeleteWebhook(r.Context(), id); err != nil {
+			h.webhookError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *AdminHandler) ListDeliveries() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := webhookID(w, r)
+		if !ok {
+			return
+		}
+
+		limit, offset, err := parsePagination(r)
+		if err != nil {
+			problem.Write(w, r, err)
+			return
+		}
+
+		deliveries, err := h.webhooks.ListDeliveries(r.Context(), id, limit, offset)
+		if err != nil {
+			h.webhookError(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, deliveries)
+	}
+}
+
+type logLevel struct {
+	Level string `json:"level"`
+}
+
+func (h *AdminHandler) LogLevel() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, logLevel{Level: logger.Level()})
+	}
+}
+
+// SetLogLevel switches the service logger to debug, info, warn or error until the
+// next restart or config reload.
+func (h *AdminHandler) SetLogLevel() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var in logLevel
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			problem.Write(w, r, apperr.New(apperr.ErrInvalidArgument, "invalid request body"))
+			return
+		}
+
+		prev := logger.Level()
+		if err := logger.SetLevel(in.Level); err != nil {
+			problem.Write(w, r, apperr.New(apperr.ErrInvalidArgument, err.Error()))
+			return
+		}
+		h.logger.WarnContext(r.Context(), "log level changed", "from", prev, "to", in.Level)
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, logLevel{Level: logger.Level()})
+	}
+}
+
+func webhookID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id < 1 {
+		problem.Write(w, r, apperr.New(apperr.ErrInvalidArgument, "invalid webhook id"))
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *AdminHandler) webhookError(w http.ResponseWriter, r *http.Request, err error) {
+	if apperr.CodeOf(err) == apperr.CodeInternal {
+		h.logger.ErrorContext(r.Context(), "webhook request failed", "err", err)
+	}
+	problem.Write(w, r, err)
+}
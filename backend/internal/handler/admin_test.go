@@ -0,0 +1,122 @@
+package order
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GkadyrG/L0/backend/internal/logger"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/repository/mocks"
+	"github.com/GkadyrG/L0/backend/internal/usecase"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestAdminHandler(repo *mocks.WebhookRepository) *AdminHandler {
+	return NewAdmin(nil, usecase.NewWebhooks(repo, nil), slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestAdminHandler_CreateWebhook(t *testing.T) {
+	type testCase struct {
+		name       string
+		body       string
+		mockSetup  func(r *mocks.WebhookRepository)
+		wantCode   int
+		assertBody func(t *testing.T, body []byte)
+	}
+
+	tests := []testCase{
+		{
+			name: "generated secret is returned once",
+			body: `{"url":"https://partner.example/hooks","event_types":["order.stored"]}`,
+			mockSetup: func(r *mocks.WebhookRepository) {
+				r.On("CreateWebhook", mock.Anything, "https://partner.example/hooks",
+					mock.MatchedBy(func(s string) bool { return len(s) == 64 }),
+					[]model.EventType{model.EventOrderStored},
+				).Return(&model.Webhook{ID: 1, URL: "https://partner.example/hooks", Enabled: true}, nil)
+			},
+			wantCode: http.StatusCreated,
+			assertBody: func(t *testing.T, body []byte) {
+				var got model.Webhook
+				assert.NoError(t, json.Unmarshal(body, &got))
+				assert.Equal(t, int64(1), got.ID)
+				assert.Len(t, got.Secret, 64)
+			},
+		},
+		{
+			name:     "relative url",
+			body:     `{"url":"/hooks"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "unknown event type",
+			body:     `{"url":"https://partner.example/hooks","event_types":["order.deleted"]}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "short secret",
+			body:     `{"url":"https://partner.example/hooks","secret":"123"}`,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewWebhookRepository(t)
+			if tc.mockSetup != nil {
+				tc.mockSetup(repo)
+			}
+			h := newTestAdminHandler(repo)
+
+			router := chi.NewRouter()
+			router.Post("/api/admin/webhooks", h.CreateWebhook())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/webhooks", strings.NewReader(tc.body))
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.wantCode, rec.Code)
+			if tc.assertBody != nil {
+				tc.assertBody(t, rec.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestAdminHandler_SetLogLevel(t *testing.T) {
+	prev := logger.Level()
+	t.Cleanup(func() { _ = logger.SetLevel(prev) })
+
+	tests := []struct {
+		name      string
+		body      string
+		wantCode  int
+		wantLevel string
+	}{
+		{name: "debug", body: `{"level":"debug"}`, wantCode: http.StatusOK, wantLevel: "debug"},
+		{name: "warn", body: `{"level":"warn"}`, wantCode: http.StatusOK, wantLevel: "warn"},
+		{name: "unknown level", body: `{"level":"trace"}`, wantCode: http.StatusBadRequest, wantLevel: "warn"},
+		{name: "invalid body", body: `level=debug`, wantCode: http.StatusBadRequest, wantLevel: "warn"},
+	}
+
+	h := newTestAdminHandler(nil)
+	router := chi.NewRouter()
+	router.Put("/api/admin/log-level", h.SetLogLevel())
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/admin/log-level", strings.NewReader(tc.body)))
+
+			assert.Equal(t, tc.wantCode, rec.Code)
+			assert.Equal(t, tc.wantLevel, logger.Level())
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package order
+
+import (
+	"encoding/csv"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/problem"
+	"github.com/GkadyrG/L0/backend/internal/usecase"
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultStatsRange = 30 * 24 * time.Hour
+	maxStatsRange     = 366 * 24 * time.Hour
+	defaultTopLimit   = 10
+	maxTopLimit       = 100
+)
+
+type AnalyticsHandler struct {
+	us     usecase.AnalyticsProvider
+	logger *slog.Logger
+}
+
+func NewAnalytics(us usecase.AnalyticsProvider, logger *slog.Logger) *AnalyticsHandler {
+	return &AnalyticsHandler{us: us, logger: logger}
+}
+
+func (h *AnalyticsHandler) OrderStats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tr, err := parseTimeRange(r)
+		if err != nil {
+			problem.Write(w, r, err)
+			return
+		}
+
+		groupBy := model.StatsGroup(r.URL.Query().Get("group_by"))
+		if groupBy == "" {
+			groupBy = model.GroupByDay
+		}
+		if !groupBy.Valid() {
+			problem.Write(w, r, apperr.New(apperr.ErrInvalidArgument, "group_by must be one of day, week, delivery_service, provider, brand, locale"))
+			return
+		}
+
+		stats, err := h.us.OrderStats(ctx, model.StatsQuery{TimeRange: tr, GroupBy: groupBy})
+		if err != nil {
+			h.logger.ErrorContext(r.Context(), "failed to get order stats", "err", err)
+			problem.Write(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, stats)
+	}
+}
+
+func (h *AnalyticsHandler) BasketStats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tr, err := parseTimeRange(r)
+		if err != nil {
+			problem.Write(w, r, err)
+			return
+		}
+
+		stats, err := h.us.BasketStats(ctx, tr)
+		if err != nil {
+			h.logger.ErrorContext(r.Context(), "failed to get basket stats", "err", err)
+			problem.Write(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, stats)
+	}
+}
+
+func (h *AnalyticsHandler) TopReport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		q, err := parseTopQuery(r)
+		if err != nil {
+			problem.Write(w, r, err)
+			return
+		}
+
+		format := r.URL.Query().Get("format")
+		if format != "" && format != "json" && format != "csv" {
+			problem.Write(w, r, apperr.New(apperr.ErrInvalidArgument, "format must be json or csv"))
+			return
+		}
+
+		report, err := h.us.TopReport(ctx, q)
+		if err != nil {
+			h.logger.ErrorContext(r.Context(), "failed to build top report", "err", err)
+			problem.Write(w, r, err)
+			return
+		}
+
+		if format == "csv" {
+			h.writeTopReportCSV(w, r, report)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, report)
+	}
+}
+
+// writeTopReportCSV renders brands and products as one table, distinguished by the kind column.
+func (h *AnalyticsHandler) writeTopReportCSV(w http.ResponseWriter, r *http.Request, report *model.TopReport) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="top-report.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"kind", "rank", "brand", "nm_id", "name", "units", "revenue", "avg_discount"})
+	for i, b := range report.Brands {
+		_ = cw.Write([]string{
+			"brand", strconv.Itoa(i + 1), b.Brand, "", "",
+			strconv.FormatInt(b.Units, 10), strconv.FormatInt(b.Revenue, 10), formatFloat(b.AvgDiscount),
+		})
+	}
+	for i, p := range report.Products {
+		_ = cw.Write([]string{
+			"product", strconv.Itoa(i + 1), p.Brand, strconv.FormatInt(p.NmID, 10), p.Name,
+			strconv.FormatInt(p.Units, 10), strconv.FormatInt(p.Revenue, 10), formatFloat(p.AvgDiscount),
+		})
+	}
+	cw.Flush()
+
+	if err := cw.Error(); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to write top report csv", "err", err)
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 2, 64)
+}
+
+func parseTopQuery(r *http.Request) (model.TopQuery, error) {
+	tr, err := parseTimeRange(r)
+	if err != nil {
+		return model.TopQuery{}, err
+	}
+
+	q := model.TopQuery{TimeRange: tr, Limit: defaultTopLimit, By: model.TopByUnits}
+
+	if v := r.URL.Query().Get("limit"); v != "" {
+		q.Limit, err = strconv.Atoi(v)
+		if err != nil || q.Limit < 1 || q.Limit > maxTopLimit {
+			return q, apperr.New(apperr.ErrInvalidArgument, "limit must be between 1 and 100")
+		}
+	}
+
+	if v := r.URL.Query().Get("by"); v != "" {
+		q.By = model.TopMetric(v)
+		if !q.By.Valid() {
+			return q, apperr.New(apperr.ErrInvalidArgument, "by must be one of units, revenue, discount")
+		}
+	}
+
+	return q, nil
+}
+
+// parseTimeRange reads the half-open [from, to) range from the query string.
+// Both bounds accept RFC 3339 timestamps or plain dates. By default the range
+// covers the last 30 days up to the end of the current UTC day, so repeated
+// requests share the same cache key.
+func parseTimeRange(r *http.Request) (model.TimeRange, error) {
+	var tr model.TimeRange
+	var err error
+
+	tr.To = time.Now().UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	if v := r.URL.Query().Get("to"); v != "" {
+		if tr.To, err = parseTime(v); err != nil {
+			return tr, apperr.New(apperr.ErrInvalidArgument, "to must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+		}
+	}
+
+	tr.From = tr.To.Add(-defaultStatsRange)
+	if v := r.URL.Query().Get("from"); v != "" {
+		if tr.From, err = parseTime(v); err != nil {
+			return tr, apperr.New(apperr.ErrInvalidArgument, "from must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+		}
+	}
+
+	if !tr.From.Before(tr.To) {
+		return tr, apperr.New(apperr.ErrInvalidArgument, "from must be before to")
+	}
+	if tr.To.Sub(tr.From) > maxStatsRange {
+		return tr, apperr.New(apperr.ErrInvalidArgument, "time range must not exceed 366 days")
+	}
+
+	return tr, nil
+}
+
+func parseTime(v string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	return t.UTC(), err
+}
@@ -0,0 +1,122 @@
+package order
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/repository/mocks"
+	"github.com/GkadyrG/L0/backend/internal/usecase"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestAnalyticsHandler(repo *mocks.AnalyticsRepository) *AnalyticsHandler {
+	uc := usecase.NewAnalytics(repo)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewAnalytics(uc, logger)
+}
+
+func TestAnalyticsHandler_OrderStats(t *testing.T) {
+	type testCase struct {
+		name       string
+		query      string
+		mockSetup  func(r *mocks.AnalyticsRepository)
+		wantCode   int
+		assertBody func(t *testing.T, body []byte)
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	buckets := []model.StatsBucket{{Key: "meest", Orders: 3, Revenue: 4500}}
+
+	tests := []testCase{
+		{
+			name:  "grouped by delivery service",
+			query: "?from=2024-01-01&to=2024-02-01&group_by=delivery_service",
+			mockSetup: func(r *mocks.AnalyticsRepository) {
+				q := model.StatsQuery{
+					TimeRange: model.TimeRange{From: from, To: to},
+					GroupBy:   model.GroupByDeliveryService,
+				}
+				r.On("OrderStats", mock.Anything, q).Return(buckets, nil)
+			},
+			wantCode: http.StatusOK,
+			assertBody: func(t *testing.T, body []byte) {
+				var got model.OrderStats
+				assert.NoError(t, json.Unmarshal(body, &got))
+				assert.Equal(t, model.GroupByDeliveryService, got.GroupBy)
+				assert.Equal(t, buckets, got.Buckets)
+			},
+		},
+		{
+			name:     "unknown grouping",
+			query:    "?group_by=color",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "inverted range",
+			query:    "?from=2024-02-01&to=2024-01-01",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "malformed date",
+			query:    "?from=yesterday",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewAnalyticsRepository(t)
+			if tc.mockSetup != nil {
+				tc.mockSetup(repo)
+			}
+			h := newTestAnalyticsHandler(repo)
+
+			router := chi.NewRouter()
+			router.Get("/api/analytics/orders", h.OrderStats())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/analytics/orders"+tc.query, nil)
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.wantCode, rec.Code)
+			if tc.assertBody != nil {
+				tc.assertBody(t, rec.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestAnalyticsHandler_TopReportCSV(t *testing.T) {
+	repo := mocks.NewAnalyticsRepository(t)
+	repo.On("TopBrands", mock.Anything, mock.MatchedBy(func(q model.TopQuery) bool {
+		return q.Limit == 2 && q.By == model.TopByRevenue
+	})).Return([]model.BrandTop{{Brand: "Acme", Units: 4, Revenue: 1200, AvgDiscount: 12.5}}, nil)
+	repo.On("TopProducts", mock.Anything, mock.Anything).
+		Return([]model.ProductTop{{NmID: 2389212, Name: "Mascaras", Brand: "Acme", Units: 4, Revenue: 1200}}, nil)
+
+	h := newTestAnalyticsHandler(repo)
+
+	router := chi.NewRouter()
+	router.Get("/api/reports/top", h.TopReport())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/reports/top?limit=2&by=revenue&format=csv", nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "kind,rank,brand,nm_id,name,units,revenue,avg_discount\n"+
+		"brand,1,Acme,,,4,1200,12.50\n"+
+		"product,1,Acme,2389212,Mascaras,4,1200,0.00\n", rec.Body.String())
+}
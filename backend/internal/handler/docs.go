@@ -0,0 +1,75 @@
+package order
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http"
+
+	"github.com/GkadyrG/L0/backend/api"
+	"github.com/pkg/errors"
+)
+
+// swaggerUI is the exact Swagger UI release the docs page loads. It is pinned so that
+// a new upload to the CDN cannot change what runs on the page.
+const swaggerUI = "https://unpkg.com/swagger-ui-dist@5.17.14/"
+
+const docsScript = `window.ui = SwaggerUIBundle({ url: '/api/openapi.json', dom_id: '#swagger-ui' });`
+
+// docsPage renders /api/openapi.json with Swagger UI.
+var docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>L0 Order Service API</title>
+  <link rel="stylesheet" href="` + swaggerUI + `swagger-ui.css" crossorigin="anonymous">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="` + swaggerUI + `swagger-ui-bundle.js" crossorigin="anonymous"></script>
+  <script>` + docsScript + `</script>
+</body>
+</html>
+`
+
+// docsPolicy lets the page run only the pinned release and its own inline script.
+var docsPolicy = func() string {
+	sum := sha256.Sum256([]byte(docsScript))
+	return "default-src 'none'; " +
+		"script-src " + swaggerUI + " 'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'; " +
+		"style-src " + swaggerUI + " 'unsafe-inline'; " +
+		"img-src 'self' data:; connect-src 'self'"
+}()
+
+type DocsHandler struct {
+	spec []byte
+}
+
+func NewDocs() (*DocsHandler, error) {
+	doc, err := api.LoadSpec()
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal openapi spec")
+	}
+
+	return &DocsHandler{spec: spec}, nil
+}
+
+func (h *DocsHandler) Spec() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(h.spec)
+	}
+}
+
+func (h *DocsHandler) Page() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Content-Security-Policy", docsPolicy)
+		io.WriteString(w, docsPage)
+	}
+}
@@ -0,0 +1,33 @@
+package order
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocsHandler_Page(t *testing.T) {
+	h, err := NewDocs()
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	h.Page().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/docs", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.NotContains(t, body, "swagger-ui-dist@5/", "the Swagger UI release must be pinned")
+
+	// the inline script must be the one allowed by the policy, or the page stays blank
+	inline := regexp.MustCompile(`<script>(.*)</script>`).FindStringSubmatch(body)
+	require.Len(t, inline, 2)
+	sum := sha256.Sum256([]byte(inline[1]))
+	policy := rec.Header().Get("Content-Security-Policy")
+	assert.Contains(t, policy, "'sha256-"+base64.StdEncoding.EncodeToString(sum[:])+"'")
+	assert.Contains(t, policy, "script-src "+swaggerUI+" ")
+}
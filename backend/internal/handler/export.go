@@ -0,0 +1,96 @@
+package order
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/export"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/problem"
+)
+
+// Export streams orders matching the list filters. The file headers go out with
+// its first byte, so a failure before that is answered with a problem response.
+// After it the status can no longer change: the connection is aborted so that the
+// client sees a failed download instead of a truncated file.
+func (h *Handler) Export() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		format, err := export.ParseFormat(r.URL.Query().Get("format"))
+		if err != nil {
+			problem.Write(w, r, apperr.New(apperr.ErrInvalidArgument, "format must be one of csv, ndjson, xlsx"))
+			return
+		}
+
+		filter, err := parseOrderFilter(r)
+		if err != nil {
+			problem.Write(w, r, err)
+			return
+		}
+
+		resp := &exportResponse{
+			ResponseWriter: w,
+			format:         format,
+			filename:       fmt.Sprintf("orders-%s.%s", time.Now().UTC().Format("20060102-150405"), format),
+		}
+		rows := 0
+		fail := func(msg string, err error) {
+			h.logger.ErrorContext(r.Context(), msg, "err", err, "rows", rows)
+			if !resp.started {
+				problem.Write(w, r, err)
+				return
+			}
+			panic(http.ErrAbortHandler)
+		}
+
+		writer, err := export.NewWriter(format, resp)
+		if err != nil {
+			fail("failed to create export writer", err)
+			return
+		}
+		defer writer.Abort()
+
+		err = h.us.ExportOrders(ctx, filter, func(row *model.ExportRow) error {
+			rows++
+			return writer.Write(row)
+		})
+		if err != nil {
+			fail("failed to export orders", err)
+			return
+		}
+
+		if err := writer.Close(); err != nil {
+			fail("failed to finish export", err)
+			return
+		}
+		resp.start()
+
+		h.logger.InfoContext(r.Context(), "orders exported", "format", format, "rows", rows)
+	}
+}
+
+// exportResponse sets the file headers and the status on the first write.
+type exportResponse struct {
+	http.ResponseWriter
+	format   export.Format
+	filename string
+	started  bool
+}
+
+func (e *exportResponse) Write(b []byte) (int, error) {
+	e.start()
+	return e.ResponseWriter.Write(b)
+}
+
+func (e *exportResponse) start() {
+	if e.started {
+		return
+	}
+	e.started = true
+	e.Header().Set("Content-Type", e.format.ContentType())
+	e.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", e.filename))
+	e.WriteHeader(http.StatusOK)
+}
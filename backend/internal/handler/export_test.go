@@ -0,0 +1,129 @@
+package order
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/problem"
+	"github.com/GkadyrG/L0/backend/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// exportRows makes the mocked ExportOrders deliver n rows before it returns.
+func exportRows(n int) func(mock.Arguments) {
+	return func(args mock.Arguments) {
+		fn := args.Get(2).(func(*model.ExportRow) error)
+		for i := range n {
+			if fn(&model.ExportRow{Order: model.Order{OrderUID: fmt.Sprintf("order-%d", i), TrackNumber: "TRK"}}) != nil {
+				return
+			}
+		}
+	}
+}
+
+func TestHandler_Export(t *testing.T) {
+	type testCase struct {
+		name      string
+		query     string
+		mockSetup func(r *mocks.OrderRepository)
+		wantCode  int
+		wantType  string
+		wantError string
+	}
+
+	tests := []testCase{
+		{
+			name:  "csv",
+			query: "?format=csv",
+			mockSetup: func(r *mocks.OrderRepository) {
+				r.On("ExportOrders", mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(exportRows(2))
+			},
+			wantCode: http.StatusOK,
+			wantType: "text/csv; charset=utf-8",
+		},
+		{
+			name:  "empty ndjson",
+			query: "?format=ndjson",
+			mockSetup: func(r *mocks.OrderRepository) {
+				r.On("ExportOrders", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			},
+			wantCode: http.StatusOK,
+			wantType: "application/x-ndjson",
+		},
+		{
+			name:      "unknown format",
+			query:     "?format=pdf",
+			wantCode:  http.StatusBadRequest,
+			wantError: "format must be one of csv, ndjson, xlsx",
+		},
+		{
+			name:  "store unavailable before the first row",
+			query: "?format=csv",
+			mockSetup: func(r *mocks.OrderRepository) {
+				r.On("ExportOrders", mock.Anything, mock.Anything, mock.Anything).
+					Return(apperr.New(apperr.ErrUnavailable, "storage is unavailable"))
+			},
+			wantCode:  http.StatusServiceUnavailable,
+			wantError: "storage is unavailable",
+		},
+		{
+			name:  "timeout while the rows are buffered",
+			query: "?format=csv",
+			mockSetup: func(r *mocks.OrderRepository) {
+				r.On("ExportOrders", mock.Anything, mock.Anything, mock.Anything).
+					Return(apperr.New(apperr.ErrTimeout, "request timed out")).Run(exportRows(2))
+			},
+			wantCode:  http.StatusGatewayTimeout,
+			wantError: "request timed out",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewOrderRepository(t)
+			if tc.mockSetup != nil {
+				tc.mockSetup(repo)
+			}
+			h := newTestHandler(repo)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/orders/export"+tc.query, nil)
+			rec := httptest.NewRecorder()
+
+			h.Export().ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.wantCode, rec.Code)
+			if tc.wantError == "" {
+				assert.Equal(t, tc.wantType, rec.Header().Get("Content-Type"))
+				assert.Contains(t, rec.Header().Get("Content-Disposition"), "attachment")
+				return
+			}
+
+			assert.Empty(t, rec.Header().Get("Content-Disposition"))
+			var p problem.Problem
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+			assert.Equal(t, tc.wantError, p.Detail)
+		})
+	}
+}
+
+func TestHandler_Export_AbortsAfterFirstByte(t *testing.T) {
+	repo := mocks.NewOrderRepository(t)
+	// enough rows to overflow the csv buffer before the failure
+	repo.On("ExportOrders", mock.Anything, mock.Anything, mock.Anything).
+		Return(apperr.New(apperr.ErrTimeout, "request timed out")).Run(exportRows(200))
+	h := newTestHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/orders/export?format=csv", nil)
+	rec := httptest.NewRecorder()
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() { h.Export().ServeHTTP(rec, req) })
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEmpty(t, rec.Body.Bytes())
+}
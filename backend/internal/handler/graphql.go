@@ -0,0 +1,52 @@
+package order
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/graph"
+	"github.com/GkadyrG/L0/backend/internal/problem"
+	"github.com/GkadyrG/L0/backend/internal/usecase"
+	"github.com/go-chi/render"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+type GraphQLHandler struct {
+	schema *graphql.Schema
+	q      usecase.QueryProvider
+	logger *slog.Logger
+}
+
+func NewGraphQL(q usecase.QueryProvider, logger *slog.Logger) *GraphQLHandler {
+	return &GraphQLHandler{schema: graph.NewSchema(q, logger), q: q, logger: logger}
+}
+
+type graphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Query executes a GraphQL request. Errors in the query itself are reported in the
+// errors field of a 200 response, as GraphQL clients expect.
+func (h *GraphQLHandler) Query() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			problem.Write(w, r, apperr.New(apperr.ErrInvalidArgument, "invalid request body"))
+			return
+		}
+		if req.Query == "" {
+			problem.Write(w, r, apperr.New(apperr.ErrInvalidArgument, "query is required"))
+			return
+		}
+
+		ctx := graph.WithLoaders(r.Context(), h.q)
+		resp := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
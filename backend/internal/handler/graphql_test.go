@@ -0,0 +1,75 @@
+package order
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/repository/mocks"
+	"github.com/GkadyrG/L0/backend/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGraphQLHandler_Query(t *testing.T) {
+	type testCase struct {
+		name      string
+		body      string
+		mockSetup func(r *mocks.QueryRepository)
+		wantCode  int
+		wantBody  string
+	}
+
+	tests := []testCase{
+		{
+			name: "query",
+			body: `{"query": "query($uid: ID!) { order(uid: $uid) { uid trackNumber } }", "variables": {"uid": "order-1"}}`,
+			mockSetup: func(r *mocks.QueryRepository) {
+				r.On("GetOrdersByUIDs", mock.Anything, []string{"order-1"}).
+					Return([]*model.OrderResponse{{OrderUID: "order-1", TrackNumber: "TRK123"}}, nil)
+			},
+			wantCode: http.StatusOK,
+			wantBody: `{"data":{"order":{"uid":"order-1","trackNumber":"TRK123"}}}`,
+		},
+		{
+			name:     "schema violation",
+			body:     `{"query": "{ order(uid: \"order-1\") { color } }"}`,
+			wantCode: http.StatusOK,
+			wantBody: `"errors"`,
+		},
+		{
+			name:     "malformed body",
+			body:     `{"query": `,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "empty query",
+			body:     `{}`,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewQueryRepository(t)
+			if tc.mockSetup != nil {
+				tc.mockSetup(repo)
+			}
+			h := NewGraphQL(usecase.NewQuery(repo), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/graphql", strings.NewReader(tc.body))
+			rec := httptest.NewRecorder()
+
+			h.Query()(rec, req)
+
+			assert.Equal(t, tc.wantCode, rec.Code)
+			if tc.wantBody != "" {
+				assert.Contains(t, rec.Body.String(), tc.wantBody)
+			}
+		})
+	}
+}
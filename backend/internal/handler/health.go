@@ -0,0 +1,39 @@
+package order
+
+import (
+	"net/http"
+
+	"github.com/GkadyrG/L0/backend/internal/health"
+	"github.com/go-chi/render"
+)
+
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+func NewHealth(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{checker: checker}
+}
+
+// Live answers as long as the process serves HTTP; it does not look at dependencies.
+func (h *HealthHandler) Live() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, map[string]string{"status": health.StatusUp})
+	}
+}
+
+// Ready runs the dependency checks and answers 503 unless all of them pass.
+func (h *HealthHandler) Ready() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := h.checker.Check(r.Context())
+
+		w.Header().Set("Cache-Control", "no-store")
+		if report.Ready() {
+			render.Status(r, http.StatusOK)
+		} else {
+			render.Status(r, http.StatusServiceUnavailable)
+		}
+		render.JSON(w, r, report)
+	}
+}
@@ -0,0 +1,53 @@
+package order
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthHandler_Ready(t *testing.T) {
+	tests := []struct {
+		name     string
+		kafkaErr error
+		drain    bool
+		wantCode int
+		wantDown []string
+	}{
+		{name: "ready", wantCode: http.StatusOK},
+		{name: "dependency down", kafkaErr: errors.New("consumer group is rebalancing"), wantCode: http.StatusServiceUnavailable, wantDown: []string{"kafka"}},
+		{name: "draining", drain: true, wantCode: http.StatusServiceUnavailable, wantDown: []string{"server"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			checker := health.New(time.Second)
+			checker.Add("postgres", func(context.Context) error { return nil })
+			checker.Add("kafka", func(context.Context) error { return tc.kafkaErr })
+			if tc.drain {
+				checker.Drain()
+			}
+
+			rec := httptest.NewRecorder()
+			NewHealth(checker).Ready()(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			require.Equal(t, tc.wantCode, rec.Code)
+
+			var report health.Report
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+			assert.Equal(t, tc.wantCode == http.StatusOK, report.Ready())
+			assert.Equal(t, health.StatusUp, report.Components["postgres"].Status)
+			for _, name := range tc.wantDown {
+				assert.Equal(t, health.StatusDown, report.Components[name].Status, name)
+				assert.NotEmpty(t, report.Components[name].Error, name)
+			}
+		})
+	}
+}
@@ -1,16 +1,23 @@
 package order
 
 import (
-	"errors"
 	"log/slog"
 	"net/http"
+	"strconv"
 
 	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/logger"
+	"github.com/GkadyrG/L0/backend/internal/model"
 	"github.com/GkadyrG/L0/backend/internal/usecase"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 )
 
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
 type Handler struct {
 	us     usecase.OrderProvider
 	logger *slog.Logger
@@ -25,19 +32,12 @@ func (h *Handler) GetByID() http.HandlerFunc {
 		ctx := r.Context()
 
 		id := chi.URLParam(r, "id")
+		ctx = logger.With(ctx, "order_uid", id)
 		order, err := h.us.GetByID(ctx, id)
 
 		if err != nil {
-			h.logger.Error("failed to get order", "err", err)
-
-			if errors.Is(err, apperr.ErrNotFound) {
-				render.Status(r, http.StatusNotFound)
-				render.JSON(w, r, map[string]string{"error": "order not found"})
-				return
-			}
-
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, map[string]string{"error": "internal server error"})
+			h.logger.ErrorContext(ctx, "failed to get order", "err", err)
+			writeV1Error(w, r, err)
 			return
 		}
 
@@ -51,18 +51,16 @@ func (h *Handler) GetAll() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
-		ordersPreview, err := h.us.GetAll(ctx)
+		filter, err := parseOrderFilter(r)
 		if err != nil {
-			h.logger.Error("failed to get all orders preview", "err", err)
-
-			if errors.Is(err, apperr.ErrNotFound) {
-				render.Status(r, http.StatusNotFound)
-				render.JSON(w, r, map[string]string{"error": "orders preview not found"})
-				return
-			}
+			writeV1Error(w, r, err)
+			return
+		}
 
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, map[string]string{"error": "internal server error"})
+		ordersPreview, err := h.us.GetAll(ctx, filter)
+		if err != nil {
+			h.logger.ErrorContext(ctx, "failed to get all orders preview", "err", err)
+			writeV1Error(w, r, err)
 			return
 		}
 
@@ -70,3 +68,129 @@ func (h *Handler) GetAll() http.HandlerFunc {
 		render.JSON(w, r, ordersPreview)
 	}
 }
+
+func (h *Handler) GetTimeline() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		id := chi.URLParam(r, "id")
+		ctx = logger.With(ctx, "order_uid", id)
+		timeline, err := h.us.GetTimeline(ctx, id)
+
+		if err != nil {
+			h.logger.ErrorContext(ctx, "failed to get order timeline", "err", err)
+			writeV1Error(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, timeline)
+	}
+}
+
+func (h *Handler) GetByTrackNumber() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		track := chi.URLParam(r, "track")
+		ctx = logger.With(ctx, "track_number", track)
+		order, err := h.us.GetByTrackNumber(ctx, track)
+
+		if err != nil {
+			h.logger.ErrorContext(ctx, "failed to get order by track number", "err", err)
+			writeV1Error(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, order)
+	}
+}
+
+func (h *Handler) ListByCustomer() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		limit, offset, err := parsePagination(r)
+		if err != nil {
+			writeV1Error(w, r, err)
+			return
+		}
+
+		customerID := chi.URLParam(r, "id")
+		ctx = logger.With(ctx, "customer_id", customerID)
+		previews, err := h.us.ListByCustomer(ctx, customerID, limit, offset)
+		if err != nil {
+			h.logger.ErrorContext(ctx, "failed to list customer orders", "err", err)
+			writeV1Error(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, previews)
+	}
+}
+
+func parsePagination(r *http.Request) (limit, offset int, err error) {
+	limit, offset = defaultPageLimit, 0
+
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			return 0, 0, apperr.New(apperr.ErrInvalidArgument, "limit must be between 1 and 100")
+		}
+	}
+
+	if v := r.URL.Query().Get("offset"); v != "" {
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return 0, 0, apperr.New(apperr.ErrInvalidArgument, "offset must be a non-negative integer")
+		}
+	}
+
+	return limit, offset, nil
+}
+
+// parseOrderFilter reads the filters shared by the order list and export endpoints.
+func parseOrderFilter(r *http.Request) (model.OrderFilter, error) {
+	q := r.URL.Query()
+	filter := model.OrderFilter{
+		CustomerID:      q.Get("customer_id"),
+		DeliveryService: q.Get("delivery_service"),
+	}
+
+	var err error
+	if v := q.Get("from"); v != "" {
+		if filter.From, err = parseTime(v); err != nil {
+			return filter, apperr.New(apperr.ErrInvalidArgument, "from must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+		}
+	}
+	if v := q.Get("to"); v != "" {
+		if filter.To, err = parseTime(v); err != nil {
+			return filter, apperr.New(apperr.ErrInvalidArgument, "to must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+		}
+	}
+
+	return filter, nil
+}
+
+// writeV1Error answers a v1 request. v1 is frozen, so it keeps the {"error": "..."}
+// body it had before problem responses. An unavailable store and a timeout are 503
+// and 504 as everywhere else; any other unclassified failure is a 500.
+func writeV1Error(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusInternalServerError
+	msg := "internal server error"
+	switch apperr.CodeOf(err) {
+	case apperr.CodeNotFound:
+		status, msg = http.StatusNotFound, apperr.Message(err)
+	case apperr.CodeInvalidArgument:
+		status, msg = http.StatusBadRequest, apperr.Message(err)
+	case apperr.CodeUnavailable:
+		status, msg = http.StatusServiceUnavailable, "service unavailable"
+	case apperr.CodeTimeout:
+		status, msg = http.StatusGatewayTimeout, "request timed out"
+	}
+
+	render.Status(r, status)
+	render.JSON(w, r, map[string]string{"error": msg})
+}
@@ -79,7 +79,7 @@ func TestHandler_GetByID(t *testing.T) {
 			name: "not found",
 			id:   "missing",
 			mockSetup: func(r *mocks.OrderRepository) {
-				r.On("GetByID", mock.Anything, "missing").Return((*model.OrderResponse)(nil), apperr.ErrNotFound)
+				r.On("GetByID", mock.Anything, "missing").Return((*model.OrderResponse)(nil), apperr.New(apperr.ErrNotFound, "order not found"))
 			},
 			wantCode: http.StatusNotFound,
 			assertBody: func(t *testing.T, body []byte) {
@@ -101,6 +101,32 @@ func TestHandler_GetByID(t *testing.T) {
 				assert.Equal(t, "internal server error", m["error"])
 			},
 		},
+		{
+			name: "store unavailable",
+			id:   "down",
+			mockSetup: func(r *mocks.OrderRepository) {
+				r.On("GetByID", mock.Anything, "down").Return((*model.OrderResponse)(nil), apperr.New(apperr.ErrUnavailable, "storage is unavailable"))
+			},
+			wantCode: http.StatusServiceUnavailable,
+			assertBody: func(t *testing.T, body []byte) {
+				var m map[string]string
+				assert.NoError(t, json.Unmarshal(body, &m))
+				assert.Equal(t, "service unavailable", m["error"])
+			},
+		},
+		{
+			name: "timeout",
+			id:   "slow",
+			mockSetup: func(r *mocks.OrderRepository) {
+				r.On("GetByID", mock.Anything, "slow").Return((*model.OrderResponse)(nil), apperr.New(apperr.ErrTimeout, "query timed out"))
+			},
+			wantCode: http.StatusGatewayTimeout,
+			assertBody: func(t *testing.T, body []byte) {
+				var m map[string]string
+				assert.NoError(t, json.Unmarshal(body, &m))
+				assert.Equal(t, "request timed out", m["error"])
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -127,6 +153,163 @@ func TestHandler_GetByID(t *testing.T) {
 	}
 }
 
+func TestHandler_GetTimeline(t *testing.T) {
+	type testCase struct {
+		name       string
+		id         string
+		mockSetup  func(r *mocks.OrderRepository)
+		wantCode   int
+		assertBody func(t *testing.T, body []byte)
+	}
+
+	created := time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC)
+	shipped := created.Add(48 * time.Hour)
+	order := &model.OrderResponse{
+		OrderUID:    "order-1",
+		DateCreated: created,
+		Payment:     model.PaymentResponse{PaymentDT: created.Add(time.Hour).Unix()},
+		Items: []model.ItemResponse{{
+			RID:    "rid-1",
+			Status: model.ItemStatusShipped,
+			History: []model.StatusChange{
+				model.NewStatusChange(model.ItemStatusCreated, created),
+				model.NewStatusChange(model.ItemStatusShipped, shipped),
+			},
+		}},
+	}
+
+	tests := []testCase{
+		{
+			name: "success",
+			id:   "order-1",
+			mockSetup: func(r *mocks.OrderRepository) {
+				r.On("GetByID", mock.Anything, "order-1").Return(order, nil)
+			},
+			wantCode: http.StatusOK,
+			assertBody: func(t *testing.T, body []byte) {
+				var got model.OrderTimeline
+				assert.NoError(t, json.Unmarshal(body, &got))
+				assert.Equal(t, "order-1", got.OrderUID)
+				assert.Equal(t, model.OrderStatusShipped, got.Status)
+				assert.True(t, shipped.Equal(got.UpdatedAt))
+
+				var types []string
+				for _, e := range got.Events {
+					types = append(types, e.Type)
+				}
+				assert.Equal(t, []string{model.EventOrderCreated, model.EventPaymentReceived, model.EventItemStatusChanged}, types)
+			},
+		},
+		{
+			name: "not found",
+			id:   "missing",
+			mockSetup: func(r *mocks.OrderRepository) {
+				r.On("GetByID", mock.Anything, "missing").Return((*model.OrderResponse)(nil), apperr.New(apperr.ErrNotFound, "order not found"))
+			},
+			wantCode: http.StatusNotFound,
+			assertBody: func(t *testing.T, body []byte) {
+				var m map[string]string
+				assert.NoError(t, json.Unmarshal(body, &m))
+				assert.Equal(t, "order not found", m["error"])
+			},
+		},
+		{
+			name: "internal error",
+			id:   "boom",
+			mockSetup: func(r *mocks.OrderRepository) {
+				r.On("GetByID", mock.Anything, "boom").Return((*model.OrderResponse)(nil), assert.AnError)
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewOrderRepository(t)
+			if tc.mockSetup != nil {
+				tc.mockSetup(repo)
+			}
+			h := newTestHandler(repo)
+
+			router := chi.NewRouter()
+			router.Get("/api/order/{id}/timeline", h.GetTimeline())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/order/"+tc.id+"/timeline", nil)
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.wantCode, rec.Code)
+			if tc.assertBody != nil {
+				tc.assertBody(t, rec.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestHandler_GetByTrackNumber(t *testing.T) {
+	type testCase struct {
+		name      string
+		track     string
+		mockSetup func(r *mocks.OrderRepository)
+		wantCode  int
+		wantUID   string
+	}
+
+	tests := []testCase{
+		{
+			name:  "success",
+			track: "TRK123",
+			mockSetup: func(r *mocks.OrderRepository) {
+				r.On("GetByTrackNumber", mock.Anything, "TRK123").Return(&model.OrderResponse{OrderUID: "order-1", TrackNumber: "TRK123"}, nil)
+			},
+			wantCode: http.StatusOK,
+			wantUID:  "order-1",
+		},
+		{
+			name:  "not found",
+			track: "missing",
+			mockSetup: func(r *mocks.OrderRepository) {
+				r.On("GetByTrackNumber", mock.Anything, "missing").Return((*model.OrderResponse)(nil), apperr.New(apperr.ErrNotFound, "order not found"))
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:  "internal error",
+			track: "boom",
+			mockSetup: func(r *mocks.OrderRepository) {
+				r.On("GetByTrackNumber", mock.Anything, "boom").Return((*model.OrderResponse)(nil), assert.AnError)
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewOrderRepository(t)
+			if tc.mockSetup != nil {
+				tc.mockSetup(repo)
+			}
+			h := newTestHandler(repo)
+
+			router := chi.NewRouter()
+			router.Get("/api/orders/by-track/{track}", h.GetByTrackNumber())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/orders/by-track/"+tc.track, nil)
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.wantCode, rec.Code)
+			if tc.wantUID != "" {
+				var got model.OrderResponse
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+				assert.Equal(t, tc.wantUID, got.OrderUID)
+			}
+		})
+	}
+}
+
 func TestHandler_GetAll(t *testing.T) {
 	type testCase struct {
 		name       string
@@ -147,7 +330,7 @@ func TestHandler_GetAll(t *testing.T) {
 		{
 			name: "success",
 			mockSetup: func(r *mocks.OrderRepository) {
-				r.On("GetAll", mock.Anything).Return(preview, nil)
+				r.On("GetAll", mock.Anything, model.OrderFilter{}).Return(preview, nil)
 			},
 			wantCode: http.StatusOK,
 			assertBody: func(t *testing.T, body []byte) {
@@ -165,7 +348,7 @@ func TestHandler_GetAll(t *testing.T) {
 		{
 			name: "not found",
 			mockSetup: func(r *mocks.OrderRepository) {
-				r.On("GetAll", mock.Anything).Return(([]*model.OrderPreview)(nil), apperr.ErrNotFound)
+				r.On("GetAll", mock.Anything, model.OrderFilter{}).Return(([]*model.OrderPreview)(nil), apperr.New(apperr.ErrNotFound, "orders preview not found"))
 			},
 			wantCode: http.StatusNotFound,
 			assertBody: func(t *testing.T, body []byte) {
@@ -177,7 +360,7 @@ func TestHandler_GetAll(t *testing.T) {
 		{
 			name: "internal error",
 			mockSetup: func(r *mocks.OrderRepository) {
-				r.On("GetAll", mock.Anything).Return(([]*model.OrderPreview)(nil), assert.AnError)
+				r.On("GetAll", mock.Anything, model.OrderFilter{}).Return(([]*model.OrderPreview)(nil), assert.AnError)
 			},
 			wantCode: http.StatusInternalServerError,
 			assertBody: func(t *testing.T, body []byte) {
@@ -211,3 +394,67 @@ func TestHandler_GetAll(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_ListByCustomer(t *testing.T) {
+	type testCase struct {
+		name      string
+		query     string
+		mockSetup func(r *mocks.OrderRepository)
+		wantCode  int
+	}
+
+	preview := []*model.OrderPreview{{
+		OrderUID:    "order-1",
+		TrackNumber: "TRK123",
+		CustomerID:  "cust-1",
+	}}
+
+	tests := []testCase{
+		{
+			name:  "default pagination",
+			query: "",
+			mockSetup: func(r *mocks.OrderRepository) {
+				r.On("ListByCustomer", mock.Anything, "cust-1", defaultPageLimit, 0).Return(preview, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:  "custom pagination",
+			query: "?limit=5&offset=10",
+			mockSetup: func(r *mocks.OrderRepository) {
+				r.On("ListByCustomer", mock.Anything, "cust-1", 5, 10).Return(preview, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "limit too large",
+			query:    "?limit=1000",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "negative offset",
+			query:    "?offset=-1",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewOrderRepository(t)
+			if tc.mockSetup != nil {
+				tc.mockSetup(repo)
+			}
+			h := newTestHandler(repo)
+
+			router := chi.NewRouter()
+			router.Get("/api/customers/{id}/orders", h.ListByCustomer())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/customers/cust-1/orders"+tc.query, nil)
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.wantCode, rec.Code)
+		})
+	}
+}
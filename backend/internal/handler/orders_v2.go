@@ -0,0 +1,161 @@
+package order
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/logger"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/problem"
+	"github.com/GkadyrG/L0/backend/internal/usecase"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// V2Handler serves the order endpoints of API v2. Every response is wrapped in an
+// envelope: {"data": ...}, plus "pagination" for listings.
+type V2Handler struct {
+	orders usecase.OrderProvider
+	q      usecase.QueryProvider
+	logger *slog.Logger
+}
+
+func NewV2(orders usecase.OrderProvider, q usecase.QueryProvider, logger *slog.Logger) *V2Handler {
+	return &V2Handler{orders: orders, q: q, logger: logger}
+}
+
+type envelope struct {
+	Data       any         `json:"data"`
+	Pagination *pagination `json:"pagination,omitempty"`
+}
+
+type pagination struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+func (h *V2Handler) GetByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		r = r.WithContext(logger.With(r.Context(), "order_uid", id))
+		details, err := h.q.OrderDetails(r.Context(), id)
+		if err != nil {
+			h.orderError(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, envelope{Data: details.ToV2()})
+	}
+}
+
+func (h *V2Handler) GetByTrackNumber() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		track := chi.URLParam(r, "track")
+		r = r.WithContext(logger.With(r.Context(), "track_number", track))
+		details, err := h.q.OrderDetailsByTrack(r.Context(), track)
+		if err != nil {
+			h.orderError(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, envelope{Data: details.ToV2()})
+	}
+}
+
+func (h *V2Handler) GetTimeline() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		r = r.WithContext(logger.With(r.Context(), "order_uid", id))
+		timeline, err := h.orders.GetTimeline(r.Context(), id)
+		if err != nil {
+			h.orderError(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, envelope{Data: timeline})
+	}
+}
+
+func (h *V2Handler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := parseOrderFilter(r)
+		if err != nil {
+			problem.Write(w, r, err)
+			return
+		}
+
+		h.listOrders(w, r, filter)
+	}
+}
+
+func (h *V2Handler) ListByCustomer() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.listOrders(w, r, model.OrderFilter{CustomerID: chi.URLParam(r, "id")})
+	}
+}
+
+func (h *V2Handler) listOrders(w http.ResponseWriter, r *http.Request, filter model.OrderFilter) {
+	limit, after, err := parseCursorPagination(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	page, err := h.q.ListOrders(r.Context(), filter, after, limit)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to list orders", "err", err)
+		problem.Write(w, r, err)
+		return
+	}
+
+	orders := page.Orders
+	if orders == nil {
+		orders = []*model.OrderPreview{}
+	}
+	p := &pagination{Limit: limit, HasMore: page.HasNextPage}
+	if page.HasNextPage {
+		p.NextCursor = model.CursorOf(orders[len(orders)-1]).String()
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, envelope{Data: orders, Pagination: p})
+}
+
+func (h *V2Handler) orderError(w http.ResponseWriter, r *http.Request, err error) {
+	if !errors.Is(err, apperr.ErrNotFound) {
+		h.logger.ErrorContext(r.Context(), "failed to get order", "err", err)
+	}
+	problem.Write(w, r, err)
+}
+
+// parseCursorPagination reads limit and the opaque cursor returned as next_cursor
+// by the previous page.
+func parseCursorPagination(r *http.Request) (int, *model.OrderCursor, error) {
+	q := r.URL.Query()
+
+	limit := defaultPageLimit
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxPageLimit {
+			return 0, nil, apperr.New(apperr.ErrInvalidArgument, "limit must be between 1 and 100")
+		}
+		limit = n
+	}
+
+	v := q.Get("cursor")
+	if v == "" {
+		return limit, nil, nil
+	}
+	cursor, err := model.ParseOrderCursor(v)
+	if err != nil {
+		return 0, nil, apperr.New(apperr.ErrInvalidArgument, "invalid cursor")
+	}
+	return limit, &cursor, nil
+}
@@ -0,0 +1,236 @@
+package order
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/problem"
+	"github.com/GkadyrG/L0/backend/internal/repository/mocks"
+	"github.com/GkadyrG/L0/backend/internal/usecase"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestV2Router(query *mocks.QueryRepository) *chi.Mux {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := NewV2(usecase.New(&mocks.OrderRepository{}), usecase.NewQuery(query), logger)
+
+	router := chi.NewRouter()
+	router.Get("/api/v2/orders", h.List())
+	router.Get("/api/v2/orders/{id}", h.GetByID())
+	router.Get("/api/v2/customers/{id}/orders", h.ListByCustomer())
+	return router
+}
+
+func TestV2Handler_GetByID(t *testing.T) {
+	type testCase struct {
+		name       string
+		id         string
+		mockSetup  func(r *mocks.QueryRepository)
+		wantCode   int
+		assertBody func(t *testing.T, body []byte)
+	}
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	details := &model.OrderDetails{
+		Order: model.Order{
+			OrderUID:    "order-1",
+			TrackNumber: "TRK123",
+			Entry:       "WBIL",
+			SmID:        99,
+			DateCreated: created,
+			Payment: model.Payment{
+				Currency:     "USD",
+				Amount:       1817,
+				PaymentDT:    created.Unix(),
+				DeliveryCost: 1500,
+				GoodsTotal:   317,
+			},
+			Items: []model.Item{{
+				ChrtID:     9934930,
+				RID:        "rid-1",
+				Price:      453,
+				Sale:       30,
+				TotalPrice: 317,
+				Status:     model.ItemStatusPaid,
+			}},
+		},
+		History: map[string][]model.StatusChange{
+			"rid-1": {
+				model.NewStatusChange(model.ItemStatusCreated, created),
+				model.NewStatusChange(model.ItemStatusPaid, created.Add(time.Hour)),
+			},
+		},
+	}
+
+	tests := []testCase{
+		{
+			name: "success",
+			id:   "order-1",
+			mockSetup: func(r *mocks.QueryRepository) {
+				r.On("GetOrderDetails", mock.Anything, "order-1").Return(details, nil)
+			},
+			wantCode: http.StatusOK,
+			assertBody: func(t *testing.T, body []byte) {
+				var got struct {
+					Data model.OrderV2 `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(body, &got))
+
+				assert.Equal(t, "WBIL", got.Data.Entry)
+				assert.Equal(t, 99, got.Data.SmID)
+				assert.Equal(t, model.Money{Amount: 1817, Currency: "USD"}, got.Data.Payment.Amount)
+				assert.Equal(t, model.Money{Amount: 1500, Currency: "USD"}, got.Data.Payment.DeliveryCost)
+				require.NotNil(t, got.Data.Payment.PaidAt)
+				assert.True(t, created.Equal(*got.Data.Payment.PaidAt))
+
+				require.Len(t, got.Data.Items, 1)
+				item := got.Data.Items[0]
+				assert.Equal(t, int64(9934930), item.ChrtID)
+				assert.Equal(t, model.Money{Amount: 317, Currency: "USD"}, item.TotalPrice)
+				assert.Equal(t, "paid", item.StatusName)
+				assert.Len(t, item.History, 2)
+			},
+		},
+		{
+			name: "not found",
+			id:   "missing",
+			mockSetup: func(r *mocks.QueryRepository) {
+				r.On("GetOrderDetails", mock.Anything, "missing").Return(nil, apperr.New(apperr.ErrNotFound, "order not found"))
+			},
+			wantCode: http.StatusNotFound,
+			assertBody: func(t *testing.T, body []byte) {
+				var p problem.Problem
+				assert.NoError(t, json.Unmarshal(body, &p))
+				assert.Equal(t, "order not found", p.Detail)
+			},
+		},
+		{
+			name: "internal error",
+			id:   "boom",
+			mockSetup: func(r *mocks.QueryRepository) {
+				r.On("GetOrderDetails", mock.Anything, "boom").Return(nil, assert.AnError)
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			query := mocks.NewQueryRepository(t)
+			tc.mockSetup(query)
+
+			rec := httptest.NewRecorder()
+			newTestV2Router(query).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v2/orders/"+tc.id, nil))
+
+			assert.Equal(t, tc.wantCode, rec.Code)
+			if tc.assertBody != nil {
+				tc.assertBody(t, rec.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestV2Handler_List(t *testing.T) {
+	type testCase struct {
+		name       string
+		target     string
+		mockSetup  func(r *mocks.QueryRepository)
+		wantCode   int
+		wantBody   string
+		wantCursor bool
+	}
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	previews := []*model.OrderPreview{
+		{OrderUID: "order-2", DateCreated: created.Add(time.Hour)},
+		{OrderUID: "order-1", DateCreated: created},
+	}
+
+	tests := []testCase{
+		{
+			name:   "last page",
+			target: "/api/v2/orders?delivery_service=meest",
+			mockSetup: func(r *mocks.QueryRepository) {
+				r.On("ListOrderPage", mock.Anything, model.OrderFilter{DeliveryService: "meest"}, (*model.OrderCursor)(nil), 21).
+					Return(previews, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:   "has more",
+			target: "/api/v2/orders?limit=1",
+			mockSetup: func(r *mocks.QueryRepository) {
+				r.On("ListOrderPage", mock.Anything, model.OrderFilter{}, (*model.OrderCursor)(nil), 2).Return(previews, nil)
+			},
+			wantCode:   http.StatusOK,
+			wantCursor: true,
+		},
+		{
+			name:   "customer orders after cursor",
+			target: "/api/v2/customers/cust-1/orders?cursor=" + model.CursorOf(previews[0]).String(),
+			mockSetup: func(r *mocks.QueryRepository) {
+				after := model.CursorOf(previews[0])
+				r.On("ListOrderPage", mock.Anything, model.OrderFilter{CustomerID: "cust-1"}, mock.MatchedBy(func(c *model.OrderCursor) bool {
+					return c != nil && c.OrderUID == after.OrderUID && c.DateCreated.Equal(after.DateCreated)
+				}), 21).Return(previews[1:], nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "invalid cursor",
+			target:   "/api/v2/orders?cursor=!",
+			wantCode: http.StatusBadRequest,
+			wantBody: "invalid cursor",
+		},
+		{
+			name:     "limit out of range",
+			target:   "/api/v2/orders?limit=0",
+			wantCode: http.StatusBadRequest,
+			wantBody: "limit must be between 1 and 100",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			query := mocks.NewQueryRepository(t)
+			if tc.mockSetup != nil {
+				tc.mockSetup(query)
+			}
+
+			rec := httptest.NewRecorder()
+			newTestV2Router(query).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.target, nil))
+			require.Equal(t, tc.wantCode, rec.Code)
+
+			if tc.wantCode != http.StatusOK {
+				var p problem.Problem
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+				assert.Equal(t, tc.wantBody, p.Detail)
+				return
+			}
+
+			var got struct {
+				Data       []model.OrderPreview `json:"data"`
+				Pagination pagination           `json:"pagination"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.NotEmpty(t, got.Data)
+			assert.Equal(t, tc.wantCursor, got.Pagination.HasMore)
+			if tc.wantCursor {
+				last := got.Data[len(got.Data)-1]
+				assert.Equal(t, model.CursorOf(&last).String(), got.Pagination.NextCursor)
+			} else {
+				assert.Empty(t, got.Pagination.NextCursor)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package order
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/feed"
+	"github.com/GkadyrG/L0/backend/internal/problem"
+)
+
+const (
+	// sseRetry tells EventSource clients how long to wait before reconnecting.
+	sseRetry = 3 * time.Second
+	// sseWriteWait bounds a single write, so a stalled client does not hold the stream.
+	sseWriteWait = 10 * time.Second
+)
+
+type FeedHandler struct {
+	hub       *feed.Hub
+	heartbeat time.Duration
+	logger    *slog.Logger
+}
+
+func NewFeed(hub *feed.Hub, heartbeat time.Duration, logger *slog.Logger) *FeedHandler {
+	return &FeedHandler{hub: hub, heartbeat: heartbeat, logger: logger}
+}
+
+// Stream pushes previews of newly saved orders as Server-Sent Events. Clients resume
+// with the Last-Event-ID header (sent by EventSource on reconnect) or the
+// last_event_id query parameter; a comment line is sent as a heartbeat.
+func (h *FeedHandler) Stream() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		filter := feed.Filter{
+			CustomerID:      q.Get("customer_id"),
+			DeliveryService: q.Get("delivery_service"),
+		}
+
+		lastID, err := parseLastEventID(r)
+		if err != nil {
+			problem.Write(w, r, err)
+			return
+		}
+
+		sub, replay := h.hub.Subscribe(filter, lastID)
+		defer sub.Close()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		// The stream outlives the server read and write timeouts; only single writes are bounded.
+		rc := http.NewResponseController(w)
+		_ = rc.SetReadDeadline(time.Time{})
+		_ = rc.SetWriteDeadline(time.Now().Add(sseWriteWait))
+		fmt.Fprintf(w, "retry: %d\n\n", sseRetry.Milliseconds())
+		for _, e := range replay {
+			if err := writeSSE(w, e); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			h.logger.ErrorContext(r.Context(), "streaming is not supported", "err", err)
+			return
+		}
+
+		ticker := time.NewTicker(h.heartbeat)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case e, ok := <-sub.C:
+				if !ok {
+					return
+				}
+				_ = rc.SetWriteDeadline(time.Now().Add(sseWriteWait))
+				if err := writeSSE(w, e); err != nil {
+					return
+				}
+			case <-ticker.C:
+				_ = rc.SetWriteDeadline(time.Now().Add(sseWriteWait))
+				if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+					return
+				}
+			}
+
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func parseLastEventID(r *http.Request) (*uint64, error) {
+	v := r.Header.Get("Last-Event-ID")
+	if v == "" {
+		v = r.URL.Query().Get("last_event_id")
+	}
+	if v == "" {
+		return nil, nil
+	}
+
+	id, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return nil, apperr.New(apperr.ErrInvalidArgument, fmt.Sprintf("invalid last event id %q", v))
+	}
+	return &id, nil
+}
+
+func writeSSE(w io.Writer, e feed.Event) error {
+	data, err := json.Marshal(e.Preview)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: order\ndata: %s\n\n", e.EventID(), data)
+	return err
+}
@@ -0,0 +1,88 @@
+package order
+
+import (
+	"bufio"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/feed"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readEvent reads lines up to the next blank line, skipping the retry preamble.
+func readEvent(t *testing.T, r *bufio.Reader) []string {
+	t.Helper()
+	for {
+		var lines []string
+		for {
+			line, err := r.ReadString('\n')
+			require.NoError(t, err)
+			line = strings.TrimSuffix(line, "\n")
+			if line == "" {
+				break
+			}
+			lines = append(lines, line)
+		}
+		if !strings.HasPrefix(lines[0], "retry:") {
+			return lines
+		}
+	}
+}
+
+func TestFeedHandler_Stream(t *testing.T) {
+	hub := feed.NewHub(10)
+	h := NewFeed(hub, 20*time.Millisecond, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	router := chi.NewRouter()
+	router.Get("/api/orders/stream", h.Stream())
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	hub.OrderSaved(&model.OrderResponse{OrderUID: "order-1", CustomerID: "c1", DeliveryService: "meest"})
+	hub.OrderSaved(&model.OrderResponse{OrderUID: "order-2", CustomerID: "c2", DeliveryService: "meest"})
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/api/orders/stream?customer_id=c1", nil)
+	require.NoError(t, err)
+	req.Header.Set("Last-Event-ID", "0")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	r := bufio.NewReader(resp.Body)
+	replayed := readEvent(t, r)
+	require.Len(t, replayed, 3)
+	assert.Equal(t, "event: order", replayed[1])
+	assert.Contains(t, replayed[2], `"order_uid":"order-1"`)
+
+	assert.Equal(t, []string{": heartbeat"}, readEvent(t, r))
+
+	hub.OrderSaved(&model.OrderResponse{OrderUID: "order-3", CustomerID: "c1"})
+	var live []string
+	for live = readEvent(t, r); live[0] == ": heartbeat"; live = readEvent(t, r) {
+	}
+	assert.Contains(t, live[2], `"order_uid":"order-3"`)
+
+	hub.Close()
+	_, err = io.ReadAll(r)
+	assert.NoError(t, err)
+}
+
+func TestFeedHandler_InvalidLastEventID(t *testing.T) {
+	h := NewFeed(feed.NewHub(10), time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/orders/stream?last_event_id=abc", nil)
+	rec := httptest.NewRecorder()
+	h.Stream()(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
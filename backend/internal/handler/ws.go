@@ -0,0 +1,52 @@
+package order
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/GkadyrG/L0/backend/config"
+	"github.com/GkadyrG/L0/backend/internal/origin"
+	"github.com/GkadyrG/L0/backend/internal/ws"
+	"github.com/gorilla/websocket"
+)
+
+type LiveHandler struct {
+	hub      *ws.Hub
+	upgrader websocket.Upgrader
+	logger   *slog.Logger
+}
+
+// NewLive accepts cross-origin connections only from the origins allowed by the
+// CORS settings; with CORS disabled only same-origin pages may connect.
+func NewLive(cfg *config.Config, origins *origin.Allowlist, hub *ws.Hub, logger *slog.Logger) *LiveHandler {
+	upgrader := websocket.Upgrader{}
+	if cfg.Cors.Enabled {
+		upgrader.CheckOrigin = func(r *http.Request) bool {
+			o := r.Header.Get("Origin")
+			if o == "" || origins.Allowed(o) {
+				return true
+			}
+			u, err := url.Parse(o)
+			return err == nil && u.Host == r.Host
+		}
+	}
+
+	return &LiveHandler{hub: hub, upgrader: upgrader, logger: logger}
+}
+
+// Subscribe upgrades the request to a WebSocket. Orders listed in order_uid query
+// parameters are subscribed right away; more can be added with
+// {"action":"subscribe","order_uids":[...]} messages.
+func (h *LiveHandler) Subscribe() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := h.upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// Upgrade has already written the error response.
+			h.logger.DebugContext(r.Context(), "websocket upgrade failed", "err", err)
+			return
+		}
+
+		h.hub.Serve(conn, r.URL.Query()["order_uid"])
+	}
+}
@@ -0,0 +1,130 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+	// StatusDegraded marks a component failing with a Degraded error, and a report
+	// whose only failures are such. A degraded report is still ready.
+	StatusDegraded = "degraded"
+)
+
+// ErrDraining is reported while the server shuts down, so traffic moves elsewhere
+// before the listeners close.
+var ErrDraining = errors.New("server is shutting down")
+
+// Check reports whether a dependency is usable; a nil error means it is.
+type Check func(ctx context.Context) error
+
+type Component struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status     string               `json:"status"`
+	Components map[string]Component `json:"components"`
+}
+
+// Ready reports whether every required component is up.
+func (r *Report) Ready() bool {
+	return r.Status != StatusDown
+}
+
+// Degraded marks err as a failure the service keeps serving through.
+func Degraded(err error) error {
+	return &degradedError{err: err}
+}
+
+type degradedError struct {
+	err error
+}
+
+func (e *degradedError) Error() string { return e.err.Error() }
+
+func (e *degradedError) Unwrap() error { return e.err }
+
+type named struct {
+	name  string
+	check Check
+}
+
+// Checker runs the registered checks for /readyz. Checks run concurrently and each
+// gets at most timeout.
+type Checker struct {
+	timeout  time.Duration
+	checks   []named
+	draining atomic.Bool
+}
+
+func New(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add registers a check. It must not be called once the checker is serving.
+func (c *Checker) Add(name string, check Check) {
+	c.checks = append(c.checks, named{name: name, check: check})
+}
+
+// Drain marks the server as shutting down; every later report is not ready.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+func (c *Checker) Check(ctx context.Context) *Report {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	report := &Report{Status: StatusUp, Components: make(map[string]Component, len(c.checks)+1)}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, n := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			component := componentOf(n.check(ctx))
+			mu.Lock()
+			report.Components[n.name] = component
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if c.draining.Load() {
+		report.Components["server"] = componentOf(ErrDraining)
+	}
+
+	for _, component := range report.Components {
+		switch component.Status {
+		case StatusDown:
+			report.Status = StatusDown
+		case StatusDegraded:
+			if report.Status == StatusUp {
+				report.Status = StatusDegraded
+			}
+		}
+	}
+	return report
+}
+
+func componentOf(err error) Component {
+	var degraded *degradedError
+	switch {
+	case err == nil:
+		return Component{Status: StatusUp}
+	case errors.As(err, &degraded):
+		return Component{Status: StatusDegraded, Error: err.Error()}
+	default:
+		return Component{Status: StatusDown, Error: err.Error()}
+	}
+}
@@ -0,0 +1,102 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker_Check(t *testing.T) {
+	up := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("connection refused") }
+	degraded := func(context.Context) error { return Degraded(errors.New("consumer group is rebalancing")) }
+	slow := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	tests := []struct {
+		name         string
+		checks       map[string]Check
+		drain        bool
+		wantReady    bool
+		wantStatus   string
+		wantDown     map[string]string
+		wantDegraded map[string]string
+	}{
+		{
+			name:      "all up",
+			checks:    map[string]Check{"postgres": up, "kafka": up},
+			wantReady: true,
+		},
+		{
+			name:     "one down",
+			checks:   map[string]Check{"postgres": down, "kafka": up},
+			wantDown: map[string]string{"postgres": "connection refused"},
+		},
+		{
+			name:         "degraded",
+			checks:       map[string]Check{"postgres": up, "kafka": degraded},
+			wantReady:    true,
+			wantStatus:   StatusDegraded,
+			wantDegraded: map[string]string{"kafka": "consumer group is rebalancing"},
+		},
+		{
+			name:         "down wins over degraded",
+			checks:       map[string]Check{"postgres": down, "kafka": degraded},
+			wantStatus:   StatusDown,
+			wantDown:     map[string]string{"postgres": "connection refused"},
+			wantDegraded: map[string]string{"kafka": "consumer group is rebalancing"},
+		},
+		{
+			name:     "check times out",
+			checks:   map[string]Check{"postgres": slow},
+			wantDown: map[string]string{"postgres": context.DeadlineExceeded.Error()},
+		},
+		{
+			name:     "draining",
+			checks:   map[string]Check{"postgres": up},
+			drain:    true,
+			wantDown: map[string]string{"server": ErrDraining.Error()},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			checker := New(20 * time.Millisecond)
+			for name, check := range tc.checks {
+				checker.Add(name, check)
+			}
+			if tc.drain {
+				checker.Drain()
+			}
+
+			report := checker.Check(context.Background())
+			assert.Equal(t, tc.wantReady, report.Ready())
+			if tc.wantStatus != "" {
+				assert.Equal(t, tc.wantStatus, report.Status)
+			}
+
+			for name, component := range report.Components {
+				if msg, ok := tc.wantDegraded[name]; ok {
+					assert.Equal(t, Component{Status: StatusDegraded, Error: msg}, component, name)
+					continue
+				}
+				msg, isDown := tc.wantDown[name]
+				if !isDown {
+					assert.Equal(t, Component{Status: StatusUp}, component, name)
+					continue
+				}
+				assert.Equal(t, StatusDown, component.Status, name)
+				assert.Equal(t, msg, component.Error, name)
+			}
+			for name := range tc.wantDown {
+				require.Contains(t, report.Components, name)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package importer
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// Checkpoint records how many input records have been fully processed, so an
+// interrupted import can continue where it stopped. It is only reused for the
+// same source; any other source starts from the beginning.
+type Checkpoint struct {
+	path string
+
+	Source  string `json:"source"`
+	Records int    `json:"records"`
+	// Report is the size of the rejection report when the checkpoint was saved.
+	// Lines past it belong to records a resumed run processes again.
+	Report int64 `json:"report"`
+}
+
+func LoadCheckpoint(path, source string) (*Checkpoint, error) {
+	cp := &Checkpoint{path: path, Source: source}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "read checkpoint")
+	}
+
+	var saved Checkpoint
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, errors.Wrap(err, "parse checkpoint")
+	}
+	if saved.Source == source {
+		cp.Records = saved.Records
+		cp.Report = saved.Report
+	}
+
+	return cp, nil
+}
+
+// Save atomically replaces the checkpoint file.
+func (c *Checkpoint) Save(records int, report int64) error {
+	c.Records = records
+	c.Report = report
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return errors.Wrap(err, "write checkpoint")
+	}
+	return errors.Wrap(os.Rename(tmp, c.path), "replace checkpoint")
+}
+
+func (c *Checkpoint) Remove() error {
+	err := os.Remove(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
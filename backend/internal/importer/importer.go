@@ -0,0 +1,278 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/validate"
+	"github.com/pkg/errors"
+)
+
+const maxLineSize = 16 << 20
+
+type BatchSaver interface {
+	SaveBatch(ctx context.Context, orders []*model.Order) ([]string, error)
+}
+
+type Options struct {
+	BatchSize int
+	// Checkpoint persists progress between runs. It may be nil.
+	Checkpoint *Checkpoint
+	// Report receives one JSON line per rejected record. It may be nil. On resume it
+	// must already be cut to Checkpoint.Report bytes, the part the checkpoint covers.
+	Report io.Writer
+}
+
+type Result struct {
+	Records    int `json:"records"`
+	Imported   int `json:"imported"`
+	Duplicates int `json:"duplicates"`
+	Rejected   int `json:"rejected"`
+	Resumed    int `json:"resumed"`
+}
+
+type Rejection struct {
+	Record   int    `json:"record"`
+	OrderUID string `json:"order_uid,omitempty"`
+	Error    string `json:"error"`
+}
+
+type pending struct {
+	record int
+	order  *model.Order
+}
+
+type Importer struct {
+	saver  BatchSaver
+	logger *slog.Logger
+	opts   Options
+	// reported is the size of the report written so far, saved with the checkpoint.
+	reported int64
+}
+
+func New(saver BatchSaver, logger *slog.Logger, opts Options) *Importer {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 500
+	}
+	return &Importer{saver: saver, logger: logger, opts: opts}
+}
+
+// Import reads orders from r, either as NDJSON or as a single JSON array. Records are
+// numbered from 1; for NDJSON the record number is the line number. Records already
+// covered by the checkpoint are skipped.
+func (im *Importer) Import(ctx context.Context, r io.Reader) (*Result, error) {
+	res := &Result{}
+	if im.opts.Checkpoint != nil {
+		res.Resumed = im.opts.Checkpoint.Records
+		im.reported = im.opts.Checkpoint.Report
+		if res.Resumed > 0 {
+			im.logger.Info("resuming import", "records", res.Resumed)
+		}
+	}
+
+	br := bufio.NewReader(r)
+	first, err := peekNonSpace(br)
+	if err == io.EOF {
+		return res, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "read input")
+	}
+
+	started := time.Now()
+	batch := make([]pending, 0, im.opts.BatchSize)
+
+	emit := func(record int, raw []byte) error {
+		res.Records = record
+		if record <= res.Resumed {
+			return nil
+		}
+
+		var order model.Order
+		if err := json.Unmarshal(raw, &order); err != nil {
+			return im.reject(res, Rejection{Record: record, Error: err.Error()})
+		}
+		if err := validate.ValidateOrder(order); err != nil {
+			return im.reject(res, Rejection{Record: record, OrderUID: order.OrderUID, Error: err.Error()})
+		}
+
+		batch = append(batch, pending{record: record, order: &order})
+		if len(batch) < im.opts.BatchSize {
+			return nil
+		}
+		if err := im.flush(ctx, res, batch); err != nil {
+			return err
+		}
+		batch = batch[:0]
+		im.logProgress(res, started)
+		return nil
+	}
+
+	if first == '[' {
+		err = readArray(br, emit)
+	} else {
+		err = readLines(br, emit)
+	}
+	if err != nil {
+		return res, err
+	}
+
+	if err := im.flush(ctx, res, batch); err != nil {
+		return res, err
+	}
+	im.logProgress(res, started)
+
+	return res, nil
+}
+
+// flush saves a batch and advances the checkpoint past the current record. If the batch
+// is rejected as a whole, records are retried one by one so a single bad record does
+// not block the rest. Retryable errors such as an unavailable database stop the import
+// without moving the checkpoint, so a rerun picks the batch up again.
+func (im *Importer) flush(ctx context.Context, res *Result, batch []pending) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	orders := make([]*model.Order, len(batch))
+	for i, p := range batch {
+		orders[i] = p.order
+	}
+
+	stored, err := im.saver.SaveBatch(ctx, orders)
+	if err == nil {
+		res.Imported += len(stored)
+		res.Duplicates += len(batch) - len(stored)
+		return im.checkpoint(res.Records)
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if apperr.CodeOf(err).Retryable() {
+		return errors.Wrap(err, "save batch")
+	}
+
+	im.logger.Warn("batch failed, retrying records one by one", "err", err, "size", len(batch))
+	for _, p := range batch {
+		stored, err := im.saver.SaveBatch(ctx, []*model.Order{p.order})
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if apperr.CodeOf(err).Retryable() {
+				return errors.Wrapf(err, "save record %d", p.record)
+			}
+			if err := im.reject(res, Rejection{Record: p.record, OrderUID: p.order.OrderUID, Error: err.Error()}); err != nil {
+				return err
+			}
+			continue
+		}
+		res.Imported += len(stored)
+		res.Duplicates += 1 - len(stored)
+	}
+
+	return im.checkpoint(res.Records)
+}
+
+func (im *Importer) checkpoint(record int) error {
+	if im.opts.Checkpoint == nil {
+		return nil
+	}
+	return im.opts.Checkpoint.Save(record, im.reported)
+}
+
+func (im *Importer) reject(res *Result, rej Rejection) error {
+	res.Rejected++
+	if im.opts.Report == nil {
+		return nil
+	}
+	b, err := json.Marshal(rej)
+	if err != nil {
+		return err
+	}
+	n, err := im.opts.Report.Write(append(b, '\n'))
+	im.reported += int64(n)
+	return errors.Wrap(err, "write error report")
+}
+
+func (im *Importer) logProgress(res *Result, started time.Time) {
+	elapsed := time.Since(started).Seconds()
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(res.Records-res.Resumed) / elapsed
+	}
+	im.logger.Info("import progress",
+		"records", res.Records,
+		"imported", res.Imported,
+		"duplicates", res.Duplicates,
+		"rejected", res.Rejected,
+		"records_per_sec", int(rate),
+	)
+}
+
+// peekNonSpace returns the first non-whitespace byte without consuming input,
+// so NDJSON line numbers stay intact.
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for n := 1; ; n++ {
+		buf, err := br.Peek(n)
+		if len(buf) < n {
+			return 0, err
+		}
+		switch b := buf[n-1]; b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		default:
+			return b, nil
+		}
+	}
+}
+
+func readLines(r io.Reader, emit func(int, []byte) error) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	line := 0
+	for sc.Scan() {
+		line++
+		raw := bytes.TrimSpace(sc.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		if err := emit(line, raw); err != nil {
+			return err
+		}
+	}
+
+	return errors.Wrap(sc.Err(), "read ndjson")
+}
+
+func readArray(r io.Reader, emit func(int, []byte) error) error {
+	dec := json.NewDecoder(r)
+	if _, err := dec.Token(); err != nil {
+		return errors.Wrap(err, "read json array")
+	}
+
+	record := 0
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return errors.Wrapf(err, "decode record %d", record+1)
+		}
+		record++
+		if err := emit(record, raw); err != nil {
+			return err
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return errors.Wrap(err, "read json array")
+	}
+	return nil
+}
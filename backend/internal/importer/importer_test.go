@@ -0,0 +1,214 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSaver struct {
+	stored  map[string]bool
+	batches int
+	poison  string
+	// down fails every save of an order with this uid as if the database were gone
+	down string
+}
+
+func (f *fakeSaver) SaveBatch(_ context.Context, orders []*model.Order) ([]string, error) {
+	f.batches++
+	for _, o := range orders {
+		if o.OrderUID == f.down {
+			return nil, apperr.New(apperr.ErrUnavailable, "database unavailable")
+		}
+	}
+	for _, o := range orders {
+		if o.OrderUID == f.poison {
+			return nil, fmt.Errorf("insert order %s: constraint violation", o.OrderUID)
+		}
+	}
+
+	var stored []string
+	for _, o := range orders {
+		if !f.stored[o.OrderUID] {
+			f.stored[o.OrderUID] = true
+			stored = append(stored, o.OrderUID)
+		}
+	}
+	return stored, nil
+}
+
+func validOrder(uid string) model.Order {
+	return model.Order{
+		OrderUID:        uid,
+		TrackNumber:     "TRK",
+		Entry:           "WBIL",
+		Locale:          "en",
+		CustomerID:      "cust",
+		DeliveryService: "meest",
+		ShardKey:        "9",
+		SmID:            99,
+		DateCreated:     time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC),
+		OofShard:        "1",
+		CreatedAt:       time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC),
+		Delivery: model.Delivery{
+			Name: "Test Testov", Phone: "+9720000000", Zip: "2639809",
+			City: "Kiryat Mozkin", Address: "Ploshad Mira 15", Email: "test@gmail.com",
+		},
+		Payment: model.Payment{
+			Transaction: uid, Currency: "USD", Provider: "wbpay", Amount: 1817, PaymentDT: 1637907727,
+		},
+		Items: []model.Item{{
+			ChrtID: 9934930, TrackNumber: "TRK", Price: 453, RID: uid + "-rid", Name: "Mascaras",
+			TotalPrice: 317, Brand: "Vivienne Sabo", Status: model.ItemStatusCreated,
+		}},
+	}
+}
+
+func ndjson(t *testing.T, lines ...any) string {
+	t.Helper()
+
+	var b strings.Builder
+	for _, l := range lines {
+		if s, ok := l.(string); ok {
+			b.WriteString(s)
+		} else {
+			raw, err := json.Marshal(l)
+			require.NoError(t, err)
+			b.Write(raw)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func newTestImporter(saver BatchSaver, opts Options) *Importer {
+	return New(saver, slog.New(slog.NewTextHandler(io.Discard, nil)), opts)
+}
+
+func TestImporter_NDJSONReportsRejectedLines(t *testing.T) {
+	invalid := validOrder("order-3")
+	invalid.Payment.Currency = "DOLLARS"
+
+	input := ndjson(t, validOrder("order-1"), "{not json", invalid, "", validOrder("order-1"), validOrder("order-5"))
+
+	saver := &fakeSaver{stored: map[string]bool{}}
+	var report bytes.Buffer
+	im := newTestImporter(saver, Options{BatchSize: 2, Report: &report})
+
+	res, err := im.Import(context.Background(), strings.NewReader(input))
+	require.NoError(t, err)
+
+	assert.Equal(t, 6, res.Records)
+	assert.Equal(t, 2, res.Imported)
+	assert.Equal(t, 1, res.Duplicates)
+	assert.Equal(t, 2, res.Rejected)
+
+	var lines []Rejection
+	for _, l := range strings.Split(strings.TrimSpace(report.String()), "\n") {
+		var rej Rejection
+		require.NoError(t, json.Unmarshal([]byte(l), &rej))
+		lines = append(lines, rej)
+	}
+	require.Len(t, lines, 2)
+	assert.Equal(t, 2, lines[0].Record)
+	assert.Equal(t, 3, lines[1].Record)
+	assert.Equal(t, "order-3", lines[1].OrderUID)
+}
+
+func TestImporter_JSONArray(t *testing.T) {
+	raw, err := json.Marshal([]model.Order{validOrder("order-1"), validOrder("order-2"), validOrder("order-3")})
+	require.NoError(t, err)
+
+	saver := &fakeSaver{stored: map[string]bool{}}
+	im := newTestImporter(saver, Options{BatchSize: 2})
+
+	res, err := im.Import(context.Background(), bytes.NewReader(raw))
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, res.Imported)
+	assert.Equal(t, 2, saver.batches)
+}
+
+func TestImporter_PoisonRecordFallsBackToSingleSaves(t *testing.T) {
+	input := ndjson(t, validOrder("order-1"), validOrder("order-2"), validOrder("order-3"))
+
+	saver := &fakeSaver{stored: map[string]bool{}, poison: "order-2"}
+	var report bytes.Buffer
+	im := newTestImporter(saver, Options{BatchSize: 3, Report: &report})
+
+	res, err := im.Import(context.Background(), strings.NewReader(input))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, res.Imported)
+	assert.Equal(t, 1, res.Rejected)
+	assert.Contains(t, report.String(), `"record":2`)
+}
+
+func TestImporter_ResumesFromCheckpoint(t *testing.T) {
+	input := ndjson(t, validOrder("order-1"), validOrder("order-2"), validOrder("order-3"), validOrder("order-4"))
+	path := filepath.Join(t.TempDir(), "import.checkpoint")
+
+	cp, err := LoadCheckpoint(path, "orders.ndjson:100")
+	require.NoError(t, err)
+	require.NoError(t, cp.Save(2, 0))
+
+	cp, err = LoadCheckpoint(path, "orders.ndjson:100")
+	require.NoError(t, err)
+	assert.Equal(t, 2, cp.Records)
+
+	saver := &fakeSaver{stored: map[string]bool{}}
+	im := newTestImporter(saver, Options{BatchSize: 10, Checkpoint: cp})
+
+	res, err := im.Import(context.Background(), strings.NewReader(input))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, res.Resumed)
+	assert.Equal(t, 2, res.Imported)
+	assert.False(t, saver.stored["order-1"])
+	assert.True(t, saver.stored["order-4"])
+	assert.Equal(t, 4, cp.Records)
+
+	other, err := LoadCheckpoint(path, "other.ndjson:100")
+	require.NoError(t, err)
+	assert.Equal(t, 0, other.Records)
+}
+
+func TestImporter_UnavailableStoreKeepsCheckpoint(t *testing.T) {
+	input := ndjson(t, validOrder("order-1"), "{not json", validOrder("order-3"), validOrder("order-4"))
+	path := filepath.Join(t.TempDir(), "import.checkpoint")
+	cp, err := LoadCheckpoint(path, "orders.ndjson:100")
+	require.NoError(t, err)
+
+	saver := &fakeSaver{stored: map[string]bool{}, down: "order-4"}
+	var report bytes.Buffer
+	im := newTestImporter(saver, Options{BatchSize: 1, Checkpoint: cp, Report: &report})
+
+	_, err = im.Import(context.Background(), strings.NewReader(input))
+	assert.ErrorIs(t, err, apperr.ErrUnavailable)
+	assert.Equal(t, 3, cp.Records, "the failed record is not checkpointed")
+	assert.Equal(t, int64(report.Len()), cp.Report)
+	assert.Equal(t, 1, strings.Count(report.String(), "\n"), "the unavailable store is not a rejection")
+
+	saver.down = ""
+	cp, err = LoadCheckpoint(path, "orders.ndjson:100")
+	require.NoError(t, err)
+	im = newTestImporter(saver, Options{BatchSize: 1, Checkpoint: cp, Report: &report})
+
+	res, err := im.Import(context.Background(), strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Imported)
+	assert.True(t, saver.stored["order-4"])
+	assert.Equal(t, 1, strings.Count(report.String(), "\n"))
+}
@@ -3,17 +3,53 @@ package consumer
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"hash/fnv"
 	"log/slog"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/GkadyrG/L0/backend/config"
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/logger"
+	"github.com/GkadyrG/L0/backend/internal/metrics"
 	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/tracing"
 	"github.com/GkadyrG/L0/backend/internal/usecase"
 	"github.com/GkadyrG/L0/backend/internal/validate"
 	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const (
+	retryInitialBackoff = 100 * time.Millisecond
+	retryMaxBackoff     = 5 * time.Second
+)
+
+// orderStore is the part of the use case the consumer writes through.
+type orderStore interface {
+	Save(ctx context.Context, order *model.Order) (bool, error)
+	SaveBatch(ctx context.Context, orders []*model.Order) ([]string, error)
+	UpdateItemStatus(ctx context.Context, event *model.ItemStatusEvent) error
+}
+
 type consumerHandler struct {
-	ready chan struct{}
-	uc    *usecase.UseCase
+	// ready is closed once the first group session is set up; active tracks whether
+	// a session is running now, it is cleared while the group rebalances.
+	ready        chan struct{}
+	readyOnce    sync.Once
+	active       atomic.Bool
+	uc           orderStore
+	statusTopic  string
+	batchSize    int
+	batchTimeout time.Duration
+	workers      int
+	logger       *slog.Logger
 }
 
 type Consumer struct {
@@ -22,55 +58,352 @@ type Consumer struct {
 	logger  *slog.Logger
 }
 
-func NewConsumer(brokers []string, groupID string, uc *usecase.UseCase, logger *slog.Logger) (*Consumer, error) {
-	cfg := sarama.NewConfig()
-	cfg.Version = sarama.V2_8_0_0
-	cfg.Consumer.Offsets.Initial = sarama.OffsetOldest
+func NewConsumer(cfg *config.Config, uc usecase.OrderProvider, logger *slog.Logger) (*Consumer, error) {
+	saramaCfg := sarama.NewConfig()
+	saramaCfg.Version = sarama.V2_8_0_0
+	saramaCfg.Consumer.Offsets.Initial = sarama.OffsetOldest
 
-	g, err := sarama.NewConsumerGroup(brokers, groupID, cfg)
+	g, err := sarama.NewConsumerGroup(cfg.GetKafkaBrokers(), cfg.Kafka.KafkaGroupID, saramaCfg)
 	if err != nil {
 		return nil, err
 	}
 
-	h := &consumerHandler{ready: make(chan struct{}), uc: uc}
+	h := &consumerHandler{
+		ready:        make(chan struct{}),
+		uc:           uc,
+		statusTopic:  cfg.Kafka.KafkaStatusTopic,
+		batchSize:    max(cfg.Kafka.BatchSize, 1),
+		batchTimeout: cfg.Kafka.BatchTimeout,
+		workers:      max(cfg.Kafka.Workers, 1),
+		logger:       logger,
+	}
 	return &Consumer{group: g, handler: h, logger: logger}, nil
 }
 
 func (h *consumerHandler) Setup(_ sarama.ConsumerGroupSession) error {
-	close(h.ready)
+	h.active.Store(true)
+	h.readyOnce.Do(func() { close(h.ready) })
 	return nil
 }
 
 func (h *consumerHandler) Cleanup(_ sarama.ConsumerGroupSession) error {
+	h.active.Store(false)
 	return nil
 }
 
 func (h *consumerHandler) ConsumeClaim(sess sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
-	slog.Info("ConsumeClaim started", "topic", claim.Topic(), "partition", claim.Partition())
+	h.logger.Info("ConsumeClaim started", "topic", claim.Topic(), "partition", claim.Partition())
+
+	if claim.Topic() == h.statusTopic {
+		for msg := range claim.Messages() {
+			observeLag(claim, msg)
+			if !h.handleStatusEvent(sess.Context(), msg) {
+				continue
+			}
 
-	for msg := range claim.Messages() {
-		var order model.Order
-		if err := json.Unmarshal(msg.Value, &order); err != nil {
-			slog.Error("Unmarshal failed", "error", err)
-			continue
+			sess.MarkMessage(msg, "")
 		}
+		return nil
+	}
+
+	return h.consumeOrders(sess, claim)
+}
+
+// consumeOrders spreads the partition across workers by message key, so orders with
+// the same order_uid are processed in sequence while different orders proceed in
+// parallel. Offsets are committed up to the lowest one that has not completed yet.
+func (h *consumerHandler) consumeOrders(sess sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	ctx := sess.Context()
+	tracker := newOffsetTracker(func(offset int64) {
+		sess.MarkOffset(claim.Topic(), claim.Partition(), offset+1, "")
+	})
+
+	var wg sync.WaitGroup
+	workers := make([]chan *sarama.ConsumerMessage, h.workers)
+	for i := range workers {
+		workers[i] = make(chan *sarama.ConsumerMessage, h.batchSize)
+		wg.Add(1)
+		go func(in <-chan *sarama.ConsumerMessage) {
+			defer wg.Done()
+			h.runWorker(ctx, in, tracker)
+		}(workers[i])
+	}
 
-		if err := validate.ValidateOrder(order); err != nil {
-			slog.Error("Invalid order", "error", err)
-			continue
+	defer func() {
+		for _, w := range workers {
+			close(w)
 		}
+		wg.Wait()
+	}()
+
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
 
-		if err := h.uc.Save(sess.Context(), &order); err != nil {
-			slog.Error("failed to save order", "error", err)
-			continue
+			observeLag(claim, msg)
+			tracker.Add(msg.Offset)
+			select {
+			case workers[shard(msg.Key, len(workers))] <- msg:
+			case <-ctx.Done():
+				return nil
+			}
+		case <-ctx.Done():
+			return nil
 		}
+	}
+}
 
-		slog.Info("order saved")
+// runWorker accumulates messages until the batch is full or batchTimeout has passed
+// since its first message, then writes the batch in one transaction. Offsets of the
+// batch are reported as done only after it is stored.
+func (h *consumerHandler) runWorker(ctx context.Context, in <-chan *sarama.ConsumerMessage, tracker *offsetTracker) {
+	batch := make([]*sarama.ConsumerMessage, 0, h.batchSize)
+	timer := time.NewTimer(h.batchTimeout)
+	timer.Stop()
+	defer timer.Stop()
 
-		sess.MarkMessage(msg, "")
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if h.saveOrders(ctx, batch) {
+			offsets := make([]int64, len(batch))
+			for i, msg := range batch {
+				offsets[i] = msg.Offset
+			}
+			tracker.Done(offsets...)
+		}
+		batch = batch[:0]
 	}
 
-	return nil
+	for {
+		select {
+		case msg, ok := <-in:
+			if !ok {
+				flush()
+				return
+			}
+
+			batch = append(batch, msg)
+			if len(batch) == 1 {
+				timer.Reset(h.batchTimeout)
+			}
+			if len(batch) >= h.batchSize {
+				timer.Stop()
+				flush()
+			}
+		case <-timer.C:
+			flush()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func observeLag(claim sarama.ConsumerGroupClaim, msg *sarama.ConsumerMessage) {
+	metrics.SetConsumerLag(msg.Topic, msg.Partition, claim.HighWaterMarkOffset()-msg.Offset-1)
+}
+
+func shard(key []byte, n int) int {
+	h := fnv.New32a()
+	h.Write(key)
+	return int(h.Sum32() % uint32(n))
+}
+
+// saveOrders decodes and stores a batch. Messages that cannot be decoded or validated
+// are skipped. If the batch write fails, orders are stored one by one: records rejected
+// by the database are skipped, other errors are retried until the session ends.
+// It reports whether every message was handled and the batch offset may be committed.
+func (h *consumerHandler) saveOrders(ctx context.Context, msgs []*sarama.ConsumerMessage) bool {
+	orders := make([]*model.Order, 0, len(msgs))
+	// orderCtxs[i] carries the span of the message orders[i] was decoded from
+	orderCtxs := make([]context.Context, 0, len(msgs))
+	for _, msg := range msgs {
+		// message spans stay open until their orders are stored
+		msgCtx, span := startProcessSpan(ctx, msg)
+		defer span.End()
+
+		if order, ok := h.decodeOrder(msgCtx, msg); ok {
+			orders = append(orders, order)
+			orderCtxs = append(orderCtxs, logger.With(msgCtx, "order_uid", order.OrderUID))
+		}
+	}
+	if len(orders) == 0 {
+		return true
+	}
+
+	topic := msgs[0].Topic
+	ctx = logger.With(ctx, "topic", topic, "partition", msgs[0].Partition)
+	stored, err := h.saveBatch(ctx, orderCtxs, orders)
+	if err == nil {
+		h.logger.InfoContext(ctx, "orders saved", "count", len(orders), "stored", len(stored))
+		metrics.ConsumerProcessed(topic, len(orders))
+		return true
+	}
+	if ctx.Err() != nil {
+		return false
+	}
+
+	h.logger.WarnContext(ctx, "batch save failed, saving orders one by one", "error", err, "count", len(orders))
+	for i, order := range orders {
+		if !h.saveOrder(orderCtxs[i], topic, order) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// saveBatch stores the orders in one call. A batch of several messages belongs to
+// several traces, so its span is a root linked to each message span.
+func (h *consumerHandler) saveBatch(ctx context.Context, orderCtxs []context.Context, orders []*model.Order) (_ []string, err error) {
+	if len(orders) == 1 {
+		return h.uc.SaveBatch(orderCtxs[0], orders)
+	}
+
+	links := make([]trace.Link, len(orderCtxs))
+	for i, orderCtx := range orderCtxs {
+		links[i] = trace.LinkFromContext(orderCtx)
+	}
+	ctx, span := tracing.Tracer().Start(ctx, "save batch", trace.WithNewRoot(), trace.WithLinks(links...),
+		trace.WithAttributes(semconv.MessagingBatchMessageCount(len(orders))))
+	defer func() { tracing.End(span, err) }()
+
+	return h.uc.SaveBatch(ctx, orders)
+}
+
+func (h *consumerHandler) saveOrder(ctx context.Context, topic string, order *model.Order) bool {
+	err := retry(ctx, h.logger, func() error {
+		_, err := h.uc.Save(ctx, order)
+		return err
+	}, "failed to save order, retrying")
+	switch {
+	case err == nil:
+		metrics.ConsumerProcessed(topic, 1)
+		return true
+	case ctx.Err() != nil:
+		return false
+	default:
+		h.logger.ErrorContext(ctx, "order rejected by storage, skipping", "error", err, "code", apperr.CodeOf(err))
+		metrics.ConsumerFailed(topic, metrics.StageSave)
+		failSpan(ctx, err)
+		return true
+	}
+}
+
+// retry calls op until it succeeds or fails with an error that apperr does not
+// consider retryable, doubling the delay between attempts. It gives up when ctx is
+// done; callers tell that case apart by checking ctx.Err().
+func retry(ctx context.Context, log *slog.Logger, op func() error, msg string, args ...any) error {
+	backoff := retryInitialBackoff
+	for {
+		err := op()
+		if err == nil {
+			return nil
+		}
+		code := apperr.CodeOf(err)
+		if !code.Retryable() {
+			return err
+		}
+
+		log.ErrorContext(ctx, msg, append(args, "error", err, "code", code, "backoff", backoff)...)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, retryMaxBackoff)
+	}
+}
+
+// startProcessSpan continues the trace found in the message headers. Records logged
+// with the returned context carry the message position.
+func startProcessSpan(ctx context.Context, msg *sarama.ConsumerMessage) (context.Context, trace.Span) {
+	ctx = logger.With(ctx, "topic", msg.Topic, "partition", msg.Partition, "offset", msg.Offset)
+	return tracing.Tracer().Start(tracing.Extract(ctx, msg), "process "+msg.Topic,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypeProcess,
+			semconv.MessagingDestinationName(msg.Topic),
+			semconv.MessagingDestinationPartitionID(strconv.Itoa(int(msg.Partition))),
+			semconv.MessagingKafkaOffset(int(msg.Offset)),
+			semconv.MessagingKafkaMessageKey(string(msg.Key)),
+		),
+	)
+}
+
+// stage runs one step of message handling in its own span.
+func stage(ctx context.Context, name string, fn func() error) error {
+	_, span := tracing.Tracer().Start(ctx, name)
+	err := fn()
+	tracing.End(span, err)
+	if err != nil {
+		failSpan(ctx, err)
+	}
+	return err
+}
+
+// failSpan marks the message span in ctx as failed.
+func failSpan(ctx context.Context, err error) {
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+func (h *consumerHandler) decodeOrder(ctx context.Context, msg *sarama.ConsumerMessage) (*model.Order, bool) {
+	var order model.Order
+	if err := stage(ctx, "unmarshal", func() error { return json.Unmarshal(msg.Value, &order) }); err != nil {
+		h.logger.ErrorContext(ctx, "Unmarshal failed", "error", err)
+		metrics.ConsumerFailed(msg.Topic, metrics.StageUnmarshal)
+		return nil, false
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("order.uid", order.OrderUID))
+
+	if err := stage(ctx, "validate", func() error { return validate.ValidateOrder(order) }); err != nil {
+		h.logger.ErrorContext(ctx, "Invalid order", "error", err, "order_uid", order.OrderUID)
+		metrics.ConsumerFailed(msg.Topic, metrics.StageValidate)
+		return nil, false
+	}
+
+	return &order, true
+}
+
+func (h *consumerHandler) handleStatusEvent(ctx context.Context, msg *sarama.ConsumerMessage) bool {
+	ctx, span := startProcessSpan(ctx, msg)
+	defer span.End()
+
+	var event model.ItemStatusEvent
+	if err := stage(ctx, "unmarshal", func() error { return json.Unmarshal(msg.Value, &event) }); err != nil {
+		h.logger.ErrorContext(ctx, "Unmarshal failed", "error", err)
+		metrics.ConsumerFailed(msg.Topic, metrics.StageUnmarshal)
+		return false
+	}
+	ctx = logger.With(ctx, "order_uid", event.OrderUID, "rid", event.RID)
+
+	if err := stage(ctx, "validate", func() error { return validate.ValidateStatusEvent(event) }); err != nil {
+		h.logger.ErrorContext(ctx, "Invalid status event", "error", err)
+		metrics.ConsumerFailed(msg.Topic, metrics.StageValidate)
+		return false
+	}
+
+	err := retry(ctx, h.logger, func() error { return h.uc.UpdateItemStatus(ctx, &event) }, "failed to update item status, retrying")
+	if err != nil {
+		if ctx.Err() == nil {
+			h.logger.ErrorContext(ctx, "status event rejected, skipping", "error", err, "code", apperr.CodeOf(err))
+			metrics.ConsumerFailed(msg.Topic, metrics.StageSave)
+		}
+		failSpan(ctx, err)
+		return false
+	}
+
+	h.logger.InfoContext(ctx, "item status updated", "status", event.Status.String())
+	metrics.ConsumerProcessed(msg.Topic, 1)
+
+	return true
 }
 
 func (c *Consumer) Run(ctx context.Context, topics []string) {
@@ -95,6 +428,19 @@ func (c *Consumer) Run(ctx context.Context, topics []string) {
 
 }
 
+// Ready reports whether the consumer currently holds a group session.
+func (c *Consumer) Ready(context.Context) error {
+	select {
+	case <-c.handler.ready:
+	default:
+		return errors.New("consumer group has not joined yet")
+	}
+	if !c.handler.active.Load() {
+		return errors.New("consumer group is rebalancing")
+	}
+	return nil
+}
+
 func (c *Consumer) Close() error {
 	c.logger.Info("closing consumer group")
 	return c.group.Close()
@@ -0,0 +1,332 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/tracing"
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+type fakeSession struct {
+	ctx context.Context
+
+	mu     sync.Mutex
+	marked []int64
+}
+
+func (s *fakeSession) Claims() map[string][]int32 { return nil }
+func (s *fakeSession) MemberID() string           { return "member" }
+func (s *fakeSession) GenerationID() int32        { return 1 }
+func (s *fakeSession) Commit()                    {}
+func (s *fakeSession) Context() context.Context   { return s.ctx }
+
+func (s *fakeSession) MarkOffset(_ string, _ int32, offset int64, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.marked = append(s.marked, offset)
+}
+
+func (s *fakeSession) ResetOffset(string, int32, int64, string) {}
+
+func (s *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	s.MarkOffset(msg.Topic, msg.Partition, msg.Offset+1, metadata)
+}
+
+func (s *fakeSession) committed() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.marked) == 0 {
+		return 0
+	}
+	return s.marked[len(s.marked)-1]
+}
+
+type fakeClaim struct {
+	msgs chan *sarama.ConsumerMessage
+}
+
+func (c *fakeClaim) Topic() string                            { return "orders" }
+func (c *fakeClaim) Partition() int32                         { return 0 }
+func (c *fakeClaim) InitialOffset() int64                     { return 0 }
+func (c *fakeClaim) HighWaterMarkOffset() int64               { return 0 }
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return c.msgs }
+
+// fakeStore blocks on orders listed in stuck until the session is cancelled,
+// simulating a write that never finishes before a crash.
+type fakeStore struct {
+	stuck map[string]bool
+
+	mu    sync.Mutex
+	saved []string
+}
+
+func (f *fakeStore) Save(ctx context.Context, order *model.Order) (bool, error) {
+	stored, err := f.SaveBatch(ctx, []*model.Order{order})
+	return len(stored) > 0, err
+}
+
+func (f *fakeStore) SaveBatch(ctx context.Context, orders []*model.Order) ([]string, error) {
+	for _, o := range orders {
+		if f.stuck[o.OrderUID] {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var stored []string
+	for _, o := range orders {
+		f.saved = append(f.saved, o.OrderUID)
+		stored = append(stored, o.OrderUID)
+	}
+	return stored, nil
+}
+
+func (f *fakeStore) UpdateItemStatus(context.Context, *model.ItemStatusEvent) error {
+	return nil
+}
+
+func (f *fakeStore) savedOrders() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.saved...)
+}
+
+func testOrder(uid string) model.Order {
+	created := time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC)
+	return model.Order{
+		OrderUID: uid, TrackNumber: "TRK", Entry: "WBIL", Locale: "en", CustomerID: "cust",
+		DeliveryService: "meest", ShardKey: "9", SmID: 99, DateCreated: created, OofShard: "1", CreatedAt: created,
+		Delivery: model.Delivery{
+			Name: "Test Testov", Phone: "+9720000000", Zip: "2639809",
+			City: "Kiryat Mozkin", Address: "Ploshad Mira 15", Email: "test@gmail.com",
+		},
+		Payment: model.Payment{Transaction: uid, Currency: "USD", Provider: "wbpay", Amount: 1817, PaymentDT: 1637907727},
+		Items: []model.Item{{
+			ChrtID: 9934930, TrackNumber: "TRK", Price: 453, RID: uid + "-rid", Name: "Mascaras",
+			TotalPrice: 317, Brand: "Vivienne Sabo", Status: model.ItemStatusCreated,
+		}},
+	}
+}
+
+func message(t *testing.T, offset int64, key, uid string) *sarama.ConsumerMessage {
+	t.Helper()
+	raw, err := json.Marshal(testOrder(uid))
+	require.NoError(t, err)
+	return &sarama.ConsumerMessage{Topic: "orders", Offset: offset, Key: []byte(key), Value: raw}
+}
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func newTestHandler(store orderStore, workers int) *consumerHandler {
+	return &consumerHandler{
+		ready:        make(chan struct{}),
+		uc:           store,
+		statusTopic:  "statuses",
+		batchSize:    1,
+		batchTimeout: 10 * time.Millisecond,
+		workers:      workers,
+		logger:       testLogger,
+	}
+}
+
+// keysOnOtherWorkers returns n keys that do not share a worker with key.
+func keysOnOtherWorkers(key string, n, workers int) []string {
+	var keys []string
+	for i := 0; len(keys) < n; i++ {
+		k := fmt.Sprintf("order-%d", i)
+		if shard([]byte(k), workers) != shard([]byte(key), workers) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+func TestOffsetTracker_MarksLowestContiguousOffset(t *testing.T) {
+	var marked []int64
+	tr := newOffsetTracker(func(offset int64) { marked = append(marked, offset) })
+	for off := int64(10); off < 15; off++ {
+		tr.Add(off)
+	}
+
+	tr.Done(12)
+	assert.Empty(t, marked)
+
+	tr.Done(10)
+	assert.Equal(t, []int64{10}, marked)
+
+	tr.Done(11)
+	assert.Equal(t, []int64{10, 12}, marked)
+
+	tr.Done(14)
+	assert.Equal(t, []int64{10, 12}, marked)
+
+	tr.Done(13)
+	assert.Equal(t, []int64{10, 12, 14}, marked)
+}
+
+func TestConsumeOrders_NoOffsetSkippedOnCrash(t *testing.T) {
+	const workers = 4
+	others := keysOnOtherWorkers("stuck", 3, workers)
+
+	store := &fakeStore{stuck: map[string]bool{"stuck": true}}
+	h := newTestHandler(store, workers)
+
+	ctx, crash := context.WithCancel(context.Background())
+	sess := &fakeSession{ctx: ctx}
+	claim := &fakeClaim{msgs: make(chan *sarama.ConsumerMessage, 4)}
+	claim.msgs <- message(t, 0, others[0], others[0])
+	claim.msgs <- message(t, 1, "stuck", "stuck")
+	claim.msgs <- message(t, 2, others[1], others[1])
+	claim.msgs <- message(t, 3, others[2], others[2])
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, h.ConsumeClaim(sess, claim))
+	}()
+
+	// Orders behind the stuck one are stored without waiting for it.
+	require.Eventually(t, func() bool { return len(store.savedOrders()) == 3 }, time.Second, 5*time.Millisecond)
+	assert.ElementsMatch(t, others, store.savedOrders())
+
+	crash()
+	<-done
+
+	// Only offset 0 is committed, so offset 1 and everything after it is redelivered.
+	assert.Equal(t, int64(1), sess.committed())
+}
+
+func TestConsumeOrders_PreservesOrderPerKey(t *testing.T) {
+	store := &fakeStore{}
+	h := newTestHandler(store, 4)
+
+	sess := &fakeSession{ctx: context.Background()}
+	claim := &fakeClaim{msgs: make(chan *sarama.ConsumerMessage, 20)}
+	var want []string
+	for i := 0; i < 20; i++ {
+		uid := fmt.Sprintf("order-%d-v%d", i%2, i)
+		claim.msgs <- message(t, int64(i), fmt.Sprintf("order-%d", i%2), uid)
+		if i%2 == 0 {
+			want = append(want, uid)
+		}
+	}
+	close(claim.msgs)
+
+	require.NoError(t, h.ConsumeClaim(sess, claim))
+
+	var got []string
+	for _, uid := range store.savedOrders() {
+		if uid[:7] == "order-0" {
+			got = append(got, uid)
+		}
+	}
+	assert.Equal(t, want, got)
+	assert.Equal(t, int64(20), sess.committed())
+}
+
+func TestRetry_StopsOnPermanentErrors(t *testing.T) {
+	unexpected := errors.New("unexpected row shape")
+	tests := []struct {
+		name     string
+		errs     []error
+		wantErr  error
+		wantCall int
+	}{
+		{name: "transient then success", errs: []error{apperr.ErrUnavailable, context.DeadlineExceeded, nil}, wantCall: 3},
+		{name: "permanent", errs: []error{apperr.ErrInvalidData}, wantErr: apperr.ErrInvalidData, wantCall: 1},
+		{name: "transient then permanent", errs: []error{apperr.ErrTimeout, apperr.ErrInvalidTransition}, wantErr: apperr.ErrInvalidTransition, wantCall: 2},
+		{name: "internal", errs: []error{unexpected}, wantErr: unexpected, wantCall: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+			err := retry(context.Background(), testLogger, func() error {
+				calls++
+				return tc.errs[calls-1]
+			}, "retrying")
+
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.wantCall, calls)
+		})
+	}
+}
+
+func TestSaveOrders_ContinuesProducerTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	// carry the producer trace context over to the consumed messages
+	produced := func(ctx context.Context, msg *sarama.ConsumerMessage) *sarama.ConsumerMessage {
+		out := &sarama.ProducerMessage{Topic: msg.Topic}
+		tracing.Inject(ctx, out)
+		for _, h := range out.Headers {
+			msg.Headers = append(msg.Headers, &sarama.RecordHeader{Key: h.Key, Value: h.Value})
+		}
+		return msg
+	}
+	producerCtx, producerSpan := otel.Tracer("test").Start(context.Background(), "send orders")
+	producerSpan.End()
+	traceID := producerSpan.SpanContext().TraceID()
+
+	good := produced(producerCtx, message(t, 0, "order-1", "order-1"))
+	bad := produced(producerCtx, &sarama.ConsumerMessage{Topic: "orders", Offset: 1, Value: []byte("{")})
+
+	store := &fakeStore{}
+	require.True(t, newTestHandler(store, 1).saveOrders(context.Background(), []*sarama.ConsumerMessage{good, bad}))
+	assert.Equal(t, []string{"order-1"}, store.savedOrders())
+
+	byName := map[string][]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		assert.Equal(t, traceID, span.SpanContext().TraceID(), span.Name())
+		byName[span.Name()] = append(byName[span.Name()], span)
+	}
+	require.Len(t, byName["process orders"], 2)
+	assert.Len(t, byName["unmarshal"], 2)
+	assert.Len(t, byName["validate"], 1, "the broken message never gets to validation")
+
+	for _, span := range byName["process orders"] {
+		assert.Equal(t, producerSpan.SpanContext().SpanID(), span.Parent().SpanID())
+		offset := offsetOf(span)
+		if offset == bad.Offset {
+			assert.Equal(t, codes.Error, span.Status().Code)
+		} else {
+			assert.Equal(t, codes.Unset, span.Status().Code)
+		}
+	}
+}
+
+func offsetOf(span sdktrace.ReadOnlySpan) int64 {
+	for _, attr := range span.Attributes() {
+		if attr.Key == semconv.MessagingKafkaOffsetKey {
+			return attr.Value.AsInt64()
+		}
+	}
+	return -1
+}
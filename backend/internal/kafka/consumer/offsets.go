@@ -0,0 +1,49 @@
+package consumer
+
+import "sync"
+
+// offsetTracker follows the offsets dispatched from one partition. Messages may finish
+// out of order, but mark is only called with the highest offset below which every
+// message has completed, so a crash never commits past unfinished work.
+type offsetTracker struct {
+	mu      sync.Mutex
+	pending []int64
+	done    map[int64]struct{}
+	mark    func(offset int64)
+}
+
+func newOffsetTracker(mark func(offset int64)) *offsetTracker {
+	return &offsetTracker{done: make(map[int64]struct{}), mark: mark}
+}
+
+// Add registers a dispatched offset. Offsets must be added in ascending order.
+func (t *offsetTracker) Add(offset int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.pending = append(t.pending, offset)
+}
+
+func (t *offsetTracker) Done(offsets ...int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, offset := range offsets {
+		t.done[offset] = struct{}{}
+	}
+
+	last := int64(-1)
+	for len(t.pending) > 0 {
+		head := t.pending[0]
+		if _, ok := t.done[head]; !ok {
+			break
+		}
+		delete(t.done, head)
+		t.pending = t.pending[1:]
+		last = head
+	}
+
+	if last >= 0 {
+		t.mark(last)
+	}
+}
@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ctxKey struct{}
+
+// With returns a copy of ctx whose log records get args as attributes, in addition
+// to those already added to ctx. args are key-value pairs as in slog.Logger.With.
+func With(ctx context.Context, args ...any) context.Context {
+	prev := attrs(ctx)
+	next := make([]slog.Attr, len(prev), len(prev)+len(args)/2)
+	copy(next, prev)
+
+	r := slog.Record{}
+	r.Add(args...)
+	r.Attrs(func(a slog.Attr) bool {
+		next = append(next, a)
+		return true
+	})
+	return context.WithValue(ctx, ctxKey{}, next)
+}
+
+func attrs(ctx context.Context) []slog.Attr {
+	a, _ := ctx.Value(ctxKey{}).([]slog.Attr)
+	return a
+}
+
+// contextHandler adds the attributes stored in the record context, and the trace ID
+// of its span, to every record.
+type contextHandler struct {
+	next slog.Handler
+}
+
+func (h contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx != nil {
+		r.AddAttrs(attrs(ctx)...)
+		if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+			r.AddAttrs(slog.String("trace_id", sc.TraceID().String()))
+		}
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{next: h.next.WithGroup(name)}
+}
@@ -1,35 +1,82 @@
 package logger
 
 import (
+	"io"
 	"log/slog"
 	"os"
+	"strings"
+
+	"github.com/GkadyrG/L0/backend/config"
+	"github.com/pkg/errors"
 )
 
 const (
-	debug = "debug"
-	info  = "info"
-	error = "error"
+	levelDebug = "debug"
+	levelInfo  = "info"
+	levelWarn  = "warn"
+	levelError = "error"
+
+	formatText = "text"
 )
 
-func SetupLogger(logLevel string) *slog.Logger {
-	var log *slog.Logger
-	switch logLevel {
-	case debug:
-		log = slog.New(
-			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
-		)
-	case info:
-		log = slog.New(
-			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}),
-		)
-	case error:
-		log = slog.New(
-			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}),
-		)
-	default:
-		log = slog.New(
-			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}),
-		)
-	}
+var ErrUnknownLevel = errors.New("unknown log level")
+
+var levels = map[string]slog.Level{
+	levelDebug: slog.LevelDebug,
+	levelInfo:  slog.LevelInfo,
+	levelWarn:  slog.LevelWarn,
+	levelError: slog.LevelError,
+}
+
+// level is the level of the logger built by SetupLogger; SetLevel changes it for
+// every logger derived from it.
+var level slog.LevelVar
+
+// SetupLogger builds the service logger and installs it as the slog default, so
+// packages logging through slog directly share its settings. Records logged with a
+// context carry the attributes added to it with With.
+func SetupLogger(cfg config.LogConfig) *slog.Logger {
+	level.Set(parseLevel(cfg.Level))
+	log := slog.New(newHandler(os.Stdout, cfg, &level))
+	slog.SetDefault(log)
 	return log
 }
+
+// Level returns the current level of the service logger.
+func Level() string {
+	return strings.ToLower(level.Level().String())
+}
+
+// SetLevel changes the level of the service logger without restarting it.
+func SetLevel(name string) error {
+	l, ok := levels[name]
+	if !ok {
+		return errors.Wrapf(ErrUnknownLevel, "%q", name)
+	}
+	level.Set(l)
+	return nil
+}
+
+func newHandler(w io.Writer, cfg config.LogConfig, level slog.Leveler) slog.Handler {
+	opts := &slog.HandlerOptions{Level: level}
+
+	var h slog.Handler
+	if cfg.Format == formatText {
+		h = slog.NewTextHandler(w, opts)
+	} else {
+		h = slog.NewJSONHandler(w, opts)
+	}
+
+	if cfg.SampleInfo > 1 {
+		h = newSampler(h, cfg.SampleInfo)
+	}
+	return contextHandler{next: h}
+}
+
+// parseLevel falls back to info for unknown names.
+func parseLevel(name string) slog.Level {
+	if l, ok := levels[name]; ok {
+		return l
+	}
+	return slog.LevelInfo
+}
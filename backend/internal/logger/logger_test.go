@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/GkadyrG/L0/backend/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func records(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &rec))
+		out = append(out, rec)
+	}
+	return out
+}
+
+func TestWith(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(newHandler(&buf, config.LogConfig{}, slog.LevelInfo))
+
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+
+	ctx := With(context.Background(), "request_id", "req-1")
+	child := With(ctx, "order_uid", "order-1")
+	child = trace.ContextWithSpanContext(child, trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+
+	log.InfoContext(child, "order loaded")
+	log.InfoContext(ctx, "request done")
+	log.Info("no context")
+
+	got := records(t, &buf)
+	require.Len(t, got, 3)
+
+	assert.Equal(t, "req-1", got[0]["request_id"])
+	assert.Equal(t, "order-1", got[0]["order_uid"])
+	assert.Equal(t, traceID.String(), got[0]["trace_id"])
+
+	assert.Equal(t, "req-1", got[1]["request_id"])
+	assert.NotContains(t, got[1], "order_uid", "the parent context is not changed")
+
+	assert.NotContains(t, got[2], "request_id")
+}
+
+func TestSetupLogger_LevelAndSampling(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.LogConfig
+		want []string
+	}{
+		{
+			name: "warn level",
+			cfg:  config.LogConfig{Level: "warn"},
+			want: []string{"WARN", "ERROR"},
+		},
+		{
+			name: "sampled info",
+			cfg:  config.LogConfig{Level: "debug", SampleInfo: 2},
+			// info and debug records 1 and 3 of each message pass, warnings always do
+			want: []string{"INFO", "DEBUG", "INFO", "DEBUG", "WARN", "ERROR"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := slog.New(newHandler(&buf, tc.cfg, parseLevel(tc.cfg.Level)))
+
+			for range 3 {
+				log.Info("message consumed")
+				log.Debug("batch flushed")
+			}
+			log.Warn("retrying")
+			log.Error("failed")
+
+			var got []string
+			for _, rec := range records(t, &buf) {
+				got = append(got, rec["level"].(string))
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	var buf bytes.Buffer
+	level.Set(slog.LevelInfo)
+	log := slog.New(newHandler(&buf, config.LogConfig{}, &level)).With("component", "consumer")
+
+	log.Debug("dropped")
+	require.NoError(t, SetLevel("debug"))
+	log.Debug("written")
+	assert.Equal(t, "debug", Level())
+
+	err := SetLevel("verbose")
+	assert.ErrorIs(t, err, ErrUnknownLevel)
+	assert.Equal(t, "debug", Level(), "an unknown level leaves the current one")
+
+	got := records(t, &buf)
+	require.Len(t, got, 1)
+	assert.Equal(t, "written", got[0]["msg"])
+}
@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// sampler writes the first and then every n-th record of each debug or info
+// message; warnings and errors always pass.
+type sampler struct {
+	next   slog.Handler
+	n      uint64
+	counts *sync.Map // message -> *atomic.Uint64
+}
+
+func newSampler(next slog.Handler, n int) sampler {
+	return sampler{next: next, n: uint64(n), counts: &sync.Map{}}
+}
+
+func (s sampler) Enabled(ctx context.Context, level slog.Level) bool {
+	return s.next.Enabled(ctx, level)
+}
+
+func (s sampler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < slog.LevelWarn {
+		c, _ := s.counts.LoadOrStore(r.Message, new(atomic.Uint64))
+		if (c.(*atomic.Uint64).Add(1)-1)%s.n != 0 {
+			return nil
+		}
+	}
+	return s.next.Handle(ctx, r)
+}
+
+func (s sampler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return sampler{next: s.next.WithAttrs(attrs), n: s.n, counts: s.counts}
+}
+
+func (s sampler) WithGroup(name string) slog.Handler {
+	return sampler{next: s.next.WithGroup(name), n: s.n, counts: s.counts}
+}
@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/repository"
+	"github.com/GkadyrG/L0/backend/internal/usecase"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	repositoryDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "repository_duration_seconds",
+		Help:      "Repository call latency by method and error code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+	providerDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "order_provider_duration_seconds",
+		Help:      "OrderProvider call latency by method and error code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+)
+
+func observe(h *prometheus.HistogramVec, method string, start time.Time, err error) {
+	code := "ok"
+	if err != nil {
+		code = string(apperr.CodeOf(err))
+	}
+	h.WithLabelValues(method, code).Observe(time.Since(start).Seconds())
+}
+
+// OrderRepository times every call to the wrapped repository.
+type OrderRepository struct {
+	next repository.OrderRepository
+}
+
+func NewOrderRepository(next repository.OrderRepository) *OrderRepository {
+	return &OrderRepository{next: next}
+}
+
+func (r *OrderRepository) Save(ctx context.Context, order *model.Order) (_ bool, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "Save", start, err) }(time.Now())
+	return r.next.Save(ctx, order)
+}
+
+func (r *OrderRepository) SaveBatch(ctx context.Context, orders []*model.Order) (_ []string, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "SaveBatch", start, err) }(time.Now())
+	return r.next.SaveBatch(ctx, orders)
+}
+
+func (r *OrderRepository) GetByID(ctx context.Context, id string) (_ *model.OrderResponse, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "GetByID", start, err) }(time.Now())
+	return r.next.GetByID(ctx, id)
+}
+
+func (r *OrderRepository) GetByTrackNumber(ctx context.Context, track string) (_ *model.OrderResponse, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "GetByTrackNumber", start, err) }(time.Now())
+	return r.next.GetByTrackNumber(ctx, track)
+}
+
+func (r *OrderRepository) ListByCustomer(ctx context.Context, customerID string, limit, offset int) (_ []*model.OrderPreview, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "ListByCustomer", start, err) }(time.Now())
+	return r.next.ListByCustomer(ctx, customerID, limit, offset)
+}
+
+func (r *OrderRepository) GetAll(ctx context.Context, filter model.OrderFilter) (_ []*model.OrderPreview, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "GetAll", start, err) }(time.Now())
+	return r.next.GetAll(ctx, filter)
+}
+
+func (r *OrderRepository) GetAllFull(ctx context.Context, limit int) (_ []*model.OrderResponse, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "GetAllFull", start, err) }(time.Now())
+	return r.next.GetAllFull(ctx, limit)
+}
+
+func (r *OrderRepository) UpdateItemStatus(ctx context.Context, event *model.ItemStatusEvent) (err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "UpdateItemStatus", start, err) }(time.Now())
+	return r.next.UpdateItemStatus(ctx, event)
+}
+
+func (r *OrderRepository) ExportOrders(ctx context.Context, filter model.OrderFilter, fn func(*model.ExportRow) error) (err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "ExportOrders", start, err) }(time.Now())
+	return r.next.ExportOrders(ctx, filter, fn)
+}
+
+// OrderProvider times every call to the wrapped use case.
+type OrderProvider struct {
+	next usecase.OrderProvider
+}
+
+func NewOrderProvider(next usecase.OrderProvider) *OrderProvider {
+	return &OrderProvider{next: next}
+}
+
+func (p *OrderProvider) Save(ctx context.Context, order *model.Order) (_ bool, err error) {
+	defer func(start time.Time) { observe(providerDuration, "Save", start, err) }(time.Now())
+	return p.next.Save(ctx, order)
+}
+
+func (p *OrderProvider) SaveBatch(ctx context.Context, orders []*model.Order) (_ []string, err error) {
+	defer func(start time.Time) { observe(providerDuration, "SaveBatch", start, err) }(time.Now())
+	return p.next.SaveBatch(ctx, orders)
+}
+
+func (p *OrderProvider) GetByID(ctx context.Context, id string) (_ *model.OrderResponse, err error) {
+	defer func(start time.Time) { observe(providerDuration, "GetByID", start, err) }(time.Now())
+	return p.next.GetByID(ctx, id)
+}
+
+func (p *OrderProvider) GetByTrackNumber(ctx context.Context, track string) (_ *model.OrderResponse, err error) {
+	defer func(start time.Time) { observe(providerDuration, "GetByTrackNumber", start, err) }(time.Now())
+	return p.next.GetByTrackNumber(ctx, track)
+}
+
+func (p *OrderProvider) ListByCustomer(ctx context.Context, customerID string, limit, offset int) (_ []*model.OrderPreview, err error) {
+	defer func(start time.Time) { observe(providerDuration, "ListByCustomer", start, err) }(time.Now())
+	return p.next.ListByCustomer(ctx, customerID, limit, offset)
+}
+
+func (p *OrderProvider) GetAll(ctx context.Context, filter model.OrderFilter) (_ []*model.OrderPreview, err error) {
+	defer func(start time.Time) { observe(providerDuration, "GetAll", start, err) }(time.Now())
+	return p.next.GetAll(ctx, filter)
+}
+
+func (p *OrderProvider) GetAllFull(ctx context.Context, limit int) (_ []*model.OrderResponse, err error) {
+	defer func(start time.Time) { observe(providerDuration, "GetAllFull", start, err) }(time.Now())
+	return p.next.GetAllFull(ctx, limit)
+}
+
+func (p *OrderProvider) UpdateItemStatus(ctx context.Context, event *model.ItemStatusEvent) (err error) {
+	defer func(start time.Time) { observe(providerDuration, "UpdateItemStatus", start, err) }(time.Now())
+	return p.next.UpdateItemStatus(ctx, event)
+}
+
+func (p *OrderProvider) ExportOrders(ctx context.Context, filter model.OrderFilter, fn func(*model.ExportRow) error) (err error) {
+	defer func(start time.Time) { observe(providerDuration, "ExportOrders", start, err) }(time.Now())
+	return p.next.ExportOrders(ctx, filter, fn)
+}
+
+func (p *OrderProvider) GetTimeline(ctx context.Context, id string) (_ *model.OrderTimeline, err error) {
+	defer func(start time.Time) { observe(providerDuration, "GetTimeline", start, err) }(time.Now())
+	return p.next.GetTimeline(ctx, id)
+}
@@ -0,0 +1,144 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "orders"
+
+// Registry holds every collector served at /metrics.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	httpRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route pattern and status.",
+	}, []string{"method", "route", "status"})
+	httpDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route pattern and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	httpHandlerTimeouts = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_handler_timeouts_total",
+		Help:      "API requests that ran out of their handler deadline, by route pattern.",
+	}, []string{"route"})
+
+	consumerProcessed = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "consumer_messages_processed_total",
+		Help:      "Kafka messages handled successfully.",
+	}, []string{"topic"})
+	consumerFailed = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "consumer_messages_failed_total",
+		Help:      "Kafka messages skipped, by the stage that rejected them.",
+	}, []string{"topic", "stage"})
+	consumerLag = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "consumer_lag",
+		Help:      "Messages between the last consumed offset and the partition high watermark.",
+	}, []string{"topic", "partition"})
+
+	cacheLookups = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_lookups_total",
+		Help:      "Order cache lookups by key kind and result.",
+	}, []string{"key", "result"})
+
+	configReloads = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "config_reloads_total",
+		Help:      "Settings changed by a config reload, by setting name.",
+	}, []string{"setting"})
+
+	emulatorSent = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "emulator_messages_total",
+		Help:      "Orders produced by the emulator, by result.",
+	}, []string{"result"})
+)
+
+// Consumer stages a message can be rejected at.
+const (
+	StageUnmarshal = "unmarshal"
+	StageValidate  = "validate"
+	StageSave      = "save"
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler serves the registry in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+func ObserveHTTP(method, route string, status int, d time.Duration) {
+	code := strconv.Itoa(status)
+	httpRequests.WithLabelValues(method, route, code).Inc()
+	httpDuration.WithLabelValues(method, route, code).Observe(d.Seconds())
+}
+
+func HandlerTimeout(route string) {
+	httpHandlerTimeouts.WithLabelValues(route).Inc()
+}
+
+func ConsumerProcessed(topic string, n int) {
+	consumerProcessed.WithLabelValues(topic).Add(float64(n))
+}
+
+func ConsumerFailed(topic, stage string) {
+	consumerFailed.WithLabelValues(topic, stage).Inc()
+}
+
+func SetConsumerLag(topic string, partition int32, lag int64) {
+	consumerLag.WithLabelValues(topic, strconv.Itoa(int(partition))).Set(float64(max(lag, 0)))
+}
+
+// CacheLookup records a cache lookup by key kind ("id" or "track").
+func CacheLookup(key string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheLookups.WithLabelValues(key, result).Inc()
+}
+
+// WatchCache exports the number of cached orders as reported by size.
+func WatchCache(size func() int) {
+	factory.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cache_orders",
+		Help:      "Orders currently held in the cache.",
+	}, func() float64 { return float64(size()) })
+}
+
+func ConfigReloaded(setting string) {
+	configReloads.WithLabelValues(setting).Inc()
+}
+
+func EmulatorSent(err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	emulatorSent.WithLabelValues(result).Inc()
+}
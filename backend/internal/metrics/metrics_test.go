@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/repository/mocks"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRepository_ObservesCalls(t *testing.T) {
+	repo := mocks.NewOrderRepository(t)
+	repo.On("GetByID", mock.Anything, "order-1").Return(&model.OrderResponse{OrderUID: "order-1"}, nil).Once()
+	repo.On("GetByID", mock.Anything, "missing").Return(nil, apperr.New(apperr.ErrNotFound, "order not found")).Once()
+
+	okBefore := sampleCount(t, "orders_repository_duration_seconds", map[string]string{"method": "GetByID", "code": "ok"})
+	missBefore := sampleCount(t, "orders_repository_duration_seconds", map[string]string{"method": "GetByID", "code": "not_found"})
+
+	decorated := NewOrderRepository(repo)
+	order, err := decorated.GetByID(context.Background(), "order-1")
+	require.NoError(t, err)
+	assert.Equal(t, "order-1", order.OrderUID)
+
+	_, err = decorated.GetByID(context.Background(), "missing")
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+
+	assert.Equal(t, okBefore+1, sampleCount(t, "orders_repository_duration_seconds", map[string]string{"method": "GetByID", "code": "ok"}))
+	assert.Equal(t, missBefore+1, sampleCount(t, "orders_repository_duration_seconds", map[string]string{"method": "GetByID", "code": "not_found"}))
+}
+
+func TestQueryRepository_ObservesCalls(t *testing.T) {
+	repo := mocks.NewQueryRepository(t)
+	repo.On("GetOrderDetails", mock.Anything, "order-1").Return(&model.OrderDetails{}, nil).Once()
+
+	before := sampleCount(t, "orders_repository_duration_seconds", map[string]string{"method": "GetOrderDetails", "code": "ok"})
+
+	_, err := NewQueryRepository(repo).GetOrderDetails(context.Background(), "order-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, before+1, sampleCount(t, "orders_repository_duration_seconds", map[string]string{"method": "GetOrderDetails", "code": "ok"}))
+}
+
+func TestSetConsumerLag(t *testing.T) {
+	SetConsumerLag("orders", 3, 42)
+	assert.Equal(t, 42.0, value(t, "orders_consumer_lag", map[string]string{"topic": "orders", "partition": "3"}))
+
+	// the high watermark can trail the offset right after a rebalance
+	SetConsumerLag("orders", 3, -1)
+	assert.Equal(t, 0.0, value(t, "orders_consumer_lag", map[string]string{"topic": "orders", "partition": "3"}))
+}
+
+// value returns the current value of the counter or gauge name with exactly labels,
+// or 0 if the series does not exist yet.
+func value(t testing.TB, name string, labels map[string]string) float64 {
+	m := find(t, name, labels)
+	switch {
+	case m == nil:
+		return 0
+	case m.GetCounter() != nil:
+		return m.GetCounter().GetValue()
+	default:
+		return m.GetGauge().GetValue()
+	}
+}
+
+// sampleCount returns how many observations the histogram name with exactly labels
+// has recorded.
+func sampleCount(t testing.TB, name string, labels map[string]string) uint64 {
+	if m := find(t, name, labels); m != nil {
+		return m.GetHistogram().GetSampleCount()
+	}
+	return 0
+}
+
+func find(t testing.TB, name string, labels map[string]string) *dto.Metric {
+	t.Helper()
+
+	families, err := Registry.Gather()
+	if err != nil {
+		t.Fatalf("gather metrics: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			if matches(m, labels) {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+func matches(m *dto.Metric, labels map[string]string) bool {
+	if len(m.GetLabel()) != len(labels) {
+		return false
+	}
+	for _, l := range m.GetLabel() {
+		if labels[l.GetName()] != l.GetValue() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,160 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/repository"
+)
+
+// QueryRepository times every call to the wrapped query repository.
+type QueryRepository struct {
+	next repository.QueryRepository
+}
+
+func NewQueryRepository(next repository.QueryRepository) *QueryRepository {
+	return &QueryRepository{next: next}
+}
+
+func (r *QueryRepository) ListOrderPage(ctx context.Context, filter model.OrderFilter, after *model.OrderCursor, limit int) (_ []*model.OrderPreview, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "ListOrderPage", start, err) }(time.Now())
+	return r.next.ListOrderPage(ctx, filter, after, limit)
+}
+
+func (r *QueryRepository) ListCustomerOrderPages(ctx context.Context, customerIDs []string, after *model.OrderCursor, limit int) (_ []*model.OrderPreview, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "ListCustomerOrderPages", start, err) }(time.Now())
+	return r.next.ListCustomerOrderPages(ctx, customerIDs, after, limit)
+}
+
+func (r *QueryRepository) GetOrdersByUIDs(ctx context.Context, orderUIDs []string) (_ []*model.OrderResponse, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "GetOrdersByUIDs", start, err) }(time.Now())
+	return r.next.GetOrdersByUIDs(ctx, orderUIDs)
+}
+
+func (r *QueryRepository) GetItemsByOrderUIDs(ctx context.Context, orderUIDs []string) (_ map[string][]model.ItemResponse, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "GetItemsByOrderUIDs", start, err) }(time.Now())
+	return r.next.GetItemsByOrderUIDs(ctx, orderUIDs)
+}
+
+func (r *QueryRepository) GetOrderDetails(ctx context.Context, orderUID string) (_ *model.OrderDetails, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "GetOrderDetails", start, err) }(time.Now())
+	return r.next.GetOrderDetails(ctx, orderUID)
+}
+
+func (r *QueryRepository) GetOrderDetailsByTrack(ctx context.Context, track string) (_ *model.OrderDetails, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "GetOrderDetailsByTrack", start, err) }(time.Now())
+	return r.next.GetOrderDetailsByTrack(ctx, track)
+}
+
+// AnalyticsRepository times every call to the wrapped analytics repository.
+type AnalyticsRepository struct {
+	next repository.AnalyticsRepository
+}
+
+func NewAnalyticsRepository(next repository.AnalyticsRepository) *AnalyticsRepository {
+	return &AnalyticsRepository{next: next}
+}
+
+func (r *AnalyticsRepository) OrderStats(ctx context.Context, q model.StatsQuery) (_ []model.StatsBucket, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "OrderStats", start, err) }(time.Now())
+	return r.next.OrderStats(ctx, q)
+}
+
+func (r *AnalyticsRepository) BasketStats(ctx context.Context, tr model.TimeRange) (_ *model.BasketStats, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "BasketStats", start, err) }(time.Now())
+	return r.next.BasketStats(ctx, tr)
+}
+
+func (r *AnalyticsRepository) TopBrands(ctx context.Context, q model.TopQuery) (_ []model.BrandTop, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "TopBrands", start, err) }(time.Now())
+	return r.next.TopBrands(ctx, q)
+}
+
+func (r *AnalyticsRepository) TopProducts(ctx context.Context, q model.TopQuery) (_ []model.ProductTop, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "TopProducts", start, err) }(time.Now())
+	return r.next.TopProducts(ctx, q)
+}
+
+// OutboxRepository times every call to the wrapped outbox repository.
+type OutboxRepository struct {
+	next repository.OutboxRepository
+}
+
+func NewOutboxRepository(next repository.OutboxRepository) *OutboxRepository {
+	return &OutboxRepository{next: next}
+}
+
+func (r *OutboxRepository) ClaimOutbox(ctx context.Context, limit int, lease time.Duration) (_ []model.OutboxMessage, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "ClaimOutbox", start, err) }(time.Now())
+	return r.next.ClaimOutbox(ctx, limit, lease)
+}
+
+func (r *OutboxRepository) MarkOutboxPublished(ctx context.Context, ids []int64) (err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "MarkOutboxPublished", start, err) }(time.Now())
+	return r.next.MarkOutboxPublished(ctx, ids)
+}
+
+func (r *OutboxRepository) FailOutbox(ctx context.Context, ids []int64, reason string) (err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "FailOutbox", start, err) }(time.Now())
+	return r.next.FailOutbox(ctx, ids, reason)
+}
+
+func (r *OutboxRepository) OutboxBacklog(ctx context.Context) (_ *model.OutboxBacklog, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "OutboxBacklog", start, err) }(time.Now())
+	return r.next.OutboxBacklog(ctx)
+}
+
+// WebhookRepository times every call to the wrapped webhook repository.
+type WebhookRepository struct {
+	next repository.WebhookRepository
+}
+
+func NewWebhookRepository(next repository.WebhookRepository) *WebhookRepository {
+	return &WebhookRepository{next: next}
+}
+
+func (r *WebhookRepository) CreateWebhook(ctx context.Context, url, secret string, eventTypes []model.EventType) (_ *model.Webhook, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "CreateWebhook", start, err) }(time.Now())
+	return r.next.CreateWebhook(ctx, url, secret, eventTypes)
+}
+
+func (r *WebhookRepository) ListWebhooks(ctx context.Context) (_ []*model.Webhook, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "ListWebhooks", start, err) }(time.Now())
+	return r.next.ListWebhooks(ctx)
+}
+
+func (r *WebhookRepository) GetWebhook(ctx context.Context, id int64) (_ *model.Webhook, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "GetWebhook", start, err) }(time.Now())
+	return r.next.GetWebhook(ctx, id)
+}
+
+func (r *WebhookRepository) UpdateWebhook(ctx context.Context, id int64, in model.WebhookInput) (_ *model.Webhook, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "UpdateWebhook", start, err) }(time.Now())
+	return r.next.UpdateWebhook(ctx, id, in)
+}
+
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, id int64) (err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "DeleteWebhook", start, err) }(time.Now())
+	return r.next.DeleteWebhook(ctx, id)
+}
+
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, webhookID int64, limit, offset int) (_ []*model.WebhookDelivery, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "ListDeliveries", start, err) }(time.Now())
+	return r.next.ListDeliveries(ctx, webhookID, limit, offset)
+}
+
+func (r *WebhookRepository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) (_ []*model.PendingDelivery, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "ClaimDeliveries", start, err) }(time.Now())
+	return r.next.ClaimDeliveries(ctx, limit, lease)
+}
+
+func (r *WebhookRepository) CompleteDelivery(ctx context.Context, deliveryID, webhookID int64, statusCode int) (err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "CompleteDelivery", start, err) }(time.Now())
+	return r.next.CompleteDelivery(ctx, deliveryID, webhookID, statusCode)
+}
+
+func (r *WebhookRepository) FailDelivery(ctx context.Context, f model.DeliveryFailure) (_ bool, err error) {
+	defer func(start time.Time) { observe(repositoryDuration, "FailDelivery", start, err) }(time.Now())
+	return r.next.FailDelivery(ctx, f)
+}
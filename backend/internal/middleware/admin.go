@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/GkadyrG/L0/backend/config"
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/problem"
+)
+
+// AdminAuth requires "Authorization: Bearer <ADMIN_TOKEN>". Without a configured
+// token every request is rejected, so the admin API is off by default.
+func AdminAuth(cfg *config.Config) func(http.Handler) http.Handler {
+	token := cfg.App.AdminToken
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token == "" {
+				problem.Write(w, r, apperr.New(apperr.ErrForbidden, "admin API is disabled"))
+				return
+			}
+
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				problem.Write(w, r, apperr.ErrUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
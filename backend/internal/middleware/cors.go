@@ -4,16 +4,24 @@ import (
 	"net/http"
 
 	"github.com/GkadyrG/L0/backend/config"
+	"github.com/GkadyrG/L0/backend/internal/origin"
+	"github.com/GkadyrG/L0/backend/internal/requestid"
 	"github.com/go-chi/cors"
 )
 
-func CORS(cfg *config.Config) func(http.Handler) http.Handler {
+// CORS checks the request origin against origins on every request, so replacing the
+// list takes effect without rebuilding the router.
+func CORS(cfg *config.Config, origins *origin.Allowlist) func(http.Handler) http.Handler {
 	if cfg == nil || !cfg.Cors.Enabled {
 		return func(next http.Handler) http.Handler { return next }
 	}
 	return cors.Handler(cors.Options{
-		AllowedOrigins: cfg.Cors.AllowedOrigins,
+		AllowOriginFunc: func(_ *http.Request, o string) bool {
+			return origins.Allowed(o)
+		},
 		AllowedMethods: cfg.Cors.AllowedMethods,
 		AllowedHeaders: cfg.Cors.AllowedHeaders,
+		// Lets browser clients see the deprecation notice of API v1 and the request ID.
+		ExposedHeaders: []string{"Deprecation", "Sunset", "Link", requestid.Header},
 	})
 }
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/GkadyrG/L0/backend/config"
+)
+
+// Deprecated marks responses of a deprecated API version with the Deprecation
+// (RFC 9745) and Sunset (RFC 8594) headers and links to the API documentation.
+func Deprecated(cfg *config.Config) func(http.Handler) http.Handler {
+	deprecation := "@" + strconv.FormatInt(cfg.API.V1DeprecatedAt.Unix(), 10)
+	sunset := cfg.API.V1Sunset.UTC().Format(http.TimeFormat)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", deprecation)
+			w.Header().Set("Sunset", sunset)
+			w.Header().Set("Link", `</api/docs>; rel="deprecation"; type="text/html"`)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
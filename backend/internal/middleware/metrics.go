@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/metrics"
+	"github.com/go-chi/chi/v5"
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+// unmatchedRoute labels requests no route matched, keeping the route label bounded.
+const unmatchedRoute = "unmatched"
+
+// Metrics records the count and latency of every request by route pattern.
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := chimw.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		route := chi.RouteContext(r.Context()).RoutePattern()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := ww.Status()
+		switch {
+		case status != 0:
+		case r.Header.Get("Upgrade") != "":
+			// the connection was hijacked, Upgrade wrote 101 itself
+			status = http.StatusSwitchingProtocols
+		default:
+			status = http.StatusOK
+		}
+		metrics.ObserveHTTP(r.Method, route, status, time.Since(start))
+	})
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/problem"
+	"golang.org/x/time/rate"
+)
+
+// clientIdle is how long a client keeps its limiter after its last request.
+const clientIdle = 10 * time.Minute
+
+type client struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// RateLimiter gives every client IP a token bucket of burst requests refilled at rps
+// per second. SetLimit changes the limit of known clients as well.
+type RateLimiter struct {
+	mu        sync.Mutex
+	limit     rate.Limit
+	burst     int
+	clients   map[string]*client
+	lastSweep time.Time
+}
+
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	l := &RateLimiter{clients: make(map[string]*client)}
+	l.SetLimit(rps, burst)
+	return l
+}
+
+// SetLimit replaces the limit; an rps of 0 or less lets every request through.
+func (l *RateLimiter) SetLimit(rps float64, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limit, l.burst = rate.Limit(rps), max(burst, 1)
+	for _, c := range l.clients {
+		c.limiter.SetLimit(l.limit)
+		c.limiter.SetBurst(l.burst)
+	}
+}
+
+// Handler answers 429 with code rate_limited once a client runs out of tokens.
+func (l *RateLimiter) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.allow(remoteIP(r), time.Now()) {
+			w.Header().Set("Retry-After", "1")
+			problem.Write(w, r, apperr.New(apperr.ErrRateLimited, "too many requests"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (l *RateLimiter) allow(ip string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limit <= 0 {
+		return true
+	}
+
+	if now.Sub(l.lastSweep) > clientIdle {
+		for key, c := range l.clients {
+			if now.Sub(c.lastSeen) > clientIdle {
+				delete(l.clients, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	c, ok := l.clients[ip]
+	if !ok {
+		c = &client{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[ip] = c
+	}
+	c.lastSeen = now
+	return c.limiter.AllowN(now, 1)
+}
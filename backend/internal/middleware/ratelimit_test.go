@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	l := NewRateLimiter(1, 2)
+
+	assert.True(t, l.allow("10.0.0.1", now))
+	assert.True(t, l.allow("10.0.0.1", now))
+	assert.False(t, l.allow("10.0.0.1", now), "burst is used up")
+	assert.True(t, l.allow("10.0.0.2", now), "other clients have their own bucket")
+	assert.True(t, l.allow("10.0.0.1", now.Add(time.Second)), "a token is added every second")
+
+	l.SetLimit(0, 2)
+	assert.True(t, l.allow("10.0.0.1", now), "a zero limit turns limiting off")
+
+	l.SetLimit(1, 1)
+	l.allow("10.0.0.1", now)
+	assert.False(t, l.allow("10.0.0.1", now), "known clients get the new burst")
+
+	assert.True(t, l.allow("10.0.0.3", now.Add(time.Hour)))
+	assert.Len(t, l.clients, 1, "idle clients are dropped")
+}
+
+func TestRateLimiter_Handler(t *testing.T) {
+	handler := NewRateLimiter(1, 1).Handler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/orders", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/orders", nil))
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+}
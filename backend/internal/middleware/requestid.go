@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+
+	"github.com/GkadyrG/L0/backend/internal/logger"
+	"github.com/GkadyrG/L0/backend/internal/requestid"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxRequestIDLength bounds client-supplied IDs, which end up in logs and responses.
+const maxRequestIDLength = 128
+
+// RequestID keeps the X-Request-ID of the request, or assigns a new one, and returns
+// it in the response. Records logged with the request context carry the ID, the
+// method, the matched route and the client address.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestid.Header)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = requestid.New()
+		}
+
+		ctx := requestid.NewContext(r.Context(), id)
+		ctx = logger.With(ctx,
+			"request_id", id,
+			"method", r.Method,
+			"route", routeValue{rctx: chi.RouteContext(ctx)},
+			"remote_ip", remoteIP(r),
+		)
+
+		w.Header().Set(requestid.Header, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// routeValue resolves to the route pattern when a record is logged; at the time the
+// attribute is added the router has not matched the request yet.
+type routeValue struct {
+	rctx *chi.Context
+}
+
+func (v routeValue) LogValue() slog.Value {
+	if v.rctx == nil {
+		return slog.StringValue("")
+	}
+	return slog.StringValue(v.rctx.RoutePattern())
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/metrics"
+	"github.com/go-chi/chi/v5"
+)
+
+// deadlineGrace is how long after the handler deadline the connection stays
+// writable, so that the timeout response itself can still be sent.
+const deadlineGrace = time.Second
+
+// Timeout gives the request context a deadline of d. Handlers see it through their
+// store calls, which fail with a timeout mapped to 504. The connection read and
+// write deadlines are moved to match, so the server-wide timeouts never cut a
+// request with a longer budget short.
+func Timeout(d time.Duration, logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			rc := http.NewResponseController(w)
+			deadline := time.Now().Add(d + deadlineGrace)
+			_ = rc.SetReadDeadline(deadline)
+			_ = rc.SetWriteDeadline(deadline)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				route := chi.RouteContext(r.Context()).RoutePattern()
+				metrics.HandlerTimeout(route)
+				logger.WarnContext(r.Context(), "request deadline exceeded", "timeout", d)
+			}
+		})
+	}
+}
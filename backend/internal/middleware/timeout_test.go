@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/metrics"
+	"github.com/GkadyrG/L0/backend/internal/problem"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeout(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	router := chi.NewRouter()
+	router.Use(Timeout(20*time.Millisecond, logger))
+	router.Get("/fast", func(w http.ResponseWriter, r *http.Request) {
+		_, ok := r.Context().Deadline()
+		assert.True(t, ok)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	router.Get("/slow/{id}", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		problem.Write(w, r, r.Context().Err())
+	})
+
+	before := timeoutCount(t, "/slow/{id}")
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fast", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow/1", nil))
+	require.Equal(t, http.StatusGatewayTimeout, rec.Code)
+	assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+	assert.Equal(t, before+1, timeoutCount(t, "/slow/{id}"))
+}
+
+func timeoutCount(t *testing.T, route string) float64 {
+	families, err := metrics.Registry.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "orders_http_handler_timeouts_total" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "route" && l.GetValue() == route {
+					return m.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,100 @@
+package model
+
+import "time"
+
+type StatsGroup string
+
+const (
+	GroupByDay             StatsGroup = "day"
+	GroupByWeek            StatsGroup = "week"
+	GroupByDeliveryService StatsGroup = "delivery_service"
+	GroupByProvider        StatsGroup = "provider"
+	GroupByBrand           StatsGroup = "brand"
+	GroupByLocale          StatsGroup = "locale"
+)
+
+func (g StatsGroup) Valid() bool {
+	switch g {
+	case GroupByDay, GroupByWeek, GroupByDeliveryService, GroupByProvider, GroupByBrand, GroupByLocale:
+		return true
+	}
+	return false
+}
+
+type TimeRange struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+type StatsQuery struct {
+	TimeRange
+	GroupBy StatsGroup `json:"group_by"`
+}
+
+type StatsBucket struct {
+	Key     string `json:"key"`
+	Orders  int64  `json:"orders"`
+	Revenue int64  `json:"revenue"`
+}
+
+type OrderStats struct {
+	StatsQuery
+	Buckets []StatsBucket `json:"buckets"`
+}
+
+type ItemCountBucket struct {
+	Items  int   `json:"items"`
+	Orders int64 `json:"orders"`
+}
+
+type BasketStats struct {
+	TimeRange
+	Orders       int64             `json:"orders"`
+	AvgBasket    float64           `json:"avg_basket"`
+	AvgItemCount float64           `json:"avg_item_count"`
+	ItemCounts   []ItemCountBucket `json:"item_counts"`
+}
+
+type TopMetric string
+
+const (
+	TopByUnits    TopMetric = "units"
+	TopByRevenue  TopMetric = "revenue"
+	TopByDiscount TopMetric = "discount"
+)
+
+func (m TopMetric) Valid() bool {
+	switch m {
+	case TopByUnits, TopByRevenue, TopByDiscount:
+		return true
+	}
+	return false
+}
+
+type TopQuery struct {
+	TimeRange
+	Limit int       `json:"limit"`
+	By    TopMetric `json:"by"`
+}
+
+type BrandTop struct {
+	Brand       string  `json:"brand"`
+	Units       int64   `json:"units"`
+	Revenue     int64   `json:"revenue"`
+	AvgDiscount float64 `json:"avg_discount"`
+}
+
+type ProductTop struct {
+	NmID        int64   `json:"nm_id"`
+	Name        string  `json:"name"`
+	Brand       string  `json:"brand"`
+	Units       int64   `json:"units"`
+	Revenue     int64   `json:"revenue"`
+	AvgDiscount float64 `json:"avg_discount"`
+}
+
+type TopReport struct {
+	TopQuery
+	Brands   []BrandTop   `json:"brands"`
+	Products []ProductTop `json:"products"`
+}
@@ -0,0 +1,127 @@
+package model
+
+import "time"
+
+// OrderDetails is an order with every stored field and the status history of its
+// items, keyed by rid.
+type OrderDetails struct {
+	Order
+	History map[string][]StatusChange
+}
+
+// Money is an amount in the order currency.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// OrderV2 is the order representation of API v2: every stored field, with amounts
+// as Money.
+type OrderV2 struct {
+	OrderUID          string    `json:"order_uid"`
+	TrackNumber       string    `json:"track_number"`
+	Entry             string    `json:"entry"`
+	Locale            string    `json:"locale"`
+	InternalSignature string    `json:"internal_signature"`
+	CustomerID        string    `json:"customer_id"`
+	DeliveryService   string    `json:"delivery_service"`
+	ShardKey          string    `json:"shardkey"`
+	SmID              int       `json:"sm_id"`
+	DateCreated       time.Time `json:"date_created"`
+	OofShard          string    `json:"oof_shard"`
+	CreatedAt         time.Time `json:"created_at"`
+	Delivery          Delivery  `json:"delivery"`
+	Payment           PaymentV2 `json:"payment"`
+	Items             []ItemV2  `json:"items"`
+}
+
+type PaymentV2 struct {
+	Transaction  string     `json:"transaction"`
+	RequestID    string     `json:"request_id"`
+	Provider     string     `json:"provider"`
+	Bank         string     `json:"bank"`
+	Amount       Money      `json:"amount"`
+	DeliveryCost Money      `json:"delivery_cost"`
+	GoodsTotal   Money      `json:"goods_total"`
+	CustomFee    Money      `json:"custom_fee"`
+	PaidAt       *time.Time `json:"paid_at"`
+}
+
+type ItemV2 struct {
+	ChrtID      int64          `json:"chrt_id"`
+	NmID        int64          `json:"nm_id"`
+	RID         string         `json:"rid"`
+	TrackNumber string         `json:"track_number"`
+	Name        string         `json:"name"`
+	Brand       string         `json:"brand"`
+	Size        string         `json:"size"`
+	Sale        int            `json:"sale"`
+	Price       Money          `json:"price"`
+	TotalPrice  Money          `json:"total_price"`
+	Status      ItemStatus     `json:"status"`
+	StatusName  string         `json:"status_name"`
+	History     []StatusChange `json:"history"`
+}
+
+func (d OrderDetails) ToV2() *OrderV2 {
+	currency := d.Payment.Currency
+	money := func(amount int64) Money {
+		return Money{Amount: amount, Currency: currency}
+	}
+
+	payment := PaymentV2{
+		Transaction:  d.Payment.Transaction,
+		RequestID:    d.Payment.RequestID,
+		Provider:     d.Payment.Provider,
+		Bank:         d.Payment.Bank,
+		Amount:       money(d.Payment.Amount),
+		DeliveryCost: money(d.Payment.DeliveryCost),
+		GoodsTotal:   money(d.Payment.GoodsTotal),
+		CustomFee:    money(d.Payment.CustomFee),
+	}
+	if d.Payment.PaymentDT > 0 {
+		paidAt := time.Unix(d.Payment.PaymentDT, 0).UTC()
+		payment.PaidAt = &paidAt
+	}
+
+	items := make([]ItemV2, len(d.Items))
+	for i, item := range d.Items {
+		history := d.History[item.RID]
+		if history == nil {
+			history = []StatusChange{}
+		}
+		items[i] = ItemV2{
+			ChrtID:      item.ChrtID,
+			NmID:        item.NmID,
+			RID:         item.RID,
+			TrackNumber: item.TrackNumber,
+			Name:        item.Name,
+			Brand:       item.Brand,
+			Size:        item.Size,
+			Sale:        item.Sale,
+			Price:       money(item.Price),
+			TotalPrice:  money(item.TotalPrice),
+			Status:      item.Status,
+			StatusName:  item.Status.String(),
+			History:     history,
+		}
+	}
+
+	return &OrderV2{
+		OrderUID:          d.OrderUID,
+		TrackNumber:       d.TrackNumber,
+		Entry:             d.Entry,
+		Locale:            d.Locale,
+		InternalSignature: d.InternalSignature,
+		CustomerID:        d.CustomerID,
+		DeliveryService:   d.DeliveryService,
+		ShardKey:          d.ShardKey,
+		SmID:              d.SmID,
+		DateCreated:       d.DateCreated,
+		OofShard:          d.OofShard,
+		CreatedAt:         d.CreatedAt,
+		Delivery:          d.Delivery,
+		Payment:           payment,
+		Items:             items,
+	}
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventOrderStored  EventType = "order.stored"
+	EventOrderUpdated EventType = "order.updated"
+)
+
+func (t EventType) Valid() bool {
+	return t == EventOrderStored || t == EventOrderUpdated
+}
+
+// EventVersion is the version of the event payload schema. It is bumped on
+// incompatible changes so subscribers can tell payloads apart.
+const EventVersion = 1
+
+// OrderEvent is published to other services. ID is stable for a given change, so
+// subscribers can use it to drop redelivered events. order.stored events carry the
+// order summary, order.updated events carry the item status change.
+type OrderEvent struct {
+	ID         string        `json:"id"`
+	Type       EventType     `json:"type"`
+	Version    int           `json:"version"`
+	OccurredAt time.Time     `json:"occurred_at"`
+	Order      *OrderSummary `json:"order,omitempty"`
+	Item       *ItemUpdate   `json:"item,omitempty"`
+}
+
+type ItemUpdate struct {
+	OrderUID   string     `json:"order_uid"`
+	RID        string     `json:"rid"`
+	Status     ItemStatus `json:"status"`
+	StatusName string     `json:"status_name"`
+	ChangedAt  time.Time  `json:"changed_at"`
+}
+
+type OrderSummary struct {
+	OrderUID        string    `json:"order_uid"`
+	TrackNumber     string    `json:"track_number"`
+	CustomerID      string    `json:"customer_id"`
+	DeliveryService string    `json:"delivery_service"`
+	Currency        string    `json:"currency"`
+	Amount          int64     `json:"amount"`
+	ItemCount       int       `json:"item_count"`
+	DateCreated     time.Time `json:"date_created"`
+}
+
+func NewOrderStoredEvent(order *Order, occurredAt time.Time) OrderEvent {
+	return OrderEvent{
+		ID:         string(EventOrderStored) + ":" + order.OrderUID,
+		Type:       EventOrderStored,
+		Version:    EventVersion,
+		OccurredAt: occurredAt.UTC(),
+		Order: &OrderSummary{
+			OrderUID:        order.OrderUID,
+			TrackNumber:     order.TrackNumber,
+			CustomerID:      order.CustomerID,
+			DeliveryService: order.DeliveryService,
+			Currency:        order.Payment.Currency,
+			Amount:          order.Payment.Amount,
+			ItemCount:       len(order.Items),
+			DateCreated:     order.DateCreated,
+		},
+	}
+}
+
+// NewOrderUpdatedEvent describes an item status change. An item reaches each status
+// at most once, so the status is enough to make the id unique.
+func NewOrderUpdatedEvent(event *ItemStatusEvent, occurredAt time.Time) OrderEvent {
+	return OrderEvent{
+		ID:         fmt.Sprintf("%s:%s:%s:%s", EventOrderUpdated, event.OrderUID, event.RID, event.Status),
+		Type:       EventOrderUpdated,
+		Version:    EventVersion,
+		OccurredAt: occurredAt.UTC(),
+		Item: &ItemUpdate{
+			OrderUID:   event.OrderUID,
+			RID:        event.RID,
+			Status:     event.Status,
+			StatusName: event.Status.String(),
+			ChangedAt:  event.ChangedAt,
+		},
+	}
+}
+
+// OutboxMessage is an event waiting in the outbox to be published.
+type OutboxMessage struct {
+	ID          int64
+	EventID     string
+	EventType   EventType
+	AggregateID string
+	Payload     []byte
+	Attempts    int
+}
+
+type OutboxBacklog struct {
+	Pending       int        `json:"pending"`
+	Failing       int        `json:"failing"`
+	OldestPending *time.Time `json:"oldest_pending,omitempty"`
+}
+
+type RelayStats struct {
+	Running       bool       `json:"running"`
+	Published     int64      `json:"published"`
+	Failures      int64      `json:"failures"`
+	LastPublishAt *time.Time `json:"last_publish_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastErrorAt   *time.Time `json:"last_error_at,omitempty"`
+}
+
+type OutboxState struct {
+	Topic   string        `json:"topic"`
+	Relay   RelayStats    `json:"relay"`
+	Backlog OutboxBacklog `json:"backlog"`
+}
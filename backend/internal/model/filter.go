@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// OrderFilter narrows order listings and exports. Zero values are ignored.
+type OrderFilter struct {
+	CustomerID      string
+	DeliveryService string
+	From            time.Time
+	To              time.Time
+}
+
+// ExportRow is one order joined with one of its items. Item is nil for orders without items.
+type ExportRow struct {
+	Order Order
+	Item  *Item
+}
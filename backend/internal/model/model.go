@@ -45,17 +45,17 @@ type Payment struct {
 }
 
 type Item struct {
-	ChrtID      int64  `json:"chrt_id" validate:"required"`
-	TrackNumber string `json:"track_number" validate:"required"`
-	Price       int64  `json:"price" validate:"required,gt=0"`
-	RID         string `json:"rid" validate:"required"`
-	Name        string `json:"name" validate:"required"`
-	Sale        int    `json:"sale" validate:"gte=0"`
-	Size        string `json:"size"`
-	TotalPrice  int64  `json:"total_price" validate:"required,gt=0"`
-	NmID        int64  `json:"nm_id"`
-	Brand       string `json:"brand" validate:"required"`
-	Status      int    `json:"status" validate:"required,gt=0"`
+	ChrtID      int64      `json:"chrt_id" validate:"required"`
+	TrackNumber string     `json:"track_number" validate:"required"`
+	Price       int64      `json:"price" validate:"required,gt=0"`
+	RID         string     `json:"rid" validate:"required"`
+	Name        string     `json:"name" validate:"required"`
+	Sale        int        `json:"sale" validate:"gte=0"`
+	Size        string     `json:"size"`
+	TotalPrice  int64      `json:"total_price" validate:"required,gt=0"`
+	NmID        int64      `json:"nm_id"`
+	Brand       string     `json:"brand" validate:"required"`
+	Status      ItemStatus `json:"status" validate:"required,item_status"`
 }
 
 type OrderPreview struct {
@@ -66,13 +66,14 @@ type OrderPreview struct {
 }
 
 type OrderResponse struct {
-	OrderUID    string           `json:"order_uid"`
-	TrackNumber string           `json:"track_number"`
-	CustomerID  string           `json:"customer_id"`
-	DateCreated time.Time        `json:"date_created"`
-	Delivery    DeliveryResponse `json:"delivery"`
-	Payment     PaymentResponse  `json:"payment"`
-	Items       []ItemResponse   `json:"items"`
+	OrderUID        string           `json:"order_uid"`
+	TrackNumber     string           `json:"track_number"`
+	CustomerID      string           `json:"customer_id"`
+	DeliveryService string           `json:"delivery_service"`
+	DateCreated     time.Time        `json:"date_created"`
+	Delivery        DeliveryResponse `json:"delivery"`
+	Payment         PaymentResponse  `json:"payment"`
+	Items           []ItemResponse   `json:"items"`
 }
 
 type DeliveryResponse struct {
@@ -87,13 +88,17 @@ type PaymentResponse struct {
 	Transaction string `json:"transaction"`
 	Currency    string `json:"currency"`
 	Amount      int64  `json:"amount"`
+	PaymentDT   int64  `json:"payment_dt"`
 }
 
 type ItemResponse struct {
-	Name   string `json:"name"`
-	Price  int64  `json:"price"`
-	Brand  string `json:"brand"`
-	Status int    `json:"status"`
+	RID        string         `json:"rid"`
+	Name       string         `json:"name"`
+	Price      int64          `json:"price"`
+	Brand      string         `json:"brand"`
+	Status     ItemStatus     `json:"status"`
+	StatusName string         `json:"status_name"`
+	History    []StatusChange `json:"history"`
 }
 
 func (o Order) ToResponse() *OrderResponse {
@@ -109,24 +114,30 @@ func (o Order) ToResponse() *OrderResponse {
 		Transaction: o.Payment.Transaction,
 		Currency:    o.Payment.Currency,
 		Amount:      o.Payment.Amount,
+		PaymentDT:   o.Payment.PaymentDT,
 	}
 
 	items := make([]ItemResponse, len(o.Items))
 	for i, item := range o.Items {
 		items[i] = ItemResponse{
-			Name:  item.Name,
-			Price: item.Price,
-			Brand: item.Brand,
+			RID:        item.RID,
+			Name:       item.Name,
+			Price:      item.Price,
+			Brand:      item.Brand,
+			Status:     item.Status,
+			StatusName: item.Status.String(),
+			History:    []StatusChange{NewStatusChange(item.Status, o.DateCreated)},
 		}
 	}
 
 	return &OrderResponse{
-		OrderUID:    o.OrderUID,
-		TrackNumber: o.TrackNumber,
-		CustomerID:  o.CustomerID,
-		DateCreated: o.DateCreated,
-		Delivery:    delivery,
-		Payment:     payment,
-		Items:       items,
+		OrderUID:        o.OrderUID,
+		TrackNumber:     o.TrackNumber,
+		CustomerID:      o.CustomerID,
+		DeliveryService: o.DeliveryService,
+		DateCreated:     o.DateCreated,
+		Delivery:        delivery,
+		Payment:         payment,
+		Items:           items,
 	}
 }
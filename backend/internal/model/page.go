@@ -0,0 +1,48 @@
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+)
+
+// OrderCursor is the position of an order in listings sorted by date_created and
+// order_uid, newest first. It is handed to clients as an opaque string.
+type OrderCursor struct {
+	DateCreated time.Time
+	OrderUID    string
+}
+
+func CursorOf(p *OrderPreview) OrderCursor {
+	return OrderCursor{DateCreated: p.DateCreated, OrderUID: p.OrderUID}
+}
+
+func (c OrderCursor) String() string {
+	raw := c.DateCreated.UTC().Format(time.RFC3339Nano) + "|" + c.OrderUID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func ParseOrderCursor(s string) (OrderCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return OrderCursor{}, errors.New("malformed cursor")
+	}
+
+	ts, uid, ok := strings.Cut(string(raw), "|")
+	if !ok || uid == "" {
+		return OrderCursor{}, errors.New("malformed cursor")
+	}
+	dateCreated, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return OrderCursor{}, errors.New("malformed cursor")
+	}
+
+	return OrderCursor{DateCreated: dateCreated, OrderUID: uid}, nil
+}
+
+// OrderPage is one page of a keyset-paginated order listing.
+type OrderPage struct {
+	Orders      []*OrderPreview
+	HasNextPage bool
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderCursor(t *testing.T) {
+	c := OrderCursor{DateCreated: time.Date(2024, 1, 2, 3, 4, 5, 123456000, time.UTC), OrderUID: "b563|feb7b2b84b6test"}
+
+	got, err := ParseOrderCursor(c.String())
+	require.NoError(t, err)
+	assert.Equal(t, c, got)
+
+	for _, s := range []string{"", "not base64!", "bm8tc2VwYXJhdG9y", "eWVzdGVyZGF5fHVpZA"} {
+		_, err := ParseOrderCursor(s)
+		assert.Error(t, err, s)
+	}
+}
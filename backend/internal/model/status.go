@@ -0,0 +1,100 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type ItemStatus int
+
+const (
+	ItemStatusCreated ItemStatus = iota + 1
+	ItemStatusPaid
+	ItemStatusAssembled
+	ItemStatusShipped
+	ItemStatusDelivered
+	ItemStatusCancelled
+	ItemStatusReturned
+)
+
+var itemStatusNames = map[ItemStatus]string{
+	ItemStatusCreated:   "created",
+	ItemStatusPaid:      "paid",
+	ItemStatusAssembled: "assembled",
+	ItemStatusShipped:   "shipped",
+	ItemStatusDelivered: "delivered",
+	ItemStatusCancelled: "cancelled",
+	ItemStatusReturned:  "returned",
+}
+
+// legacyItemStatuses maps the raw codes producers sent before statuses were an
+// enum to their enum value.
+var legacyItemStatuses = map[ItemStatus]ItemStatus{
+	202: ItemStatusCreated,
+}
+
+// itemTransitions lists the statuses an item may move to from each state.
+// Statuses without an entry are terminal.
+var itemTransitions = map[ItemStatus][]ItemStatus{
+	ItemStatusCreated:   {ItemStatusPaid, ItemStatusCancelled},
+	ItemStatusPaid:      {ItemStatusAssembled, ItemStatusCancelled},
+	ItemStatusAssembled: {ItemStatusShipped, ItemStatusCancelled},
+	ItemStatusShipped:   {ItemStatusDelivered, ItemStatusReturned},
+	ItemStatusDelivered: {ItemStatusReturned},
+}
+
+func (s ItemStatus) String() string {
+	if name, ok := itemStatusNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+func (s ItemStatus) Valid() bool {
+	_, ok := itemStatusNames[s]
+	return ok
+}
+
+// UnmarshalJSON accepts the legacy codes as well, so producers that still send
+// them keep working.
+func (s *ItemStatus) UnmarshalJSON(b []byte) error {
+	var code int
+	if err := json.Unmarshal(b, &code); err != nil {
+		return err
+	}
+	*s = ItemStatus(code)
+	if status, ok := legacyItemStatuses[*s]; ok {
+		*s = status
+	}
+	return nil
+}
+
+func (s ItemStatus) CanTransitionTo(next ItemStatus) bool {
+	for _, allowed := range itemTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+type StatusChange struct {
+	Status     ItemStatus `json:"status"`
+	StatusName string     `json:"status_name"`
+	ChangedAt  time.Time  `json:"changed_at"`
+}
+
+func NewStatusChange(status ItemStatus, changedAt time.Time) StatusChange {
+	return StatusChange{
+		Status:     status,
+		StatusName: status.String(),
+		ChangedAt:  changedAt,
+	}
+}
+
+type ItemStatusEvent struct {
+	OrderUID  string     `json:"order_uid" validate:"required"`
+	RID       string     `json:"rid" validate:"required"`
+	Status    ItemStatus `json:"status" validate:"required,item_status"`
+	ChangedAt time.Time  `json:"changed_at" validate:"required"`
+}
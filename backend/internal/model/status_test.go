@@ -0,0 +1,69 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemStatus_CanTransitionTo(t *testing.T) {
+	type testCase struct {
+		name string
+		from ItemStatus
+		to   ItemStatus
+		want bool
+	}
+
+	tests := []testCase{
+		{name: "created to paid", from: ItemStatusCreated, to: ItemStatusPaid, want: true},
+		{name: "paid to assembled", from: ItemStatusPaid, to: ItemStatusAssembled, want: true},
+		{name: "assembled to shipped", from: ItemStatusAssembled, to: ItemStatusShipped, want: true},
+		{name: "shipped to delivered", from: ItemStatusShipped, to: ItemStatusDelivered, want: true},
+		{name: "delivered to returned", from: ItemStatusDelivered, to: ItemStatusReturned, want: true},
+		{name: "created to cancelled", from: ItemStatusCreated, to: ItemStatusCancelled, want: true},
+		{name: "skip payment", from: ItemStatusCreated, to: ItemStatusShipped, want: false},
+		{name: "backwards", from: ItemStatusShipped, to: ItemStatusPaid, want: false},
+		{name: "same status", from: ItemStatusPaid, to: ItemStatusPaid, want: false},
+		{name: "cancelled is terminal", from: ItemStatusCancelled, to: ItemStatusPaid, want: false},
+		{name: "returned is terminal", from: ItemStatusReturned, to: ItemStatusDelivered, want: false},
+		{name: "unknown status", from: ItemStatus(202), to: ItemStatusPaid, want: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.from.CanTransitionTo(tc.to))
+		})
+	}
+}
+
+func TestItemStatus_String(t *testing.T) {
+	assert.Equal(t, "shipped", ItemStatusShipped.String())
+	assert.Equal(t, "unknown", ItemStatus(202).String())
+	assert.False(t, ItemStatus(0).Valid())
+	assert.True(t, ItemStatusReturned.Valid())
+}
+
+func TestItemStatus_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want ItemStatus
+	}{
+		{name: "enum", raw: `4`, want: ItemStatusShipped},
+		{name: "legacy code", raw: `202`, want: ItemStatusCreated},
+		{name: "unknown code kept", raw: `500`, want: ItemStatus(500)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var event ItemStatusEvent
+			require.NoError(t, json.Unmarshal([]byte(`{"status": `+tc.raw+`}`), &event))
+			assert.Equal(t, tc.want, event.Status)
+		})
+	}
+
+	var status ItemStatus
+	assert.Error(t, json.Unmarshal([]byte(`"paid"`), &status))
+}
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+type OrderStatus string
+
+const (
+	OrderStatusCreated   OrderStatus = "created"
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusAssembled OrderStatus = "assembled"
+	OrderStatusShipped   OrderStatus = "shipped"
+	OrderStatusDelivered OrderStatus = "delivered"
+	OrderStatusCancelled OrderStatus = "cancelled"
+	OrderStatusReturned  OrderStatus = "returned"
+)
+
+const (
+	EventOrderCreated      = "order_created"
+	EventPaymentReceived   = "payment_received"
+	EventItemStatusChanged = "item_status_changed"
+)
+
+type TimelineEvent struct {
+	Type        string    `json:"type"`
+	At          time.Time `json:"at"`
+	RID         string    `json:"rid,omitempty"`
+	Status      string    `json:"status,omitempty"`
+	Description string    `json:"description"`
+}
+
+type OrderTimeline struct {
+	OrderUID  string          `json:"order_uid"`
+	Status    OrderStatus     `json:"status"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	Events    []TimelineEvent `json:"events"`
+}
+
+// DeriveOrderStatus folds item statuses and payment data into a single order status.
+// Cancelled items are ignored unless every item is cancelled.
+func DeriveOrderStatus(items []ItemResponse, paid bool) OrderStatus {
+	counts := make(map[ItemStatus]int)
+	for _, item := range items {
+		counts[item.Status]++
+	}
+
+	active := len(items) - counts[ItemStatusCancelled]
+	switch {
+	case len(items) > 0 && active == 0:
+		return OrderStatusCancelled
+	case active > 0 && counts[ItemStatusReturned] == active:
+		return OrderStatusReturned
+	case active > 0 && counts[ItemStatusDelivered]+counts[ItemStatusReturned] == active:
+		return OrderStatusDelivered
+	case counts[ItemStatusShipped] > 0 || counts[ItemStatusDelivered] > 0:
+		return OrderStatusShipped
+	case active > 0 && counts[ItemStatusAssembled] == active:
+		return OrderStatusAssembled
+	case paid || counts[ItemStatusPaid] > 0 || counts[ItemStatusAssembled] > 0:
+		return OrderStatusPaid
+	default:
+		return OrderStatusCreated
+	}
+}
+
+func (o OrderResponse) Timeline() *OrderTimeline {
+	events := []TimelineEvent{{
+		Type:        EventOrderCreated,
+		At:          o.DateCreated,
+		Status:      string(OrderStatusCreated),
+		Description: "order created",
+	}}
+
+	if o.Payment.PaymentDT > 0 {
+		events = append(events, TimelineEvent{
+			Type:        EventPaymentReceived,
+			At:          time.Unix(o.Payment.PaymentDT, 0).UTC(),
+			Status:      string(OrderStatusPaid),
+			Description: fmt.Sprintf("payment of %d %s received", o.Payment.Amount, o.Payment.Currency),
+		})
+	}
+
+	for _, item := range o.Items {
+		for _, change := range item.History {
+			// the initial "created" entry is already covered by the order_created event
+			if change.Status == ItemStatusCreated {
+				continue
+			}
+			events = append(events, TimelineEvent{
+				Type:        EventItemStatusChanged,
+				At:          change.ChangedAt,
+				RID:         item.RID,
+				Status:      change.StatusName,
+				Description: o.itemEventDescription(item, change.Status),
+			})
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].At.Before(events[j].At)
+	})
+
+	return &OrderTimeline{
+		OrderUID:  o.OrderUID,
+		Status:    DeriveOrderStatus(o.Items, o.Payment.PaymentDT > 0),
+		UpdatedAt: events[len(events)-1].At,
+		Events:    events,
+	}
+}
+
+func (o OrderResponse) itemEventDescription(item ItemResponse, status ItemStatus) string {
+	if status == ItemStatusShipped && o.DeliveryService != "" {
+		return fmt.Sprintf("%s shipped via %s", item.Name, o.DeliveryService)
+	}
+	return fmt.Sprintf("%s %s", item.Name, status)
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeriveOrderStatus(t *testing.T) {
+	type testCase struct {
+		name     string
+		statuses []ItemStatus
+		paid     bool
+		want     OrderStatus
+	}
+
+	tests := []testCase{
+		{name: "no items", want: OrderStatusCreated},
+		{name: "created unpaid", statuses: []ItemStatus{ItemStatusCreated}, want: OrderStatusCreated},
+		{name: "created with payment", statuses: []ItemStatus{ItemStatusCreated}, paid: true, want: OrderStatusPaid},
+		{name: "all assembled", statuses: []ItemStatus{ItemStatusAssembled, ItemStatusAssembled}, want: OrderStatusAssembled},
+		{name: "partially shipped", statuses: []ItemStatus{ItemStatusShipped, ItemStatusPaid}, want: OrderStatusShipped},
+		{name: "delivered with cancelled item", statuses: []ItemStatus{ItemStatusDelivered, ItemStatusCancelled}, want: OrderStatusDelivered},
+		{name: "all cancelled", statuses: []ItemStatus{ItemStatusCancelled, ItemStatusCancelled}, paid: true, want: OrderStatusCancelled},
+		{name: "all returned", statuses: []ItemStatus{ItemStatusReturned}, want: OrderStatusReturned},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			items := make([]ItemResponse, len(tc.statuses))
+			for i, status := range tc.statuses {
+				items[i] = ItemResponse{Status: status}
+			}
+			assert.Equal(t, tc.want, DeriveOrderStatus(items, tc.paid))
+		})
+	}
+}
+
+func TestOrderResponse_Timeline(t *testing.T) {
+	created := time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC)
+	shipped := created.Add(48 * time.Hour)
+
+	order := OrderResponse{
+		OrderUID:        "order-1",
+		DeliveryService: "meest",
+		DateCreated:     created,
+		Payment: PaymentResponse{
+			Currency:  "USD",
+			Amount:    1817,
+			PaymentDT: created.Add(time.Hour).Unix(),
+		},
+		Items: []ItemResponse{{
+			RID:    "rid-1",
+			Name:   "Mascaras",
+			Status: ItemStatusShipped,
+			History: []StatusChange{
+				NewStatusChange(ItemStatusCreated, created),
+				NewStatusChange(ItemStatusShipped, shipped),
+				NewStatusChange(ItemStatusPaid, created.Add(2*time.Hour)),
+			},
+		}},
+	}
+
+	timeline := order.Timeline()
+
+	assert.Equal(t, OrderStatusShipped, timeline.Status)
+	assert.Equal(t, shipped, timeline.UpdatedAt)
+	if assert.Len(t, timeline.Events, 4) {
+		assert.Equal(t, EventOrderCreated, timeline.Events[0].Type)
+		assert.Equal(t, EventPaymentReceived, timeline.Events[1].Type)
+		assert.Equal(t, "paid", timeline.Events[2].Status)
+		assert.Equal(t, "Mascaras shipped via meest", timeline.Events[3].Description)
+	}
+}
@@ -0,0 +1,70 @@
+package model
+
+import "time"
+
+type Webhook struct {
+	ID         int64       `json:"id"`
+	URL        string      `json:"url"`
+	EventTypes []EventType `json:"event_types"`
+	Enabled    bool        `json:"enabled"`
+	// Secret is only returned when the webhook is created.
+	Secret              string     `json:"secret,omitempty"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// WebhookInput creates a webhook or, with nil fields left unchanged, updates one.
+// An empty event type list subscribes to every event.
+type WebhookInput struct {
+	URL        *string     `json:"url"`
+	EventTypes []EventType `json:"event_types"`
+	Secret     *string     `json:"secret"`
+	Enabled    *bool       `json:"enabled"`
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+type WebhookDelivery struct {
+	ID             int64          `json:"id"`
+	WebhookID      int64          `json:"webhook_id"`
+	EventID        string         `json:"event_id"`
+	EventType      EventType      `json:"event_type"`
+	Status         DeliveryStatus `json:"status"`
+	Attempts       int            `json:"attempts"`
+	LastStatusCode *int           `json:"last_status_code,omitempty"`
+	LastError      *string        `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time      `json:"next_attempt_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty"`
+}
+
+// PendingDelivery is a delivery claimed by the dispatcher together with what it
+// needs to send it.
+type PendingDelivery struct {
+	ID        int64
+	WebhookID int64
+	URL       string
+	Secret    string
+	EventID   string
+	EventType EventType
+	Payload   []byte
+	Attempts  int
+}
+
+// DeliveryFailure records a failed attempt. A nil RetryAt means the delivery is
+// given up. The webhook is disabled once it has DisableAfter consecutive failures.
+type DeliveryFailure struct {
+	DeliveryID   int64
+	WebhookID    int64
+	StatusCode   int
+	Error        string
+	RetryAt      *time.Time
+	DisableAfter int
+}
@@ -0,0 +1,35 @@
+// Package origin holds the origins allowed to call the API from a browser. The list
+// can be replaced while the service runs.
+package origin
+
+import (
+	"slices"
+	"sync/atomic"
+)
+
+const wildcard = "*"
+
+type Allowlist struct {
+	origins atomic.Pointer[[]string]
+}
+
+func NewAllowlist(origins []string) *Allowlist {
+	a := &Allowlist{}
+	a.Set(origins)
+	return a
+}
+
+func (a *Allowlist) Set(origins []string) {
+	origins = slices.Clone(origins)
+	a.origins.Store(&origins)
+}
+
+func (a *Allowlist) Origins() []string {
+	return slices.Clone(*a.origins.Load())
+}
+
+// Allowed reports whether origin is listed; "*" allows every origin.
+func (a *Allowlist) Allowed(origin string) bool {
+	origins := *a.origins.Load()
+	return slices.Contains(origins, wildcard) || slices.Contains(origins, origin)
+}
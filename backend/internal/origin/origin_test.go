@@ -0,0 +1,20 @@
+package origin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowlist(t *testing.T) {
+	a := NewAllowlist([]string{"https://shop.example"})
+	assert.True(t, a.Allowed("https://shop.example"))
+	assert.False(t, a.Allowed("https://evil.example"))
+
+	a.Set([]string{"*"})
+	assert.True(t, a.Allowed("https://evil.example"))
+
+	a.Set(nil)
+	assert.False(t, a.Allowed("https://shop.example"))
+	assert.Empty(t, a.Origins())
+}
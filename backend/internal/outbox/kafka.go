@@ -0,0 +1,57 @@
+package outbox
+
+import (
+	"context"
+
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/IBM/sarama"
+	"github.com/pkg/errors"
+)
+
+const (
+	HeaderEventID   = "event-id"
+	HeaderEventType = "event-type"
+)
+
+// KafkaPublisher sends events keyed by order_uid, so events of one order stay in one
+// partition. The event id travels in the event-id header for deduplication.
+type KafkaPublisher struct {
+	producer sarama.SyncProducer
+	topic    string
+}
+
+func NewKafkaPublisher(brokers []string, topic string) (*KafkaPublisher, error) {
+	cfg := sarama.NewConfig()
+	cfg.Version = sarama.V2_8_0_0
+	cfg.Producer.RequiredAcks = sarama.WaitForAll
+	cfg.Producer.Idempotent = true
+	cfg.Producer.Return.Successes = true
+	cfg.Net.MaxOpenRequests = 1
+
+	p, err := sarama.NewSyncProducer(brokers, cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "new sync producer")
+	}
+	return &KafkaPublisher{producer: p, topic: topic}, nil
+}
+
+func (p *KafkaPublisher) Publish(_ context.Context, msgs []model.OutboxMessage) error {
+	batch := make([]*sarama.ProducerMessage, len(msgs))
+	for i, m := range msgs {
+		batch[i] = &sarama.ProducerMessage{
+			Topic: p.topic,
+			Key:   sarama.StringEncoder(m.AggregateID),
+			Value: sarama.ByteEncoder(m.Payload),
+			Headers: []sarama.RecordHeader{
+				{Key: []byte(HeaderEventID), Value: []byte(m.EventID)},
+				{Key: []byte(HeaderEventType), Value: []byte(m.EventType)},
+			},
+		}
+	}
+
+	return errors.Wrap(p.producer.SendMessages(batch), "send outbox events")
+}
+
+func (p *KafkaPublisher) Close() error {
+	return p.producer.Close()
+}
@@ -0,0 +1,148 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/config"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/repository"
+)
+
+// Publisher delivers a batch of outbox events. It must not report success unless
+// every event in the batch was accepted.
+type Publisher interface {
+	Publish(ctx context.Context, msgs []model.OutboxMessage) error
+}
+
+// Relay moves events from the outbox table to the publisher. Events are marked
+// published only after the publisher accepts them, so delivery is at-least-once:
+// a crash between the two steps republishes the batch with the same event ids.
+type Relay struct {
+	repo      repository.OutboxRepository
+	publisher Publisher
+	topic     string
+	interval  time.Duration
+	batchSize int
+	lease     time.Duration
+	logger    *slog.Logger
+
+	mu    sync.Mutex
+	stats model.RelayStats
+}
+
+func NewRelay(cfg *config.Config, repo repository.OutboxRepository, publisher Publisher, logger *slog.Logger) *Relay {
+	return &Relay{
+		repo:      repo,
+		publisher: publisher,
+		topic:     cfg.Outbox.Topic,
+		interval:  cfg.Outbox.PollInterval,
+		batchSize: max(cfg.Outbox.BatchSize, 1),
+		lease:     cfg.Outbox.Lease,
+		logger:    logger,
+	}
+}
+
+// Run polls the outbox until ctx is cancelled. A full batch is followed by the next
+// one right away; otherwise the relay waits for the poll interval.
+func (r *Relay) Run(ctx context.Context) {
+	r.setRunning(true)
+	defer r.setRunning(false)
+
+	r.logger.Info("outbox relay started", "topic", r.topic)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		// A full batch means more events are waiting: publish them back to back
+		// instead of one batch per tick, until the backlog is drained or we stop.
+		for ctx.Err() == nil {
+			if r.relayBatch(ctx) < r.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("outbox relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Relay) relayBatch(ctx context.Context) int {
+	n, err := r.publishBatch(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UTC()
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.Error("failed to publish outbox events", "error", err)
+		}
+		r.stats.Failures++
+		r.stats.LastError = err.Error()
+		r.stats.LastErrorAt = &now
+		return 0
+	}
+
+	if n > 0 {
+		r.stats.Published += int64(n)
+		r.stats.LastPublishAt = &now
+		r.logger.Debug("outbox events published", "count", n)
+	}
+	return n
+}
+
+// publishBatch claims a batch, publishes it and records the outcome. The claim is
+// committed before publishing, so no transaction stays open while the broker is
+// slow; events whose outcome is not recorded are claimed again after the lease.
+func (r *Relay) publishBatch(ctx context.Context) (int, error) {
+	msgs, err := r.repo.ClaimOutbox(ctx, r.batchSize, r.lease)
+	if err != nil || len(msgs) == 0 {
+		return 0, err
+	}
+
+	ids := make([]int64, len(msgs))
+	for i, m := range msgs {
+		ids[i] = m.ID
+	}
+
+	if pubErr := r.publisher.Publish(ctx, msgs); pubErr != nil {
+		if err := r.repo.FailOutbox(context.WithoutCancel(ctx), ids, pubErr.Error()); err != nil {
+			r.logger.Error("failed to record outbox failure", "error", err)
+		}
+		return 0, pubErr
+	}
+
+	if err := r.repo.MarkOutboxPublished(context.WithoutCancel(ctx), ids); err != nil {
+		return 0, err
+	}
+	return len(msgs), nil
+}
+
+func (r *Relay) setRunning(running bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats.Running = running
+}
+
+func (r *Relay) Stats() model.RelayStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stats
+}
+
+// State reports the relay counters together with the backlog still in the table.
+func (r *Relay) State(ctx context.Context) (*model.OutboxState, error) {
+	backlog, err := r.repo.OutboxBacklog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &model.OutboxState{Topic: r.topic, Relay: r.Stats(), Backlog: *backlog}, nil
+}
@@ -0,0 +1,85 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/config"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePublisher struct {
+	err       error
+	published []model.OutboxMessage
+}
+
+func (p *fakePublisher) Publish(_ context.Context, msgs []model.OutboxMessage) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.published = append(p.published, msgs...)
+	return nil
+}
+
+func newTestRelay(repo *mocks.OutboxRepository, pub Publisher) *Relay {
+	cfg := &config.Config{Outbox: config.OutboxConfig{Topic: "order-stored-events", PollInterval: time.Hour, BatchSize: 2, Lease: time.Minute}}
+	return NewRelay(cfg, repo, pub, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestRelay_DrainsFullBatches(t *testing.T) {
+	msgs := []model.OutboxMessage{
+		{ID: 1, EventID: "order.stored:a", EventType: model.EventOrderStored, AggregateID: "a"},
+		{ID: 2, EventID: "order.stored:b", EventType: model.EventOrderStored, AggregateID: "b"},
+	}
+
+	repo := mocks.NewOutboxRepository(t)
+	repo.On("ClaimOutbox", mock.Anything, 2, time.Minute).Return(msgs, nil).Once()
+	repo.On("MarkOutboxPublished", mock.Anything, []int64{1, 2}).Return(nil).Once()
+	repo.On("ClaimOutbox", mock.Anything, 2, time.Minute).Return(nil, nil).Once()
+
+	pub := &fakePublisher{}
+	relay := newTestRelay(repo, pub)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		relay.Run(ctx)
+	}()
+
+	require.Eventually(t, func() bool { return relay.Stats().Published == 2 }, time.Second, 5*time.Millisecond)
+	cancel()
+	<-done
+
+	assert.Equal(t, msgs, pub.published)
+	assert.False(t, relay.Stats().Running)
+}
+
+func TestRelay_RecordsFailures(t *testing.T) {
+	msgs := []model.OutboxMessage{{ID: 1, EventID: "order.stored:a", AggregateID: "a"}}
+
+	repo := mocks.NewOutboxRepository(t)
+	repo.On("ClaimOutbox", mock.Anything, 2, time.Minute).Return(msgs, nil).Once()
+	repo.On("FailOutbox", mock.Anything, []int64{1}, "broker unavailable").Return(nil).Once()
+	repo.On("OutboxBacklog", mock.Anything).Return(&model.OutboxBacklog{Pending: 1, Failing: 1}, nil).Once()
+
+	relay := newTestRelay(repo, &fakePublisher{err: errors.New("broker unavailable")})
+
+	assert.Equal(t, 0, relay.relayBatch(context.Background()))
+
+	state, err := relay.State(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "order-stored-events", state.Topic)
+	assert.Equal(t, int64(0), state.Relay.Published)
+	assert.Equal(t, int64(1), state.Relay.Failures)
+	assert.Equal(t, "broker unavailable", state.Relay.LastError)
+	assert.Equal(t, 1, state.Backlog.Failing)
+}
@@ -0,0 +1,90 @@
+// Package problem writes errors as RFC 7807 application/problem+json responses.
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/requestid"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const ContentType = "application/problem+json"
+
+// Problem is the response body. Type is always about:blank, so clients should
+// switch on Code rather than on Title or Detail.
+type Problem struct {
+	Type      string      `json:"type"`
+	Title     string      `json:"title"`
+	Status    int         `json:"status"`
+	Detail    string      `json:"detail,omitempty"`
+	Instance  string      `json:"instance,omitempty"`
+	Code      apperr.Code `json:"code"`
+	RequestID string      `json:"request_id,omitempty"`
+	TraceID   string      `json:"trace_id,omitempty"`
+}
+
+var statuses = map[apperr.Code]int{
+	apperr.CodeNotFound:        http.StatusNotFound,
+	apperr.CodeInvalidArgument: http.StatusBadRequest,
+	apperr.CodeConflict:        http.StatusConflict,
+	apperr.CodeUnavailable:     http.StatusServiceUnavailable,
+	apperr.CodeTimeout:         http.StatusGatewayTimeout,
+	apperr.CodeRateLimited:     http.StatusTooManyRequests,
+	apperr.CodeUnauthorized:    http.StatusUnauthorized,
+	apperr.CodeForbidden:       http.StatusForbidden,
+	apperr.CodeInternal:        http.StatusInternalServerError,
+}
+
+// Status returns the HTTP status for code.
+func Status(code apperr.Code) int {
+	if status, ok := statuses[code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// New describes err as a problem for the request r. Internal errors get a generic
+// detail so that nothing about the failure leaks to the client.
+func New(r *http.Request, err error) *Problem {
+	code := apperr.CodeOf(err)
+	status := Status(code)
+
+	detail := apperr.Message(err)
+	if detail == "" {
+		detail = "internal server error"
+	}
+
+	return &Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		Code:      code,
+		RequestID: requestid.FromContext(r.Context()),
+		TraceID:   traceID(r),
+	}
+}
+
+// Write responds to r with err as a problem.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	p := New(r, err)
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// traceID returns the trace the request belongs to: the one of its server span or,
+// for requests not seen by the tracing middleware, of a W3C traceparent header.
+func traceID(r *http.Request) string {
+	ctx := propagation.TraceContext{}.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		return sc.TraceID().String()
+	}
+	return ""
+}
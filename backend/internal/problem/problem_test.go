@@ -0,0 +1,71 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		traceparent string
+		want        Problem
+	}{
+		{
+			name:        "not found",
+			err:         apperr.New(apperr.ErrNotFound, "order not found"),
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			want: Problem{
+				Type: "about:blank", Title: "Not Found", Status: http.StatusNotFound, Detail: "order not found",
+				Instance: "/api/order/x", Code: apperr.CodeNotFound, RequestID: "req-1",
+				TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			},
+		},
+		{
+			name: "timeout",
+			err:  apperr.ErrTimeout,
+			want: Problem{
+				Type: "about:blank", Title: "Gateway Timeout", Status: http.StatusGatewayTimeout, Detail: "timeout",
+				Instance: "/api/order/x", Code: apperr.CodeTimeout, RequestID: "req-1",
+			},
+		},
+		{
+			name:        "internal error hides details",
+			err:         errors.New("pq: password authentication failed"),
+			traceparent: "garbage",
+			want: Problem{
+				Type: "about:blank", Title: "Internal Server Error", Status: http.StatusInternalServerError,
+				Detail: "internal server error", Instance: "/api/order/x", Code: apperr.CodeInternal, RequestID: "req-1",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/order/x", nil)
+			req = req.WithContext(requestid.NewContext(req.Context(), "req-1"))
+			if tc.traceparent != "" {
+				req.Header.Set("traceparent", tc.traceparent)
+			}
+
+			rec := httptest.NewRecorder()
+			Write(rec, req, tc.err)
+
+			assert.Equal(t, tc.want.Status, rec.Code)
+			assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+
+			var got Problem
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
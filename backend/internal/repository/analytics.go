@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/pkg/errors"
+)
+
+// statsKeys maps a grouping to the SQL expression used as the bucket key.
+// Only values from this map are ever interpolated into queries.
+var statsKeys = map[model.StatsGroup]string{
+	model.GroupByDay:             `to_char(date_trunc('day', o.date_created AT TIME ZONE 'UTC'), 'YYYY-MM-DD')`,
+	model.GroupByWeek:            `to_char(date_trunc('week', o.date_created AT TIME ZONE 'UTC'), 'YYYY-MM-DD')`,
+	model.GroupByDeliveryService: `COALESCE(o.delivery_service, '')`,
+	model.GroupByProvider:        `COALESCE(p.provider, '')`,
+	model.GroupByLocale:          `COALESCE(o.locale, '')`,
+}
+
+func (r *Repo) OrderStats(ctx context.Context, q model.StatsQuery) ([]model.StatsBucket, error) {
+	var query string
+	if q.GroupBy == model.GroupByBrand {
+		// an order with several brands is counted once per brand, revenue comes from item totals
+		query = `
+        SELECT
+            COALESCE(i.brand, '') AS key,
+            COUNT(DISTINCT i.order_uid),
+            COALESCE(SUM(i.total_price), 0)::BIGINT
+        FROM items i
+        JOIN orders o ON o.order_uid = i.order_uid
+        WHERE o.date_created >= $1 AND o.date_created < $2
+        GROUP BY key
+        ORDER BY key
+    `
+	} else {
+		key, ok := statsKeys[q.GroupBy]
+		if !ok {
+			return nil, errors.Errorf("unsupported grouping %q", q.GroupBy)
+		}
+		query = fmt.Sprintf(`
+        SELECT
+            %s AS key,
+            COUNT(*),
+            COALESCE(SUM(p.amount), 0)::BIGINT
+        FROM orders o
+        JOIN payment p ON p.order_uid = o.order_uid
+        WHERE o.date_created >= $1 AND o.date_created < $2
+        GROUP BY key
+        ORDER BY key
+    `, key)
+	}
+
+	rows, err := r.conn.Query(ctx, query, q.From, q.To)
+	if err != nil {
+		return nil, classify(err, "query order stats")
+	}
+	defer rows.Close()
+
+	buckets := []model.StatsBucket{}
+	for rows.Next() {
+		var b model.StatsBucket
+		if err := rows.Scan(&b.Key, &b.Orders, &b.Revenue); err != nil {
+			return nil, classify(err, "scan stats bucket")
+		}
+		buckets = append(buckets, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, classify(err, "stats rows iteration")
+	}
+
+	return buckets, nil
+}
+
+func (r *Repo) BasketStats(ctx context.Context, tr model.TimeRange) (*model.BasketStats, error) {
+	stats := &model.BasketStats{TimeRange: tr, ItemCounts: []model.ItemCountBucket{}}
+
+	const basketQuery = `
+        SELECT COUNT(*), COALESCE(AVG(p.amount), 0)::FLOAT8
+        FROM orders o
+        JOIN payment p ON p.order_uid = o.order_uid
+        WHERE o.date_created >= $1 AND o.date_created < $2
+    `
+	err := r.conn.QueryRow(ctx, basketQuery, tr.From, tr.To).Scan(&stats.Orders, &stats.AvgBasket)
+	if err != nil {
+		return nil, classify(err, "query basket stats")
+	}
+
+	const distributionQuery = `
+        SELECT c.items, COUNT(*)
+        FROM (
+            SELECT i.order_uid, COUNT(*) AS items
+            FROM items i
+            JOIN orders o ON o.order_uid = i.order_uid
+            WHERE o.date_created >= $1 AND o.date_created < $2
+            GROUP BY i.order_uid
+        ) c
+        GROUP BY c.items
+        ORDER BY c.items
+    `
+	rows, err := r.conn.Query(ctx, distributionQuery, tr.From, tr.To)
+	if err != nil {
+		return nil, classify(err, "query item count distribution")
+	}
+	defer rows.Close()
+
+	var orders, items int64
+	for rows.Next() {
+		var b model.ItemCountBucket
+		if err := rows.Scan(&b.Items, &b.Orders); err != nil {
+			return nil, classify(err, "scan item count bucket")
+		}
+		orders += b.Orders
+		items += int64(b.Items) * b.Orders
+		stats.ItemCounts = append(stats.ItemCounts, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, classify(err, "item count rows iteration")
+	}
+
+	if orders > 0 {
+		stats.AvgItemCount = float64(items) / float64(orders)
+	}
+
+	return stats, nil
+}
+
+// topOrders maps a ranking metric to the ORDER BY column of the top-N queries.
+var topOrders = map[model.TopMetric]string{
+	model.TopByUnits:    "units",
+	model.TopByRevenue:  "revenue",
+	model.TopByDiscount: "avg_discount",
+}
+
+func (r *Repo) TopBrands(ctx context.Context, q model.TopQuery) ([]model.BrandTop, error) {
+	orderBy, ok := topOrders[q.By]
+	if !ok {
+		return nil, errors.Errorf("unsupported metric %q", q.By)
+	}
+
+	query := fmt.Sprintf(`
+        SELECT
+            COALESCE(i.brand, '') AS brand,
+            COUNT(*) AS units,
+            COALESCE(SUM(i.total_price), 0)::BIGINT AS revenue,
+            COALESCE(AVG(i.sale), 0)::FLOAT8 AS avg_discount
+        FROM items i
+        JOIN orders o ON o.order_uid = i.order_uid
+        WHERE o.date_created >= $1 AND o.date_created < $2
+        GROUP BY 1
+        ORDER BY %s DESC, brand
+        LIMIT $3
+    `, orderBy)
+
+	rows, err := r.conn.Query(ctx, query, q.From, q.To, q.Limit)
+	if err != nil {
+		return nil, classify(err, "query top brands")
+	}
+	defer rows.Close()
+
+	brands := []model.BrandTop{}
+	for rows.Next() {
+		var b model.BrandTop
+		if err := rows.Scan(&b.Brand, &b.Units, &b.Revenue, &b.AvgDiscount); err != nil {
+			return nil, classify(err, "scan top brand")
+		}
+		brands = append(brands, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, classify(err, "top brands rows iteration")
+	}
+
+	return brands, nil
+}
+
+func (r *Repo) TopProducts(ctx context.Context, q model.TopQuery) ([]model.ProductTop, error) {
+	orderBy, ok := topOrders[q.By]
+	if !ok {
+		return nil, errors.Errorf("unsupported metric %q", q.By)
+	}
+
+	query := fmt.Sprintf(`
+        SELECT
+            i.nm_id,
+            COALESCE(MAX(i.name), '') AS name,
+            COALESCE(MAX(i.brand), '') AS brand,
+            COUNT(*) AS units,
+            COALESCE(SUM(i.total_price), 0)::BIGINT AS revenue,
+            COALESCE(AVG(i.sale), 0)::FLOAT8 AS avg_discount
+        FROM items i
+        JOIN orders o ON o.order_uid = i.order_uid
+        WHERE o.date_created >= $1 AND o.date_created < $2 AND i.nm_id IS NOT NULL
+        GROUP BY i.nm_id
+        ORDER BY %s DESC, i.nm_id
+        LIMIT $3
+    `, orderBy)
+
+	rows, err := r.conn.Query(ctx, query, q.From, q.To, q.Limit)
+	if err != nil {
+		return nil, classify(err, "query top products")
+	}
+	defer rows.Close()
+
+	products := []model.ProductTop{}
+	for rows.Next() {
+		var p model.ProductTop
+		if err := rows.Scan(&p.NmID, &p.Name, &p.Brand, &p.Units, &p.Revenue, &p.AvgDiscount); err != nil {
+			return nil, classify(err, "scan top product")
+		}
+		products = append(products, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, classify(err, "top products rows iteration")
+	}
+
+	return products, nil
+}
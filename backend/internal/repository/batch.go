@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/tracing"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SaveBatch stores orders in a single transaction. Rows are loaded with COPY into
+// temporary staging tables and moved into the main tables with ON CONFLICT DO NOTHING,
+// so orders that already exist are skipped. An order.stored outbox event is written for
+// every new order. It returns the order_uids of the newly stored orders.
+func (r *Repo) SaveBatch(ctx context.Context, orders []*model.Order) (_ []string, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "Repo.SaveBatch", trace.WithAttributes(attribute.Int("orders.count", len(orders))))
+	defer func() { tracing.End(span, err) }()
+
+	orders = uniqueOrders(orders)
+	if len(orders) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	tx, err := r.beginShort(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, classify(err, "begin transaction")
+	}
+
+	defer tx.Rollback(ctx)
+
+	const stagingQuery = `
+        CREATE TEMP TABLE staging_orders (LIKE orders INCLUDING DEFAULTS) ON COMMIT DROP;
+        CREATE TEMP TABLE staging_delivery (LIKE delivery) ON COMMIT DROP;
+        CREATE TEMP TABLE staging_payment (LIKE payment) ON COMMIT DROP;
+        CREATE TEMP TABLE staging_items (
+            order_uid TEXT, chrt_id BIGINT, track_number TEXT, price BIGINT, rid TEXT, name TEXT,
+            sale INTEGER, size TEXT, total_price BIGINT, nm_id BIGINT, brand TEXT, status INTEGER,
+            date_created TIMESTAMPTZ
+        ) ON COMMIT DROP;
+        CREATE TEMP TABLE staging_new (order_uid TEXT PRIMARY KEY) ON COMMIT DROP;
+    `
+	if _, err = tx.Exec(ctx, stagingQuery); err != nil {
+		return nil, classify(err, "create staging tables")
+	}
+
+	if err = copyOrders(ctx, tx, orders); err != nil {
+		return nil, err
+	}
+
+	const ordersQuery = `
+        WITH inserted AS (
+            INSERT INTO orders (
+                order_uid, track_number, entry, locale, internal_signature,
+                customer_id, delivery_service, shardkey, sm_id, date_created,
+                oof_shard, created_at
+            )
+            SELECT
+                order_uid, track_number, entry, locale, internal_signature,
+                customer_id, delivery_service, shardkey, sm_id, date_created,
+                oof_shard, created_at
+            FROM staging_orders
+            ON CONFLICT (order_uid) DO NOTHING
+            RETURNING order_uid
+        )
+        INSERT INTO staging_new SELECT order_uid FROM inserted
+        RETURNING order_uid
+    `
+	rows, err := tx.Query(ctx, ordersQuery)
+	if err != nil {
+		return nil, classify(err, "insert orders")
+	}
+	stored, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, classify(err, "insert orders")
+	}
+
+	const childQueries = `
+        INSERT INTO delivery SELECT s.* FROM staging_delivery s JOIN staging_new n USING (order_uid);
+        INSERT INTO payment SELECT s.* FROM staging_payment s JOIN staging_new n USING (order_uid);
+        INSERT INTO items (
+            order_uid, chrt_id, track_number, price, rid, name, sale,
+            size, total_price, nm_id, brand, status
+        )
+        SELECT
+            s.order_uid, s.chrt_id, s.track_number, s.price, s.rid, s.name, s.sale,
+            s.size, s.total_price, s.nm_id, s.brand, s.status
+        FROM staging_items s JOIN staging_new n USING (order_uid);
+        INSERT INTO item_status_history (order_uid, rid, status, changed_at)
+        SELECT s.order_uid, s.rid, s.status, s.date_created
+        FROM staging_items s JOIN staging_new n USING (order_uid);
+    `
+	if _, err = tx.Exec(ctx, childQueries); err != nil {
+		return nil, classify(err, "insert order details")
+	}
+
+	byUID := make(map[string]*model.Order, len(orders))
+	for _, o := range orders {
+		byUID[o.OrderUID] = o
+	}
+	now := time.Now()
+	events := make([]model.OrderEvent, len(stored))
+	for i, uid := range stored {
+		events[i] = model.NewOrderStoredEvent(byUID[uid], now)
+	}
+	if err = insertOutboxEvents(ctx, tx, events...); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, classify(err, "commit tx")
+	}
+
+	return stored, nil
+}
+
+func copyOrders(ctx context.Context, tx pgx.Tx, orders []*model.Order) error {
+	_, err := tx.CopyFrom(ctx, pgx.Identifier{"staging_orders"},
+		[]string{
+			"order_uid", "track_number", "entry", "locale", "internal_signature",
+			"customer_id", "delivery_service", "shardkey", "sm_id", "date_created",
+			"oof_shard", "created_at",
+		},
+		pgx.CopyFromSlice(len(orders), func(i int) ([]any, error) {
+			o := orders[i]
+			return []any{
+				o.OrderUID, o.TrackNumber, o.Entry, o.Locale, o.InternalSignature,
+				o.CustomerID, o.DeliveryService, o.ShardKey, o.SmID, o.DateCreated,
+				o.OofShard, o.CreatedAt,
+			}, nil
+		}),
+	)
+	if err != nil {
+		return classify(err, "copy orders")
+	}
+
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"staging_delivery"},
+		[]string{"order_uid", "name", "phone", "zip", "city", "address", "region", "email"},
+		pgx.CopyFromSlice(len(orders), func(i int) ([]any, error) {
+			o := orders[i]
+			d := o.Delivery
+			return []any{o.OrderUID, d.Name, d.Phone, d.Zip, d.City, d.Address, d.Region, d.Email}, nil
+		}),
+	)
+	if err != nil {
+		return classify(err, "copy delivery")
+	}
+
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"staging_payment"},
+		[]string{
+			"order_uid", "transaction", "request_id", "currency", "provider", "amount",
+			"payment_dt", "bank", "delivery_cost", "goods_total", "custom_fee",
+		},
+		pgx.CopyFromSlice(len(orders), func(i int) ([]any, error) {
+			o := orders[i]
+			p := o.Payment
+			return []any{
+				o.OrderUID, p.Transaction, p.RequestID, p.Currency, p.Provider, p.Amount,
+				p.PaymentDT, p.Bank, p.DeliveryCost, p.GoodsTotal, p.CustomFee,
+			}, nil
+		}),
+	)
+	if err != nil {
+		return classify(err, "copy payment")
+	}
+
+	var items [][]any
+	for _, o := range orders {
+		for _, item := range o.Items {
+			items = append(items, []any{
+				o.OrderUID, item.ChrtID, item.TrackNumber, item.Price, item.RID, item.Name,
+				item.Sale, item.Size, item.TotalPrice, item.NmID, item.Brand, item.Status,
+				o.DateCreated,
+			})
+		}
+	}
+
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"staging_items"},
+		[]string{
+			"order_uid", "chrt_id", "track_number", "price", "rid", "name",
+			"sale", "size", "total_price", "nm_id", "brand", "status", "date_created",
+		},
+		pgx.CopyFromRows(items),
+	)
+	if err != nil {
+		return classify(err, "copy items")
+	}
+
+	return nil
+}
+
+// uniqueOrders drops repeated order_uids, keeping the first occurrence.
+func uniqueOrders(orders []*model.Order) []*model.Order {
+	seen := make(map[string]struct{}, len(orders))
+	unique := make([]*model.Order, 0, len(orders))
+	for _, o := range orders {
+		if _, ok := seen[o.OrderUID]; ok {
+			continue
+		}
+		seen[o.OrderUID] = struct{}{}
+		unique = append(unique, o)
+	}
+	return unique
+}
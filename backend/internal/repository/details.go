@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+)
+
+func (r *Repo) GetOrderDetails(ctx context.Context, orderUID string) (*model.OrderDetails, error) {
+	return r.orderDetails(ctx, "o.order_uid = $1", orderUID)
+}
+
+func (r *Repo) GetOrderDetailsByTrack(ctx context.Context, track string) (*model.OrderDetails, error) {
+	return r.orderDetails(ctx, "o.track_number = $1", track)
+}
+
+// orderDetails loads every stored field of the single order matching cond. Track
+// numbers may repeat, the newest order wins, as in GetByTrackNumber.
+func (r *Repo) orderDetails(ctx context.Context, cond string, arg any) (*model.OrderDetails, error) {
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	tx, err := r.beginShort(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, classify(err, "begin transaction")
+	}
+
+	defer tx.Rollback(ctx)
+
+	orderQuery := `
+        SELECT
+            o.order_uid, o.track_number, COALESCE(o.entry, ''), COALESCE(o.locale, ''),
+            COALESCE(o.internal_signature, ''), COALESCE(o.customer_id, ''),
+            COALESCE(o.delivery_service, ''), COALESCE(o.shardkey, ''), COALESCE(o.sm_id, 0),
+            o.date_created, COALESCE(o.oof_shard, ''), o.created_at,
+            COALESCE(d.name, ''), COALESCE(d.phone, ''), COALESCE(d.zip, ''), COALESCE(d.city, ''),
+            COALESCE(d.address, ''), COALESCE(d.region, ''), COALESCE(d.email, ''),
+            COALESCE(p.transaction, ''), COALESCE(p.request_id, ''), COALESCE(p.currency, ''),
+            COALESCE(p.provider, ''), COALESCE(p.amount, 0), COALESCE(p.payment_dt, 0),
+            COALESCE(p.bank, ''), COALESCE(p.delivery_cost, 0), COALESCE(p.goods_total, 0),
+            COALESCE(p.custom_fee, 0)
+        FROM orders o
+        LEFT JOIN delivery d ON d.order_uid = o.order_uid
+        LEFT JOIN payment p ON p.order_uid = o.order_uid
+        WHERE ` + cond + `
+        ORDER BY o.date_created DESC
+        LIMIT 1
+    `
+
+	var details model.OrderDetails
+	o := &details.Order
+	err = tx.QueryRow(ctx, orderQuery, arg).Scan(
+		&o.OrderUID, &o.TrackNumber, &o.Entry, &o.Locale,
+		&o.InternalSignature, &o.CustomerID,
+		&o.DeliveryService, &o.ShardKey, &o.SmID,
+		&o.DateCreated, &o.OofShard, &o.CreatedAt,
+		&o.Delivery.Name, &o.Delivery.Phone, &o.Delivery.Zip, &o.Delivery.City,
+		&o.Delivery.Address, &o.Delivery.Region, &o.Delivery.Email,
+		&o.Payment.Transaction, &o.Payment.RequestID, &o.Payment.Currency,
+		&o.Payment.Provider, &o.Payment.Amount, &o.Payment.PaymentDT,
+		&o.Payment.Bank, &o.Payment.DeliveryCost, &o.Payment.GoodsTotal,
+		&o.Payment.CustomFee,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperr.New(apperr.ErrNotFound, "order not found")
+	}
+	if err != nil {
+		return nil, classify(err, "get order details")
+	}
+
+	const itemsQuery = `
+        SELECT
+            COALESCE(chrt_id, 0), COALESCE(track_number, ''), COALESCE(price, 0), rid,
+            COALESCE(name, ''), COALESCE(sale, 0), COALESCE(size, ''), COALESCE(total_price, 0),
+            COALESCE(nm_id, 0), COALESCE(brand, ''), COALESCE(status, 0)
+        FROM items
+        WHERE order_uid = $1
+        ORDER BY id
+    `
+	rows, err := tx.Query(ctx, itemsQuery, o.OrderUID)
+	if err != nil {
+		return nil, classify(err, "query order items")
+	}
+	defer rows.Close()
+
+	o.Items = []model.Item{}
+	for rows.Next() {
+		var item model.Item
+		err := rows.Scan(
+			&item.ChrtID, &item.TrackNumber, &item.Price, &item.RID,
+			&item.Name, &item.Sale, &item.Size, &item.TotalPrice,
+			&item.NmID, &item.Brand, &item.Status,
+		)
+		if err != nil {
+			return nil, classify(err, "scan item row")
+		}
+		o.Items = append(o.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, classify(err, "items rows iteration")
+	}
+
+	history, err := r.getHistoryByOrderUIDs(ctx, tx, []string{o.OrderUID})
+	if err != nil {
+		return nil, err
+	}
+	details.History = history[o.OrderUID]
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, classify(err, "commit tx")
+	}
+
+	return &details, nil
+}
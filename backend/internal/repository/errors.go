@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pkg/errors"
+)
+
+// classify wraps err with msg and marks it with the apperr kind of the failure:
+//   - data exceptions (class 22) and integrity constraint violations (class 23) are
+//     apperr.ErrInvalidData, since retrying them can never succeed;
+//   - cancelled statements (57014, e.g. statement_timeout) and network timeouts are
+//     apperr.ErrTimeout;
+//   - failed connections, connection exceptions (class 08), insufficient resources
+//     (class 53) and server shutdown (57P01..57P03) are apperr.ErrUnavailable.
+func classify(err error, msg string) error {
+	if kind := kindOf(err); kind != nil {
+		err = fmt.Errorf("%w: %w", kind, err)
+	}
+	return errors.Wrap(err, msg)
+}
+
+func kindOf(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case strings.HasPrefix(pgErr.Code, "22"), strings.HasPrefix(pgErr.Code, "23"):
+			return apperr.ErrInvalidData
+		case pgErr.Code == "57014":
+			return apperr.ErrTimeout
+		case strings.HasPrefix(pgErr.Code, "08"), strings.HasPrefix(pgErr.Code, "53"), strings.HasPrefix(pgErr.Code, "57P0"):
+			return apperr.ErrUnavailable
+		}
+		return nil
+	}
+
+	var connErr *pgconn.ConnectError
+	switch {
+	case errors.As(err, &connErr):
+		return apperr.ErrUnavailable
+	case pgconn.Timeout(err):
+		return apperr.ErrTimeout
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/jackc/pgx/v5"
+)
+
+const exportFetchSize = 1000
+
+// ExportOrders streams orders joined with their items through a server-side cursor,
+// fetching exportFetchSize rows at a time. Rows of one order are delivered consecutively.
+func (r *Repo) ExportOrders(ctx context.Context, filter model.OrderFilter, fn func(*model.ExportRow) error) error {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return classify(err, "begin transaction")
+	}
+
+	defer tx.Rollback(ctx)
+
+	where, args := filterClause(filter)
+	cursorQuery := `
+        DECLARE export_cursor NO SCROLL CURSOR FOR
+        SELECT
+            o.order_uid, o.track_number, COALESCE(o.entry, ''), COALESCE(o.locale, ''),
+            COALESCE(o.internal_signature, ''), COALESCE(o.customer_id, ''),
+            COALESCE(o.delivery_service, ''), COALESCE(o.shardkey, ''), COALESCE(o.sm_id, 0),
+            o.date_created, COALESCE(o.oof_shard, ''), o.created_at,
+            COALESCE(d.name, ''), COALESCE(d.phone, ''), COALESCE(d.zip, ''), COALESCE(d.city, ''),
+            COALESCE(d.address, ''), COALESCE(d.region, ''), COALESCE(d.email, ''),
+            COALESCE(p.transaction, ''), COALESCE(p.request_id, ''), COALESCE(p.currency, ''),
+            COALESCE(p.provider, ''), COALESCE(p.amount, 0), COALESCE(p.payment_dt, 0),
+            COALESCE(p.bank, ''), COALESCE(p.delivery_cost, 0), COALESCE(p.goods_total, 0),
+            COALESCE(p.custom_fee, 0),
+            i.chrt_id, i.track_number, i.price, i.rid, i.name, i.sale,
+            i.size, i.total_price, i.nm_id, i.brand, i.status
+        FROM orders o
+        LEFT JOIN delivery d ON d.order_uid = o.order_uid
+        LEFT JOIN payment p ON p.order_uid = o.order_uid
+        LEFT JOIN items i ON i.order_uid = o.order_uid
+        ` + where + `
+        ORDER BY o.date_created DESC, o.order_uid, i.id
+    `
+	if _, err = tx.Exec(ctx, cursorQuery, args...); err != nil {
+		return classify(err, "declare export cursor")
+	}
+
+	for {
+		fetched, err := r.fetchExportChunk(ctx, tx, fn)
+		if err != nil {
+			return err
+		}
+		if fetched < exportFetchSize {
+			break
+		}
+	}
+
+	if _, err = tx.Exec(ctx, "CLOSE export_cursor"); err != nil {
+		return classify(err, "close export cursor")
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return classify(err, "commit tx")
+	}
+
+	return nil
+}
+
+func (r *Repo) fetchExportChunk(ctx context.Context, tx pgx.Tx, fn func(*model.ExportRow) error) (int, error) {
+	rows, err := tx.Query(ctx, fmt.Sprintf("FETCH FORWARD %d FROM export_cursor", exportFetchSize))
+	if err != nil {
+		return 0, classify(err, "fetch export cursor")
+	}
+	defer rows.Close()
+
+	fetched := 0
+	for rows.Next() {
+		var row model.ExportRow
+		o := &row.Order
+
+		var (
+			chrtID, price, totalPrice, nmID *int64
+			sale, status                    *int
+			track, rid, name, size, brand   *string
+		)
+
+		err := rows.Scan(
+			&o.OrderUID, &o.TrackNumber, &o.Entry, &o.Locale,
+			&o.InternalSignature, &o.CustomerID,
+			&o.DeliveryService, &o.ShardKey, &o.SmID,
+			&o.DateCreated, &o.OofShard, &o.CreatedAt,
+			&o.Delivery.Name, &o.Delivery.Phone, &o.Delivery.Zip, &o.Delivery.City,
+			&o.Delivery.Address, &o.Delivery.Region, &o.Delivery.Email,
+			&o.Payment.Transaction, &o.Payment.RequestID, &o.Payment.Currency,
+			&o.Payment.Provider, &o.Payment.Amount, &o.Payment.PaymentDT,
+			&o.Payment.Bank, &o.Payment.DeliveryCost, &o.Payment.GoodsTotal,
+			&o.Payment.CustomFee,
+			&chrtID, &track, &price, &rid, &name, &sale,
+			&size, &totalPrice, &nmID, &brand, &status,
+		)
+		if err != nil {
+			return 0, classify(err, "scan export row")
+		}
+
+		if rid != nil {
+			row.Item = &model.Item{
+				ChrtID:      deref(chrtID),
+				TrackNumber: deref(track),
+				Price:       deref(price),
+				RID:         *rid,
+				Name:        deref(name),
+				Sale:        deref(sale),
+				Size:        deref(size),
+				TotalPrice:  deref(totalPrice),
+				NmID:        deref(nmID),
+				Brand:       deref(brand),
+				Status:      model.ItemStatus(deref(status)),
+			}
+		}
+
+		if err := fn(&row); err != nil {
+			return 0, err
+		}
+		fetched++
+	}
+
+	if err := rows.Err(); err != nil {
+		return 0, classify(err, "export rows iteration")
+	}
+
+	return fetched, nil
+}
+
+func deref[T any](v *T) T {
+	var zero T
+	if v == nil {
+		return zero
+	}
+	return *v
+}
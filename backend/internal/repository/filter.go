@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GkadyrG/L0/backend/internal/model"
+)
+
+// filterClause builds a WHERE clause over the orders table aliased as o.
+// Placeholders are numbered from 1, so the clause must come first in the argument list.
+func filterClause(f model.OrderFilter) (string, []any) {
+	var conds []string
+	var args []any
+
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if f.CustomerID != "" {
+		add("o.customer_id = $%d", f.CustomerID)
+	}
+	if f.DeliveryService != "" {
+		add("o.delivery_service = $%d", f.DeliveryService)
+	}
+	if !f.From.IsZero() {
+		add("o.date_created >= $%d", f.From)
+	}
+	if !f.To.IsZero() {
+		add("o.date_created < $%d", f.To)
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+)
+
+func (r *Repo) Ping(ctx context.Context) error {
+	if err := r.conn.Ping(ctx); err != nil {
+		return classify(err, "ping database")
+	}
+	return nil
+}
+
+// MigrationVersion reads the state golang-migrate keeps in schema_migrations.
+func (r *Repo) MigrationVersion(ctx context.Context) (version uint, dirty bool, err error) {
+	err = r.conn.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err != nil {
+		return 0, false, classify(err, "read migration version")
+	}
+	return version, dirty, nil
+}
@@ -2,14 +2,59 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/GkadyrG/L0/backend/internal/model"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@latest --name=OrderRepository
 type OrderRepository interface {
-	Save(ctx context.Context, order *model.Order) error
+	Save(ctx context.Context, order *model.Order) (bool, error)
+	SaveBatch(ctx context.Context, orders []*model.Order) ([]string, error)
 	GetByID(ctx context.Context, id string) (*model.OrderResponse, error)
-	GetAll(ctx context.Context) ([]*model.OrderPreview, error)
+	GetByTrackNumber(ctx context.Context, track string) (*model.OrderResponse, error)
+	ListByCustomer(ctx context.Context, customerID string, limit, offset int) ([]*model.OrderPreview, error)
+	GetAll(ctx context.Context, filter model.OrderFilter) ([]*model.OrderPreview, error)
 	GetAllFull(ctx context.Context, limit int) ([]*model.OrderResponse, error)
+	UpdateItemStatus(ctx context.Context, event *model.ItemStatusEvent) error
+	ExportOrders(ctx context.Context, filter model.OrderFilter, fn func(*model.ExportRow) error) error
+}
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=QueryRepository
+type QueryRepository interface {
+	ListOrderPage(ctx context.Context, filter model.OrderFilter, after *model.OrderCursor, limit int) ([]*model.OrderPreview, error)
+	ListCustomerOrderPages(ctx context.Context, customerIDs []string, after *model.OrderCursor, limit int) ([]*model.OrderPreview, error)
+	GetOrdersByUIDs(ctx context.Context, orderUIDs []string) ([]*model.OrderResponse, error)
+	GetItemsByOrderUIDs(ctx context.Context, orderUIDs []string) (map[string][]model.ItemResponse, error)
+	GetOrderDetails(ctx context.Context, orderUID string) (*model.OrderDetails, error)
+	GetOrderDetailsByTrack(ctx context.Context, track string) (*model.OrderDetails, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=AnalyticsRepository
+type AnalyticsRepository interface {
+	OrderStats(ctx context.Context, q model.StatsQuery) ([]model.StatsBucket, error)
+	BasketStats(ctx context.Context, tr model.TimeRange) (*model.BasketStats, error)
+	TopBrands(ctx context.Context, q model.TopQuery) ([]model.BrandTop, error)
+	TopProducts(ctx context.Context, q model.TopQuery) ([]model.ProductTop, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=OutboxRepository
+type OutboxRepository interface {
+	ClaimOutbox(ctx context.Context, limit int, lease time.Duration) ([]model.OutboxMessage, error)
+	MarkOutboxPublished(ctx context.Context, ids []int64) error
+	FailOutbox(ctx context.Context, ids []int64, reason string) error
+	OutboxBacklog(ctx context.Context) (*model.OutboxBacklog, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=WebhookRepository
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, url, secret string, eventTypes []model.EventType) (*model.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]*model.Webhook, error)
+	GetWebhook(ctx context.Context, id int64) (*model.Webhook, error)
+	UpdateWebhook(ctx context.Context, id int64, in model.WebhookInput) (*model.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	ListDeliveries(ctx context.Context, webhookID int64, limit, offset int) ([]*model.WebhookDelivery, error)
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*model.PendingDelivery, error)
+	CompleteDelivery(ctx context.Context, deliveryID, webhookID int64, statusCode int) error
+	FailDelivery(ctx context.Context, f model.DeliveryFailure) (bool, error)
 }
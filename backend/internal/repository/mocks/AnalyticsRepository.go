@@ -0,0 +1,149 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/GkadyrG/L0/backend/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AnalyticsRepository is an autogenerated mock type for the AnalyticsRepository type
+type AnalyticsRepository struct {
+	mock.Mock
+}
+
+// BasketStats provides a mock function with given fields: ctx, tr
+func (_m *AnalyticsRepository) BasketStats(ctx context.Context, tr model.TimeRange) (*model.BasketStats, error) {
+	ret := _m.Called(ctx, tr)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BasketStats")
+	}
+
+	var r0 *model.BasketStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.TimeRange) (*model.BasketStats, error)); ok {
+		return rf(ctx, tr)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.TimeRange) *model.BasketStats); ok {
+		r0 = rf(ctx, tr)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.BasketStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.TimeRange) error); ok {
+		r1 = rf(ctx, tr)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderStats provides a mock function with given fields: ctx, q
+func (_m *AnalyticsRepository) OrderStats(ctx context.Context, q model.StatsQuery) ([]model.StatsBucket, error) {
+	ret := _m.Called(ctx, q)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OrderStats")
+	}
+
+	var r0 []model.StatsBucket
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.StatsQuery) ([]model.StatsBucket, error)); ok {
+		return rf(ctx, q)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.StatsQuery) []model.StatsBucket); ok {
+		r0 = rf(ctx, q)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.StatsBucket)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.StatsQuery) error); ok {
+		r1 = rf(ctx, q)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TopBrands provides a mock function with given fields: ctx, q
+func (_m *AnalyticsRepository) TopBrands(ctx context.Context, q model.TopQuery) ([]model.BrandTop, error) {
+	ret := _m.Called(ctx, q)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TopBrands")
+	}
+
+	var r0 []model.BrandTop
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.TopQuery) ([]model.BrandTop, error)); ok {
+		return rf(ctx, q)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.TopQuery) []model.BrandTop); ok {
+		r0 = rf(ctx, q)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.BrandTop)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.TopQuery) error); ok {
+		r1 = rf(ctx, q)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TopProducts provides a mock function with given fields: ctx, q
+func (_m *AnalyticsRepository) TopProducts(ctx context.Context, q model.TopQuery) ([]model.ProductTop, error) {
+	ret := _m.Called(ctx, q)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TopProducts")
+	}
+
+	var r0 []model.ProductTop
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.TopQuery) ([]model.ProductTop, error)); ok {
+		return rf(ctx, q)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.TopQuery) []model.ProductTop); ok {
+		r0 = rf(ctx, q)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ProductTop)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.TopQuery) error); ok {
+		r1 = rf(ctx, q)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAnalyticsRepository creates a new instance of AnalyticsRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAnalyticsRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AnalyticsRepository {
+	mock := &AnalyticsRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -14,9 +14,27 @@ type OrderRepository struct {
 	mock.Mock
 }
 
-// GetAll provides a mock function with given fields: ctx
-func (_m *OrderRepository) GetAll(ctx context.Context) ([]*model.OrderPreview, error) {
-	ret := _m.Called(ctx)
+// ExportOrders provides a mock function with given fields: ctx, filter, fn
+func (_m *OrderRepository) ExportOrders(ctx context.Context, filter model.OrderFilter, fn func(*model.ExportRow) error) error {
+	ret := _m.Called(ctx, filter, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportOrders")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.OrderFilter, func(*model.ExportRow) error) error); ok {
+		r0 = rf(ctx, filter, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAll provides a mock function with given fields: ctx, filter
+func (_m *OrderRepository) GetAll(ctx context.Context, filter model.OrderFilter) ([]*model.OrderPreview, error) {
+	ret := _m.Called(ctx, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAll")
@@ -24,19 +42,19 @@ func (_m *OrderRepository) GetAll(ctx context.Context) ([]*model.OrderPreview, e
 
 	var r0 []*model.OrderPreview
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.OrderPreview, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, model.OrderFilter) ([]*model.OrderPreview, error)); ok {
+		return rf(ctx, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []*model.OrderPreview); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, model.OrderFilter) []*model.OrderPreview); ok {
+		r0 = rf(ctx, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*model.OrderPreview)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, model.OrderFilter) error); ok {
+		r1 = rf(ctx, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -104,17 +122,135 @@ func (_m *OrderRepository) GetByID(ctx context.Context, id string) (*model.Order
 	return r0, r1
 }
 
+// GetByTrackNumber provides a mock function with given fields: ctx, track
+func (_m *OrderRepository) GetByTrackNumber(ctx context.Context, track string) (*model.OrderResponse, error) {
+	ret := _m.Called(ctx, track)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByTrackNumber")
+	}
+
+	var r0 *model.OrderResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.OrderResponse, error)); ok {
+		return rf(ctx, track)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.OrderResponse); ok {
+		r0 = rf(ctx, track)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OrderResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, track)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListByCustomer provides a mock function with given fields: ctx, customerID, limit, offset
+func (_m *OrderRepository) ListByCustomer(ctx context.Context, customerID string, limit int, offset int) ([]*model.OrderPreview, error) {
+	ret := _m.Called(ctx, customerID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByCustomer")
+	}
+
+	var r0 []*model.OrderPreview
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) ([]*model.OrderPreview, error)); ok {
+		return rf(ctx, customerID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) []*model.OrderPreview); ok {
+		r0 = rf(ctx, customerID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.OrderPreview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) error); ok {
+		r1 = rf(ctx, customerID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Save provides a mock function with given fields: ctx, order
-func (_m *OrderRepository) Save(ctx context.Context, order *model.Order) error {
+func (_m *OrderRepository) Save(ctx context.Context, order *model.Order) (bool, error) {
 	ret := _m.Called(ctx, order)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Save")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *model.Order) error); ok {
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Order) (bool, error)); ok {
+		return rf(ctx, order)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Order) bool); ok {
 		r0 = rf(ctx, order)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Order) error); ok {
+		r1 = rf(ctx, order)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveBatch provides a mock function with given fields: ctx, orders
+func (_m *OrderRepository) SaveBatch(ctx context.Context, orders []*model.Order) ([]string, error) {
+	ret := _m.Called(ctx, orders)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveBatch")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*model.Order) ([]string, error)); ok {
+		return rf(ctx, orders)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*model.Order) []string); ok {
+		r0 = rf(ctx, orders)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []*model.Order) error); ok {
+		r1 = rf(ctx, orders)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateItemStatus provides a mock function with given fields: ctx, event
+func (_m *OrderRepository) UpdateItemStatus(ctx context.Context, event *model.ItemStatusEvent) error {
+	ret := _m.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateItemStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.ItemStatusEvent) error); ok {
+		r0 = rf(ctx, event)
 	} else {
 		r0 = ret.Error(0)
 	}
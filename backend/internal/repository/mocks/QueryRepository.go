@@ -0,0 +1,209 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/GkadyrG/L0/backend/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// QueryRepository is an autogenerated mock type for the QueryRepository type
+type QueryRepository struct {
+	mock.Mock
+}
+
+// GetItemsByOrderUIDs provides a mock function with given fields: ctx, orderUIDs
+func (_m *QueryRepository) GetItemsByOrderUIDs(ctx context.Context, orderUIDs []string) (map[string][]model.ItemResponse, error) {
+	ret := _m.Called(ctx, orderUIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetItemsByOrderUIDs")
+	}
+
+	var r0 map[string][]model.ItemResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) (map[string][]model.ItemResponse, error)); ok {
+		return rf(ctx, orderUIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) map[string][]model.ItemResponse); ok {
+		r0 = rf(ctx, orderUIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string][]model.ItemResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, orderUIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrderDetails provides a mock function with given fields: ctx, orderUID
+func (_m *QueryRepository) GetOrderDetails(ctx context.Context, orderUID string) (*model.OrderDetails, error) {
+	ret := _m.Called(ctx, orderUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrderDetails")
+	}
+
+	var r0 *model.OrderDetails
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.OrderDetails, error)); ok {
+		return rf(ctx, orderUID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.OrderDetails); ok {
+		r0 = rf(ctx, orderUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OrderDetails)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, orderUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrderDetailsByTrack provides a mock function with given fields: ctx, track
+func (_m *QueryRepository) GetOrderDetailsByTrack(ctx context.Context, track string) (*model.OrderDetails, error) {
+	ret := _m.Called(ctx, track)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrderDetailsByTrack")
+	}
+
+	var r0 *model.OrderDetails
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.OrderDetails, error)); ok {
+		return rf(ctx, track)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.OrderDetails); ok {
+		r0 = rf(ctx, track)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OrderDetails)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, track)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrdersByUIDs provides a mock function with given fields: ctx, orderUIDs
+func (_m *QueryRepository) GetOrdersByUIDs(ctx context.Context, orderUIDs []string) ([]*model.OrderResponse, error) {
+	ret := _m.Called(ctx, orderUIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrdersByUIDs")
+	}
+
+	var r0 []*model.OrderResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]*model.OrderResponse, error)); ok {
+		return rf(ctx, orderUIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []*model.OrderResponse); ok {
+		r0 = rf(ctx, orderUIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.OrderResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, orderUIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListCustomerOrderPages provides a mock function with given fields: ctx, customerIDs, after, limit
+func (_m *QueryRepository) ListCustomerOrderPages(ctx context.Context, customerIDs []string, after *model.OrderCursor, limit int) ([]*model.OrderPreview, error) {
+	ret := _m.Called(ctx, customerIDs, after, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListCustomerOrderPages")
+	}
+
+	var r0 []*model.OrderPreview
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, *model.OrderCursor, int) ([]*model.OrderPreview, error)); ok {
+		return rf(ctx, customerIDs, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, *model.OrderCursor, int) []*model.OrderPreview); ok {
+		r0 = rf(ctx, customerIDs, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.OrderPreview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, *model.OrderCursor, int) error); ok {
+		r1 = rf(ctx, customerIDs, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListOrderPage provides a mock function with given fields: ctx, filter, after, limit
+func (_m *QueryRepository) ListOrderPage(ctx context.Context, filter model.OrderFilter, after *model.OrderCursor, limit int) ([]*model.OrderPreview, error) {
+	ret := _m.Called(ctx, filter, after, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOrderPage")
+	}
+
+	var r0 []*model.OrderPreview
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.OrderFilter, *model.OrderCursor, int) ([]*model.OrderPreview, error)); ok {
+		return rf(ctx, filter, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.OrderFilter, *model.OrderCursor, int) []*model.OrderPreview); ok {
+		r0 = rf(ctx, filter, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.OrderPreview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.OrderFilter, *model.OrderCursor, int) error); ok {
+		r1 = rf(ctx, filter, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewQueryRepository creates a new instance of QueryRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewQueryRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *QueryRepository {
+	mock := &QueryRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
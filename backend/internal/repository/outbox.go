@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/jackc/pgx/v5"
+)
+
+// insertOutboxEvents writes events to the outbox and queues a delivery for every
+// enabled webhook subscribed to them. It runs in the caller's transaction, so events
+// and deliveries exist if and only if the change they describe is committed.
+func insertOutboxEvents(ctx context.Context, tx pgx.Tx, events ...model.OrderEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(events))
+	types := make([]string, len(events))
+	aggregates := make([]string, len(events))
+	payloads := make([]string, len(events))
+	for i, e := range events {
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return classify(err, "marshal order event")
+		}
+		ids[i] = e.ID
+		types[i] = string(e.Type)
+		aggregates[i] = eventAggregate(e)
+		payloads[i] = string(payload)
+	}
+
+	const outboxQuery = `
+        INSERT INTO outbox (event_id, event_type, aggregate_id, payload)
+        SELECT * FROM unnest($1::text[], $2::text[], $3::text[], $4::jsonb[])
+        ON CONFLICT (event_id) DO NOTHING
+    `
+	if _, err := tx.Exec(ctx, outboxQuery, ids, types, aggregates, payloads); err != nil {
+		return classify(err, "insert outbox events")
+	}
+
+	const fanOutQuery = `
+        INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+        SELECT w.id, o.event_id, o.event_type, o.payload
+        FROM outbox o
+        JOIN webhooks w ON w.enabled
+            AND (cardinality(w.event_types) = 0 OR o.event_type = ANY(w.event_types))
+        WHERE o.event_id = ANY($1)
+        ON CONFLICT (webhook_id, event_id) DO NOTHING
+    `
+	if _, err := tx.Exec(ctx, fanOutQuery, ids); err != nil {
+		return classify(err, "queue webhook deliveries")
+	}
+
+	return nil
+}
+
+func eventAggregate(e model.OrderEvent) string {
+	switch {
+	case e.Order != nil:
+		return e.Order.OrderUID
+	case e.Item != nil:
+		return e.Item.OrderUID
+	}
+	return ""
+}
+
+// ClaimOutbox leases up to limit unpublished events in id order. Leased events are
+// skipped by other relays until the lease expires, so a relay that dies mid-publish
+// only delays its events. No lock is held while the events are being published.
+func (r *Repo) ClaimOutbox(ctx context.Context, limit int, lease time.Duration) ([]model.OutboxMessage, error) {
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	const query = `
+        WITH due AS (
+            SELECT id
+            FROM outbox
+            WHERE published_at IS NULL AND (locked_until IS NULL OR locked_until <= now())
+            ORDER BY id
+            LIMIT $1
+            FOR UPDATE SKIP LOCKED
+        )
+        UPDATE outbox o
+        SET locked_until = now() + $2::interval
+        FROM due
+        WHERE o.id = due.id
+        RETURNING o.id, o.event_id, o.event_type, o.aggregate_id, o.payload, o.attempts
+    `
+	rows, err := r.conn.Query(ctx, query, limit, lease)
+	if err != nil {
+		return nil, classify(err, "claim outbox")
+	}
+	defer rows.Close()
+
+	var msgs []model.OutboxMessage
+	for rows.Next() {
+		var m model.OutboxMessage
+		if err := rows.Scan(&m.ID, &m.EventID, &m.EventType, &m.AggregateID, &m.Payload, &m.Attempts); err != nil {
+			return nil, classify(err, "scan outbox")
+		}
+		msgs = append(msgs, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, classify(err, "rows outbox")
+	}
+
+	slices.SortFunc(msgs, func(a, b model.OutboxMessage) int { return cmp.Compare(a.ID, b.ID) })
+	return msgs, nil
+}
+
+// MarkOutboxPublished records that the events were accepted by the broker.
+func (r *Repo) MarkOutboxPublished(ctx context.Context, ids []int64) error {
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	const query = `
+        UPDATE outbox SET published_at = now(), attempts = attempts + 1, last_error = NULL, locked_until = NULL
+        WHERE id = ANY($1)
+    `
+	if _, err := r.conn.Exec(ctx, query, ids); err != nil {
+		return classify(err, "mark outbox published")
+	}
+	return nil
+}
+
+// FailOutbox records a failed attempt and releases the lease, so the events are
+// picked up again on the next poll.
+func (r *Repo) FailOutbox(ctx context.Context, ids []int64, reason string) error {
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	const query = `
+        UPDATE outbox SET attempts = attempts + 1, last_error = $2, locked_until = NULL
+        WHERE id = ANY($1)
+    `
+	if _, err := r.conn.Exec(ctx, query, ids, reason); err != nil {
+		return classify(err, "record outbox failure")
+	}
+	return nil
+}
+
+func (r *Repo) OutboxBacklog(ctx context.Context) (*model.OutboxBacklog, error) {
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	const query = `
+        SELECT
+            COUNT(*),
+            COUNT(*) FILTER (WHERE attempts > 0),
+            MIN(created_at)
+        FROM outbox
+        WHERE published_at IS NULL
+    `
+
+	var b model.OutboxBacklog
+	if err := r.conn.QueryRow(ctx, query).Scan(&b.Pending, &b.Failing, &b.OldestPending); err != nil {
+		return nil, classify(err, "outbox backlog")
+	}
+	return &b, nil
+}
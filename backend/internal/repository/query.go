@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/jackc/pgx/v5"
+)
+
+// ListOrderPage returns up to limit previews matching the filter that come after the
+// cursor, newest first.
+func (r *Repo) ListOrderPage(ctx context.Context, filter model.OrderFilter, after *model.OrderCursor, limit int) ([]*model.OrderPreview, error) {
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	where, args := filterClause(filter)
+	if after != nil {
+		args = append(args, after.DateCreated, after.OrderUID)
+		cond := fmt.Sprintf("(o.date_created, o.order_uid) < ($%d, $%d)", len(args)-1, len(args))
+		if where == "" {
+			where = "WHERE " + cond
+		} else {
+			where += " AND " + cond
+		}
+	}
+	args = append(args, limit)
+
+	pageQuery := `
+	SELECT o.order_uid, o.track_number, o.customer_id, o.date_created
+	FROM orders o ` + where + `
+	ORDER BY o.date_created DESC, o.order_uid DESC
+	LIMIT $` + fmt.Sprint(len(args))
+
+	rows, err := r.conn.Query(ctx, pageQuery, args...)
+	if err != nil {
+		return nil, classify(err, "list order page")
+	}
+	defer rows.Close()
+
+	previews := []*model.OrderPreview{}
+	for rows.Next() {
+		var p model.OrderPreview
+		if err := rows.Scan(&p.OrderUID, &p.TrackNumber, &p.CustomerID, &p.DateCreated); err != nil {
+			return nil, classify(err, "scan preview")
+		}
+		previews = append(previews, &p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, classify(err, "rows iteration")
+	}
+
+	return previews, nil
+}
+
+// ListCustomerOrderPages returns up to limit previews after the cursor for every one
+// of the customers in a single query, grouped by customer and newest first.
+func (r *Repo) ListCustomerOrderPages(ctx context.Context, customerIDs []string, after *model.OrderCursor, limit int) ([]*model.OrderPreview, error) {
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	args := []any{customerIDs}
+	cond := ""
+	if after != nil {
+		args = append(args, after.DateCreated, after.OrderUID)
+		cond = "AND (o.date_created, o.order_uid) < ($2, $3)"
+	}
+	args = append(args, limit)
+
+	pageQuery := `
+	SELECT p.order_uid, p.track_number, p.customer_id, p.date_created
+	FROM unnest($1::text[]) WITH ORDINALITY AS c(id, n)
+	CROSS JOIN LATERAL (
+		SELECT o.order_uid, o.track_number, o.customer_id, o.date_created
+		FROM orders o
+		WHERE o.customer_id = c.id ` + cond + `
+		ORDER BY o.date_created DESC, o.order_uid DESC
+		LIMIT $` + fmt.Sprint(len(args)) + `
+	) p
+	ORDER BY c.n, p.date_created DESC, p.order_uid DESC`
+
+	rows, err := r.conn.Query(ctx, pageQuery, args...)
+	if err != nil {
+		return nil, classify(err, "list customer order pages")
+	}
+	defer rows.Close()
+
+	previews := []*model.OrderPreview{}
+	for rows.Next() {
+		var p model.OrderPreview
+		if err := rows.Scan(&p.OrderUID, &p.TrackNumber, &p.CustomerID, &p.DateCreated); err != nil {
+			return nil, classify(err, "scan preview")
+		}
+		previews = append(previews, &p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, classify(err, "rows iteration")
+	}
+
+	return previews, nil
+}
+
+// GetOrdersByUIDs loads orders with delivery and payment but without items; unknown
+// uids are left out.
+func (r *Repo) GetOrdersByUIDs(ctx context.Context, orderUIDs []string) ([]*model.OrderResponse, error) {
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	const ordersQuery = `
+        SELECT
+            o.order_uid,
+            o.track_number,
+            o.customer_id,
+            o.delivery_service,
+            o.date_created,
+            d.name,
+            d.phone,
+            d.city,
+            d.address,
+            d.email,
+            p.transaction,
+            p.currency,
+            p.amount,
+            p.payment_dt
+        FROM orders o
+        JOIN delivery d ON d.order_uid = o.order_uid
+        JOIN payment p ON p.order_uid = o.order_uid
+        WHERE o.order_uid = ANY($1)
+    `
+
+	rows, err := r.conn.Query(ctx, ordersQuery, orderUIDs)
+	if err != nil {
+		return nil, classify(err, "query orders by uids")
+	}
+	defer rows.Close()
+
+	orders := []*model.OrderResponse{}
+	for rows.Next() {
+		var o model.OrderResponse
+		err := rows.Scan(
+			&o.OrderUID,
+			&o.TrackNumber,
+			&o.CustomerID,
+			&o.DeliveryService,
+			&o.DateCreated,
+			&o.Delivery.Name,
+			&o.Delivery.Phone,
+			&o.Delivery.City,
+			&o.Delivery.Address,
+			&o.Delivery.Email,
+			&o.Payment.Transaction,
+			&o.Payment.Currency,
+			&o.Payment.Amount,
+			&o.Payment.PaymentDT,
+		)
+		if err != nil {
+			return nil, classify(err, "scan order row")
+		}
+		orders = append(orders, &o)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, classify(err, "orders rows iteration")
+	}
+
+	return orders, nil
+}
+
+// GetItemsByOrderUIDs loads the items of several orders, with their status history,
+// in one round of ANY($1) queries.
+func (r *Repo) GetItemsByOrderUIDs(ctx context.Context, orderUIDs []string) (map[string][]model.ItemResponse, error) {
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	tx, err := r.beginShort(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, classify(err, "begin transaction")
+	}
+
+	defer tx.Rollback(ctx)
+
+	itemsMap, err := r.getItemsByOrderUIDs(ctx, tx, orderUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, classify(err, "commit tx")
+	}
+
+	return itemsMap, nil
+}
@@ -2,26 +2,67 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/GkadyrG/L0/backend/internal/apperr"
 	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/tracing"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Repo struct {
-	conn *pgxpool.Pool
+	conn         *pgxpool.Pool
+	queryTimeout time.Duration
 }
 
-func New(conn *pgxpool.Pool) *Repo {
-	return &Repo{conn: conn}
+// New creates the repository. queryTimeout bounds the calls serving requests and
+// events; zero leaves them bound by the caller's context only.
+func New(conn *pgxpool.Pool, queryTimeout time.Duration) *Repo {
+	return &Repo{conn: conn, queryTimeout: queryTimeout}
 }
 
-func (r *Repo) Save(ctx context.Context, order *model.Order) error {
-	tx, err := r.conn.Begin(ctx)
+// short puts the query timeout on ctx. Exports, cache warm-up and analytics do not
+// use it: they read many rows and may legitimately run long. Imports are bound per
+// batch through SaveBatch.
+func (r *Repo) short(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.queryTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, r.queryTimeout)
+}
+
+// beginShort starts the transaction of a short call. Postgres also gets the query
+// timeout as statement_timeout, so a statement the driver stopped waiting for does
+// not keep running on the server.
+func (r *Repo) beginShort(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	tx, err := r.conn.BeginTx(ctx, opts)
+	if err != nil || r.queryTimeout <= 0 {
+		return tx, err
+	}
+	if _, err := tx.Exec(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", r.queryTimeout.Milliseconds())); err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, err
+	}
+	return tx, nil
+}
+
+// Save stores the order with its details and an order.stored outbox event. It
+// reports false without changing anything if the order is already stored.
+func (r *Repo) Save(ctx context.Context, order *model.Order) (_ bool, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "Repo.Save", trace.WithAttributes(attribute.String("order.uid", order.OrderUID)))
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	tx, err := r.beginShort(ctx, pgx.TxOptions{})
 	if err != nil {
-		return errors.Wrap(err, "begin transaction")
+		return false, classify(err, "begin transaction")
 	}
 
 	defer tx.Rollback(ctx)
@@ -32,9 +73,10 @@ func (r *Repo) Save(ctx context.Context, order *model.Order) error {
             customer_id, delivery_service, shardkey, sm_id, date_created,
             oof_shard, created_at
         ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)
+        ON CONFLICT (order_uid) DO NOTHING
     `
 
-	_, err = tx.Exec(ctx, ordersQuery,
+	tag, err := tx.Exec(ctx, ordersQuery,
 		order.OrderUID,
 		order.TrackNumber,
 		order.Entry,
@@ -49,7 +91,12 @@ func (r *Repo) Save(ctx context.Context, order *model.Order) error {
 		order.CreatedAt,
 	)
 	if err != nil {
-		return errors.Wrap(err, "insert order")
+		return false, classify(err, "insert order")
+	}
+
+	// the order is already stored, redelivered messages are a no-op
+	if tag.RowsAffected() == 0 {
+		return false, nil
 	}
 
 	const deliveryQuery = `
@@ -68,7 +115,7 @@ func (r *Repo) Save(ctx context.Context, order *model.Order) error {
 		order.Delivery.Email,
 	)
 	if err != nil {
-		return errors.Wrap(err, "insert delivery")
+		return false, classify(err, "insert delivery")
 	}
 
 	const paymentQuery = `
@@ -91,7 +138,7 @@ func (r *Repo) Save(ctx context.Context, order *model.Order) error {
 		order.Payment.CustomFee,
 	)
 	if err != nil {
-		return errors.Wrap(err, "insert payment")
+		return false, classify(err, "insert payment")
 	}
 
 	const itemsQuery = `
@@ -100,6 +147,10 @@ func (r *Repo) Save(ctx context.Context, order *model.Order) error {
                 size, total_price, nm_id, brand, status
             ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)
         `
+	const historyQuery = `
+            INSERT INTO item_status_history (order_uid, rid, status, changed_at)
+            VALUES ($1,$2,$3,$4)
+        `
 	for _, item := range order.Items {
 		_, err = tx.Exec(ctx, itemsQuery,
 			order.OrderUID,
@@ -116,47 +167,114 @@ func (r *Repo) Save(ctx context.Context, order *model.Order) error {
 			item.Status,
 		)
 		if err != nil {
-			return errors.Wrap(err, "insert item")
+			return false, classify(err, "insert item")
 		}
+
+		_, err = tx.Exec(ctx, historyQuery, order.OrderUID, item.RID, item.Status, order.DateCreated)
+		if err != nil {
+			return false, classify(err, "insert item status history")
+		}
+	}
+
+	if err = insertOutboxEvents(ctx, tx, model.NewOrderStoredEvent(order, time.Now())); err != nil {
+		return false, err
 	}
 
 	if err = tx.Commit(ctx); err != nil {
-		return errors.Wrap(err, "commit tx")
+		return false, classify(err, "commit tx")
 	}
 
-	return nil
+	return true, nil
 }
 
 func (r *Repo) GetByID(ctx context.Context, id string) (*model.OrderResponse, error) {
-	tx, err := r.conn.Begin(ctx)
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	tx, err := r.beginShort(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, classify(err, "begin transaction")
+	}
+
+	defer tx.Rollback(ctx)
+
+	o, err := r.getOrder(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, classify(err, "commit tx")
+	}
+
+	return o, nil
+}
+
+func (r *Repo) GetByTrackNumber(ctx context.Context, track string) (*model.OrderResponse, error) {
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	tx, err := r.beginShort(ctx, pgx.TxOptions{})
 	if err != nil {
-		return nil, errors.Wrap(err, "begin transaction")
+		return nil, classify(err, "begin transaction")
 	}
 
 	defer tx.Rollback(ctx)
 
+	const trackQuery = `
+        SELECT order_uid FROM orders
+        WHERE track_number = $1
+        ORDER BY date_created DESC
+        LIMIT 1
+    `
+
+	var id string
+	err = tx.QueryRow(ctx, trackQuery, track).Scan(&id)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperr.New(apperr.ErrNotFound, "order not found")
+	}
+
+	if err != nil {
+		return nil, classify(err, "get order by track number")
+	}
+
+	o, err := r.getOrder(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, classify(err, "commit tx")
+	}
+
+	return o, nil
+}
+
+func (r *Repo) getOrder(ctx context.Context, tx pgx.Tx, id string) (*model.OrderResponse, error) {
 	o := model.OrderResponse{}
 
 	const orderdQuery = `
         SELECT 
-            order_uid, track_number, customer_id, date_created
+            order_uid, track_number, customer_id, delivery_service, date_created
         FROM orders 
         WHERE order_uid = $1
     `
 
-	err = tx.QueryRow(ctx, orderdQuery, id).Scan(
+	err := tx.QueryRow(ctx, orderdQuery, id).Scan(
 		&o.OrderUID,
 		&o.TrackNumber,
 		&o.CustomerID,
+		&o.DeliveryService,
 		&o.DateCreated,
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, errors.Wrap(apperr.ErrNotFound, "not found")
+		return nil, apperr.New(apperr.ErrNotFound, "order not found")
 	}
 
 	if err != nil {
-		return nil, errors.Wrap(err, "get order")
+		return nil, classify(err, "get order")
 	}
 
 	const deliveryQuery = `
@@ -172,85 +290,98 @@ func (r *Repo) GetByID(ctx context.Context, id string) (*model.OrderResponse, er
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, errors.Wrap(apperr.ErrNotFound, "not found")
+		return nil, apperr.New(apperr.ErrNotFound, "order not found")
 	}
 
 	if err != nil {
-		return nil, errors.Wrap(err, "get delivery")
+		return nil, classify(err, "get delivery")
 	}
 
 	const paymentQuery = `
-        SELECT transaction, currency, amount
+        SELECT transaction, currency, amount, payment_dt
         FROM payment WHERE order_uid = $1
     `
 	err = tx.QueryRow(ctx, paymentQuery, id).Scan(
 		&o.Payment.Transaction,
 		&o.Payment.Currency,
 		&o.Payment.Amount,
+		&o.Payment.PaymentDT,
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, errors.Wrap(apperr.ErrNotFound, "not found")
+		return nil, apperr.New(apperr.ErrNotFound, "order not found")
 	}
 
 	if err != nil {
-		return nil, errors.Wrap(err, "get payment")
+		return nil, classify(err, "get payment")
 	}
 
-	const itemsQuery = `
-	SELECT price, name, brand, status
-	FROM items WHERE order_uid = $1
+	itemsMap, err := r.getItemsByOrderUIDs(ctx, tx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+
+	o.Items = itemsMap[id]
+	if len(o.Items) == 0 {
+		return nil, apperr.New(apperr.ErrNotFound, "order not found")
+	}
+
+	return &o, nil
+}
+
+func (r *Repo) ListByCustomer(ctx context.Context, customerID string, limit, offset int) ([]*model.OrderPreview, error) {
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	const customerQuery = `
+	SELECT order_uid, track_number, customer_id, date_created
+	FROM orders
+	WHERE customer_id = $1
+	ORDER BY date_created DESC, order_uid
+	LIMIT $2 OFFSET $3
 	`
-	rows, err := tx.Query(ctx, itemsQuery, id)
+	rows, err := r.conn.Query(ctx, customerQuery, customerID, limit, offset)
 	if err != nil {
-		return nil, errors.Wrap(err, "get items")
+		return nil, classify(err, "list orders by customer")
 	}
 	defer rows.Close()
 
+	previews := []*model.OrderPreview{}
 	for rows.Next() {
-		var item model.ItemResponse
-		if err := rows.Scan(
-			&item.Price,
-			&item.Name,
-			&item.Brand,
-			&item.Status,
-		); err != nil {
-			return nil, errors.Wrap(err, "scan item")
+		var p model.OrderPreview
+		if err := rows.Scan(&p.OrderUID, &p.TrackNumber, &p.CustomerID, &p.DateCreated); err != nil {
+			return nil, classify(err, "scan preview")
 		}
-		o.Items = append(o.Items, item)
+		previews = append(previews, &p)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, errors.Wrap(err, "rows iteration")
-	}
-
-	if len(o.Items) == 0 {
-		return nil, errors.Wrap(apperr.ErrNotFound, "orders not found")
+		return nil, classify(err, "rows iteration")
 	}
 
-	if err = tx.Commit(ctx); err != nil {
-		return nil, errors.Wrap(err, "commit tx")
-	}
-
-	return &o, nil
+	return previews, nil
 }
 
-func (r *Repo) GetAll(ctx context.Context) ([]*model.OrderPreview, error) {
-	tx, err := r.conn.Begin(ctx)
+func (r *Repo) GetAll(ctx context.Context, filter model.OrderFilter) ([]*model.OrderPreview, error) {
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	tx, err := r.beginShort(ctx, pgx.TxOptions{})
 	if err != nil {
-		return nil, errors.Wrap(err, "begin transaction")
+		return nil, classify(err, "begin transaction")
 	}
 
 	defer tx.Rollback(ctx)
 
-	const orderQuery = `
-	SELECT order_uid, track_number, customer_id, date_created
-	FROM orders ORDER BY date_created DESC
+	where, args := filterClause(filter)
+	orderQuery := `
+	SELECT o.order_uid, o.track_number, o.customer_id, o.date_created
+	FROM orders o ` + where + ` ORDER BY o.date_created DESC
 	`
-	rows, err := tx.Query(ctx, orderQuery)
+	rows, err := tx.Query(ctx, orderQuery, args...)
 
 	if err != nil {
-		return nil, errors.Wrap(err, "get all orders")
+		return nil, classify(err, "get all orders")
 	}
 	defer rows.Close()
 
@@ -258,21 +389,21 @@ func (r *Repo) GetAll(ctx context.Context) ([]*model.OrderPreview, error) {
 	for rows.Next() {
 		var p model.OrderPreview
 		if err := rows.Scan(&p.OrderUID, &p.TrackNumber, &p.CustomerID, &p.DateCreated); err != nil {
-			return nil, errors.Wrap(err, "scan preview")
+			return nil, classify(err, "scan preview")
 		}
 		previews = append(previews, &p)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, errors.Wrap(err, "rows iteration")
+		return nil, classify(err, "rows iteration")
 	}
 
 	if len(previews) == 0 {
-		return nil, errors.Wrap(apperr.ErrNotFound, "orders not found")
+		return nil, apperr.New(apperr.ErrNotFound, "orders preview not found")
 	}
 
 	if err = tx.Commit(ctx); err != nil {
-		return nil, errors.Wrap(err, "commit tx")
+		return nil, classify(err, "commit tx")
 	}
 
 	return previews, nil
@@ -281,7 +412,7 @@ func (r *Repo) GetAll(ctx context.Context) ([]*model.OrderPreview, error) {
 func (r *Repo) GetAllFull(ctx context.Context, limit int) ([]*model.OrderResponse, error) {
 	tx, err := r.conn.Begin(ctx)
 	if err != nil {
-		return nil, errors.Wrap(err, "begin transaction")
+		return nil, classify(err, "begin transaction")
 	}
 
 	defer tx.Rollback(ctx)
@@ -291,6 +422,7 @@ func (r *Repo) GetAllFull(ctx context.Context, limit int) ([]*model.OrderRespons
             o.order_uid, 
             o.track_number, 
             o.customer_id, 
+            o.delivery_service,
             o.date_created,
             d.name, 
             d.phone, 
@@ -299,7 +431,8 @@ func (r *Repo) GetAllFull(ctx context.Context, limit int) ([]*model.OrderRespons
             d.email,
             p.transaction, 
             p.currency, 
-            p.amount
+            p.amount,
+            p.payment_dt
         FROM orders o
         LEFT JOIN delivery d ON d.order_uid = o.order_uid
         LEFT JOIN payment p ON p.order_uid = o.order_uid
@@ -309,7 +442,7 @@ func (r *Repo) GetAllFull(ctx context.Context, limit int) ([]*model.OrderRespons
 
 	mainRows, err := tx.Query(ctx, mainQuery, limit)
 	if err != nil {
-		return nil, errors.Wrap(err, "query main orders data")
+		return nil, classify(err, "query main orders data")
 	}
 	defer mainRows.Close()
 
@@ -325,6 +458,7 @@ func (r *Repo) GetAllFull(ctx context.Context, limit int) ([]*model.OrderRespons
 			&o.OrderUID,
 			&o.TrackNumber,
 			&o.CustomerID,
+			&o.DeliveryService,
 			&o.DateCreated,
 			&d.Name,
 			&d.Phone,
@@ -334,9 +468,10 @@ func (r *Repo) GetAllFull(ctx context.Context, limit int) ([]*model.OrderRespons
 			&p.Transaction,
 			&p.Currency,
 			&p.Amount,
+			&p.PaymentDT,
 		)
 		if err != nil {
-			return nil, errors.Wrap(err, "scan main row")
+			return nil, classify(err, "scan main row")
 		}
 
 		o.Delivery = d
@@ -348,7 +483,7 @@ func (r *Repo) GetAllFull(ctx context.Context, limit int) ([]*model.OrderRespons
 	}
 
 	if err := mainRows.Err(); err != nil {
-		return nil, errors.Wrap(err, "main rows iteration")
+		return nil, classify(err, "main rows iteration")
 	}
 
 	if len(ordersMap) == 0 {
@@ -371,7 +506,7 @@ func (r *Repo) GetAllFull(ctx context.Context, limit int) ([]*model.OrderRespons
 	}
 
 	if err = tx.Commit(ctx); err != nil {
-		return nil, errors.Wrap(err, "commit tx")
+		return nil, classify(err, "commit tx")
 	}
 
 	return orders, nil
@@ -381,17 +516,19 @@ func (r *Repo) getItemsByOrderUIDs(ctx context.Context, tx pgx.Tx, orderUIDs []s
 	const itemsQuery = `
         SELECT 
             order_uid, 
+            rid,
             price, 
             name, 
             brand, 
             status
         FROM items 
         WHERE order_uid = ANY($1)
+        ORDER BY id
     `
 
 	rows, err := tx.Query(ctx, itemsQuery, orderUIDs)
 	if err != nil {
-		return nil, errors.Wrap(err, "query items")
+		return nil, classify(err, "query items")
 	}
 	defer rows.Close()
 
@@ -403,21 +540,135 @@ func (r *Repo) getItemsByOrderUIDs(ctx context.Context, tx pgx.Tx, orderUIDs []s
 
 		err := rows.Scan(
 			&orderUID,
+			&item.RID,
 			&item.Price,
 			&item.Name,
 			&item.Brand,
 			&item.Status,
 		)
 		if err != nil {
-			return nil, errors.Wrap(err, "scan item row")
+			return nil, classify(err, "scan item row")
 		}
 
+		item.StatusName = item.Status.String()
 		itemsMap[orderUID] = append(itemsMap[orderUID], item)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, errors.Wrap(err, "items rows iteration")
+		return nil, classify(err, "items rows iteration")
+	}
+
+	historyMap, err := r.getHistoryByOrderUIDs(ctx, tx, orderUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for uid, items := range itemsMap {
+		for i := range items {
+			items[i].History = historyMap[uid][items[i].RID]
+			if items[i].History == nil {
+				items[i].History = make([]model.StatusChange, 0)
+			}
+		}
 	}
 
 	return itemsMap, nil
 }
+
+func (r *Repo) getHistoryByOrderUIDs(ctx context.Context, tx pgx.Tx, orderUIDs []string) (map[string]map[string][]model.StatusChange, error) {
+	const historyQuery = `
+        SELECT order_uid, rid, status, changed_at
+        FROM item_status_history
+        WHERE order_uid = ANY($1)
+        ORDER BY changed_at, id
+    `
+
+	rows, err := tx.Query(ctx, historyQuery, orderUIDs)
+	if err != nil {
+		return nil, classify(err, "query item status history")
+	}
+	defer rows.Close()
+
+	historyMap := make(map[string]map[string][]model.StatusChange)
+
+	for rows.Next() {
+		var orderUID, rid string
+		var status model.ItemStatus
+		var changedAt time.Time
+
+		if err := rows.Scan(&orderUID, &rid, &status, &changedAt); err != nil {
+			return nil, classify(err, "scan item status history row")
+		}
+
+		if historyMap[orderUID] == nil {
+			historyMap[orderUID] = make(map[string][]model.StatusChange)
+		}
+		historyMap[orderUID][rid] = append(historyMap[orderUID][rid], model.NewStatusChange(status, changedAt))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, classify(err, "item status history rows iteration")
+	}
+
+	return historyMap, nil
+}
+
+func (r *Repo) UpdateItemStatus(ctx context.Context, event *model.ItemStatusEvent) error {
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	tx, err := r.beginShort(ctx, pgx.TxOptions{})
+	if err != nil {
+		return classify(err, "begin transaction")
+	}
+
+	defer tx.Rollback(ctx)
+
+	const currentQuery = `
+        SELECT status FROM items
+        WHERE order_uid = $1 AND rid = $2
+        FOR UPDATE
+    `
+
+	var current model.ItemStatus
+	err = tx.QueryRow(ctx, currentQuery, event.OrderUID, event.RID).Scan(&current)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperr.New(apperr.ErrNotFound, "item not found")
+	}
+
+	if err != nil {
+		return classify(err, "get item status")
+	}
+
+	if !current.CanTransitionTo(event.Status) {
+		return errors.Wrapf(apperr.ErrInvalidTransition, "%s -> %s", current, event.Status)
+	}
+
+	const updateQuery = `
+        UPDATE items SET status = $3
+        WHERE order_uid = $1 AND rid = $2
+    `
+	if _, err = tx.Exec(ctx, updateQuery, event.OrderUID, event.RID, event.Status); err != nil {
+		return classify(err, "update item status")
+	}
+
+	const historyQuery = `
+        INSERT INTO item_status_history (order_uid, rid, status, changed_at)
+        VALUES ($1,$2,$3,$4)
+    `
+	_, err = tx.Exec(ctx, historyQuery, event.OrderUID, event.RID, event.Status, event.ChangedAt)
+	if err != nil {
+		return classify(err, "insert item status history")
+	}
+
+	if err = insertOutboxEvents(ctx, tx, model.NewOrderUpdatedEvent(event, time.Now())); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return classify(err, "commit tx")
+	}
+
+	return nil
+}
@@ -0,0 +1,295 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+)
+
+const webhookColumns = `id, url, event_types, enabled, consecutive_failures, disabled_at, created_at`
+
+func scanWebhook(row pgx.Row) (*model.Webhook, error) {
+	var (
+		w     model.Webhook
+		types []string
+	)
+	if err := row.Scan(&w.ID, &w.URL, &types, &w.Enabled, &w.ConsecutiveFailures, &w.DisabledAt, &w.CreatedAt); err != nil {
+		return nil, err
+	}
+	w.EventTypes = make([]model.EventType, len(types))
+	for i, t := range types {
+		w.EventTypes[i] = model.EventType(t)
+	}
+	return &w, nil
+}
+
+func eventTypeStrings(types []model.EventType) []string {
+	s := make([]string, len(types))
+	for i, t := range types {
+		s[i] = string(t)
+	}
+	return s
+}
+
+func (r *Repo) CreateWebhook(ctx context.Context, url, secret string, eventTypes []model.EventType) (*model.Webhook, error) {
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	query := `
+        INSERT INTO webhooks (url, secret, event_types)
+        VALUES ($1,$2,$3)
+        RETURNING ` + webhookColumns
+
+	w, err := scanWebhook(r.conn.QueryRow(ctx, query, url, secret, eventTypeStrings(eventTypes)))
+	if err != nil {
+		return nil, classify(err, "insert webhook")
+	}
+	return w, nil
+}
+
+func (r *Repo) ListWebhooks(ctx context.Context) ([]*model.Webhook, error) {
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	rows, err := r.conn.Query(ctx, `SELECT `+webhookColumns+` FROM webhooks ORDER BY id`)
+	if err != nil {
+		return nil, classify(err, "select webhooks")
+	}
+	defer rows.Close()
+
+	webhooks := []*model.Webhook{}
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, classify(err, "scan webhook")
+		}
+		webhooks = append(webhooks, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, classify(err, "rows webhooks")
+	}
+	return webhooks, nil
+}
+
+func (r *Repo) GetWebhook(ctx context.Context, id int64) (*model.Webhook, error) {
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	w, err := scanWebhook(r.conn.QueryRow(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperr.New(apperr.ErrNotFound, "webhook not found")
+	}
+	if err != nil {
+		return nil, classify(err, "select webhook")
+	}
+	return w, nil
+}
+
+// UpdateWebhook applies the non-nil fields of in. Re-enabling a webhook resets its
+// failure counter.
+func (r *Repo) UpdateWebhook(ctx context.Context, id int64, in model.WebhookInput) (*model.Webhook, error) {
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	var types []string
+	if in.EventTypes != nil {
+		types = eventTypeStrings(in.EventTypes)
+	}
+
+	query := `
+        UPDATE webhooks SET
+            url = COALESCE($2, url),
+            secret = COALESCE($3, secret),
+            event_types = COALESCE($4, event_types),
+            enabled = COALESCE($5, enabled),
+            consecutive_failures = CASE WHEN $5 THEN 0 ELSE consecutive_failures END,
+            disabled_at = CASE WHEN $5 THEN NULL WHEN NOT $5 THEN COALESCE(disabled_at, now()) ELSE disabled_at END
+        WHERE id = $1
+        RETURNING ` + webhookColumns
+
+	w, err := scanWebhook(r.conn.QueryRow(ctx, query, id, in.URL, in.Secret, types, in.Enabled))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperr.New(apperr.ErrNotFound, "webhook not found")
+	}
+	if err != nil {
+		return nil, classify(err, "update webhook")
+	}
+	return w, nil
+}
+
+func (r *Repo) DeleteWebhook(ctx context.Context, id int64) error {
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	tag, err := r.conn.Exec(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return classify(err, "delete webhook")
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.New(apperr.ErrNotFound, "webhook not found")
+	}
+	return nil
+}
+
+// ListDeliveries returns the delivery log of a webhook, newest first.
+func (r *Repo) ListDeliveries(ctx context.Context, webhookID int64, limit, offset int) ([]*model.WebhookDelivery, error) {
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	const query = `
+        SELECT id, webhook_id, event_id, event_type, status, attempts,
+               last_status_code, last_error, next_attempt_at, created_at, delivered_at
+        FROM webhook_deliveries
+        WHERE webhook_id = $1
+        ORDER BY id DESC
+        LIMIT $2 OFFSET $3
+    `
+	rows, err := r.conn.Query(ctx, query, webhookID, limit, offset)
+	if err != nil {
+		return nil, classify(err, "select webhook deliveries")
+	}
+	defer rows.Close()
+
+	deliveries := []*model.WebhookDelivery{}
+	for rows.Next() {
+		var d model.WebhookDelivery
+		err := rows.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &d.Status, &d.Attempts,
+			&d.LastStatusCode, &d.LastError, &d.NextAttemptAt, &d.CreatedAt, &d.DeliveredAt)
+		if err != nil {
+			return nil, classify(err, "scan webhook delivery")
+		}
+		deliveries = append(deliveries, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, classify(err, "rows webhook deliveries")
+	}
+	return deliveries, nil
+}
+
+// ClaimDeliveries picks due deliveries of enabled webhooks and pushes their next
+// attempt lease into the future, so other dispatchers skip them while they are sent.
+// A dispatcher that dies mid-send leaves the delivery to be retried once the lease ends.
+func (r *Repo) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*model.PendingDelivery, error) {
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	const query = `
+        WITH due AS (
+            SELECT d.id
+            FROM webhook_deliveries d
+            JOIN webhooks w ON w.id = d.webhook_id AND w.enabled
+            WHERE d.status = 'pending' AND d.next_attempt_at <= now()
+            ORDER BY d.next_attempt_at
+            LIMIT $1
+            FOR UPDATE OF d SKIP LOCKED
+        )
+        UPDATE webhook_deliveries d
+        SET next_attempt_at = now() + $2::interval
+        FROM due, webhooks w
+        WHERE d.id = due.id AND w.id = d.webhook_id
+        RETURNING d.id, d.webhook_id, w.url, w.secret, d.event_id, d.event_type, d.payload, d.attempts
+    `
+	rows, err := r.conn.Query(ctx, query, limit, lease)
+	if err != nil {
+		return nil, classify(err, "claim webhook deliveries")
+	}
+	defer rows.Close()
+
+	var deliveries []*model.PendingDelivery
+	for rows.Next() {
+		var d model.PendingDelivery
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.URL, &d.Secret, &d.EventID, &d.EventType, &d.Payload, &d.Attempts); err != nil {
+			return nil, classify(err, "scan webhook delivery")
+		}
+		deliveries = append(deliveries, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, classify(err, "rows webhook deliveries")
+	}
+	return deliveries, nil
+}
+
+func (r *Repo) CompleteDelivery(ctx context.Context, deliveryID, webhookID int64, statusCode int) error {
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	tx, err := r.beginShort(ctx, pgx.TxOptions{})
+	if err != nil {
+		return classify(err, "begin transaction")
+	}
+
+	defer tx.Rollback(ctx)
+
+	const deliveryQuery = `
+        UPDATE webhook_deliveries
+        SET status = 'delivered', attempts = attempts + 1, last_status_code = $2,
+            last_error = NULL, delivered_at = now()
+        WHERE id = $1
+    `
+	if _, err = tx.Exec(ctx, deliveryQuery, deliveryID, statusCode); err != nil {
+		return classify(err, "update webhook delivery")
+	}
+
+	if _, err = tx.Exec(ctx, `UPDATE webhooks SET consecutive_failures = 0 WHERE id = $1`, webhookID); err != nil {
+		return classify(err, "reset webhook failures")
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return classify(err, "commit tx")
+	}
+	return nil
+}
+
+// FailDelivery records a failed attempt and reports whether the webhook got disabled.
+func (r *Repo) FailDelivery(ctx context.Context, f model.DeliveryFailure) (bool, error) {
+	ctx, cancel := r.short(ctx)
+	defer cancel()
+
+	tx, err := r.beginShort(ctx, pgx.TxOptions{})
+	if err != nil {
+		return false, classify(err, "begin transaction")
+	}
+
+	defer tx.Rollback(ctx)
+
+	var statusCode *int
+	if f.StatusCode != 0 {
+		statusCode = &f.StatusCode
+	}
+
+	const deliveryQuery = `
+        UPDATE webhook_deliveries
+        SET attempts = attempts + 1, last_status_code = $2, last_error = $3,
+            status = CASE WHEN $4::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+            next_attempt_at = COALESCE($4, next_attempt_at)
+        WHERE id = $1
+    `
+	if _, err = tx.Exec(ctx, deliveryQuery, f.DeliveryID, statusCode, f.Error, f.RetryAt); err != nil {
+		return false, classify(err, "update webhook delivery")
+	}
+
+	const webhookQuery = `
+        UPDATE webhooks
+        SET consecutive_failures = consecutive_failures + 1,
+            enabled = enabled AND consecutive_failures + 1 < $2,
+            disabled_at = CASE
+                WHEN enabled AND consecutive_failures + 1 >= $2 THEN now()
+                ELSE disabled_at
+            END
+        WHERE id = $1
+        RETURNING COALESCE(NOT enabled AND disabled_at = now(), false)
+    `
+	var disabled bool
+	if err = tx.QueryRow(ctx, webhookQuery, f.WebhookID, f.DisableAfter).Scan(&disabled); err != nil {
+		return false, classify(err, "update webhook failures")
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, classify(err, "commit tx")
+	}
+	return disabled, nil
+}
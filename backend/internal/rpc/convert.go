@@ -0,0 +1,69 @@
+package rpc
+
+import (
+	orderv1 "github.com/GkadyrG/L0/backend/api/order/v1"
+	"github.com/GkadyrG/L0/backend/internal/feed"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toOrderResponse(o *model.OrderResponse) *orderv1.OrderResponse {
+	items := make([]*orderv1.ItemResponse, len(o.Items))
+	for i, item := range o.Items {
+		history := make([]*orderv1.StatusChange, len(item.History))
+		for j, h := range item.History {
+			history[j] = &orderv1.StatusChange{
+				Status:     int32(h.Status),
+				StatusName: h.StatusName,
+				ChangedAt:  timestamppb.New(h.ChangedAt),
+			}
+		}
+		items[i] = &orderv1.ItemResponse{
+			Rid:        item.RID,
+			Name:       item.Name,
+			Price:      item.Price,
+			Brand:      item.Brand,
+			Status:     int32(item.Status),
+			StatusName: item.StatusName,
+			History:    history,
+		}
+	}
+
+	return &orderv1.OrderResponse{
+		OrderUid:        o.OrderUID,
+		TrackNumber:     o.TrackNumber,
+		CustomerId:      o.CustomerID,
+		DeliveryService: o.DeliveryService,
+		DateCreated:     timestamppb.New(o.DateCreated),
+		Delivery: &orderv1.DeliveryResponse{
+			Name:    o.Delivery.Name,
+			Phone:   o.Delivery.Phone,
+			City:    o.Delivery.City,
+			Address: o.Delivery.Address,
+			Email:   o.Delivery.Email,
+		},
+		Payment: &orderv1.PaymentResponse{
+			Transaction: o.Payment.Transaction,
+			Currency:    o.Payment.Currency,
+			Amount:      o.Payment.Amount,
+			PaymentDt:   o.Payment.PaymentDT,
+		},
+		Items: items,
+	}
+}
+
+func toOrderPreview(p *model.OrderPreview) *orderv1.OrderPreview {
+	return &orderv1.OrderPreview{
+		OrderUid:    p.OrderUID,
+		TrackNumber: p.TrackNumber,
+		CustomerId:  p.CustomerID,
+		DateCreated: timestamppb.New(p.DateCreated),
+	}
+}
+
+func toOrderEvent(e feed.Event) *orderv1.OrderEvent {
+	return &orderv1.OrderEvent{
+		Id:    e.ID,
+		Order: toOrderPreview(&e.Preview),
+	}
+}
@@ -0,0 +1,170 @@
+package rpc
+
+import (
+	"context"
+	"log/slog"
+
+	orderv1 "github.com/GkadyrG/L0/backend/api/order/v1"
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/feed"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/usecase"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// OrderService serves the order API over gRPC on top of the same use case as the
+// HTTP handlers.
+type OrderService struct {
+	orderv1.UnimplementedOrderServiceServer
+
+	us     usecase.OrderProvider
+	query  usecase.QueryProvider
+	hub    *feed.Hub
+	logger *slog.Logger
+}
+
+func New(us usecase.OrderProvider, query usecase.QueryProvider, hub *feed.Hub, logger *slog.Logger) *OrderService {
+	return &OrderService{us: us, query: query, hub: hub, logger: logger}
+}
+
+// NewServer creates a gRPC server with the order service registered.
+func NewServer(svc *OrderService) *grpc.Server {
+	srv := grpc.NewServer()
+	orderv1.RegisterOrderServiceServer(srv, svc)
+	return srv
+}
+
+func (s *OrderService) GetOrder(ctx context.Context, req *orderv1.GetOrderRequest) (*orderv1.OrderResponse, error) {
+	if req.GetOrderUid() == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_uid is required")
+	}
+
+	order, err := s.us.GetByID(ctx, req.GetOrderUid())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to get order", "err", err)
+		return nil, toStatus(err)
+	}
+
+	return toOrderResponse(order), nil
+}
+
+func (s *OrderService) ListOrders(req *orderv1.ListOrdersRequest, stream grpc.ServerStreamingServer[orderv1.ListOrdersResponse]) error {
+	pageSize := int(req.GetPageSize())
+	switch {
+	case pageSize == 0:
+		pageSize = defaultPageSize
+	case pageSize < 0 || pageSize > maxPageSize:
+		return status.Error(codes.InvalidArgument, "page_size must be between 1 and 100")
+	}
+
+	filter := model.OrderFilter{
+		CustomerID:      req.GetCustomerId(),
+		DeliveryService: req.GetDeliveryService(),
+	}
+	if req.From != nil {
+		filter.From = req.GetFrom().AsTime()
+	}
+	if req.To != nil {
+		filter.To = req.GetTo().AsTime()
+	}
+
+	var after *model.OrderCursor
+	if token := req.GetPageToken(); token != "" {
+		cursor, err := model.ParseOrderCursor(token)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+		after = &cursor
+	}
+
+	// Pages are read one at a time with the same keyset query as /api/v2/orders, so a
+	// long listing never sits in memory as a whole.
+	ctx := stream.Context()
+	for {
+		page, err := s.query.ListOrders(ctx, filter, after, pageSize)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "failed to list orders", "err", err)
+			return toStatus(err)
+		}
+		if len(page.Orders) == 0 {
+			return nil
+		}
+
+		resp := &orderv1.ListOrdersResponse{Orders: make([]*orderv1.OrderPreview, len(page.Orders))}
+		for i, p := range page.Orders {
+			resp.Orders[i] = toOrderPreview(p)
+		}
+		if page.HasNextPage {
+			cursor := model.CursorOf(page.Orders[len(page.Orders)-1])
+			resp.NextPageToken = cursor.String()
+			after = &cursor
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+		if !page.HasNextPage {
+			return nil
+		}
+	}
+}
+
+// WatchOrders streams newly stored orders from the feed hub. When the client falls
+// too far behind or the server shuts down the stream ends with UNAVAILABLE, and the
+// client resumes with the id of the last event it received.
+func (s *OrderService) WatchOrders(req *orderv1.WatchOrdersRequest, stream grpc.ServerStreamingServer[orderv1.OrderEvent]) error {
+	filter := feed.Filter{
+		CustomerID:      req.GetCustomerId(),
+		DeliveryService: req.GetDeliveryService(),
+	}
+
+	sub, replay := s.hub.Subscribe(filter, req.LastEventId)
+	defer sub.Close()
+
+	for _, e := range replay {
+		if err := stream.Send(toOrderEvent(e)); err != nil {
+			return err
+		}
+	}
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case e, ok := <-sub.C:
+			if !ok {
+				return status.Error(codes.Unavailable, "order stream closed, resume with last_event_id")
+			}
+			if err := stream.Send(toOrderEvent(e)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+var statusCodes = map[apperr.Code]codes.Code{
+	apperr.CodeNotFound:        codes.NotFound,
+	apperr.CodeInvalidArgument: codes.InvalidArgument,
+	apperr.CodeConflict:        codes.FailedPrecondition,
+	apperr.CodeUnavailable:     codes.Unavailable,
+	apperr.CodeTimeout:         codes.DeadlineExceeded,
+	apperr.CodeRateLimited:     codes.ResourceExhausted,
+	apperr.CodeUnauthorized:    codes.Unauthenticated,
+	apperr.CodeForbidden:       codes.PermissionDenied,
+}
+
+// toStatus maps err to a gRPC status with the same classification the HTTP API uses.
+func toStatus(err error) error {
+	code, ok := statusCodes[apperr.CodeOf(err)]
+	if !ok {
+		return status.Error(codes.Internal, "internal server error")
+	}
+	return status.Error(code, apperr.Message(err))
+}
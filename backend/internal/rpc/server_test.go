@@ -0,0 +1,249 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	orderv1 "github.com/GkadyrG/L0/backend/api/order/v1"
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/feed"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/repository/mocks"
+	"github.com/GkadyrG/L0/backend/internal/usecase"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func newTestClient(t *testing.T, repo *mocks.OrderRepository, query *mocks.QueryRepository, hub *feed.Hub) orderv1.OrderServiceClient {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv := NewServer(New(usecase.New(repo), usecase.NewQuery(query), hub, logger))
+
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return orderv1.NewOrderServiceClient(conn)
+}
+
+func TestOrderService_GetOrder(t *testing.T) {
+	type testCase struct {
+		name      string
+		uid       string
+		mockSetup func(r *mocks.OrderRepository)
+		wantCode  codes.Code
+	}
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	order := &model.OrderResponse{
+		OrderUID:    "order-1",
+		TrackNumber: "TRK123",
+		CustomerID:  "cust-1",
+		DateCreated: now,
+		Payment:     model.PaymentResponse{Transaction: "tx-1", Currency: "USD", Amount: 100},
+		Items: []model.ItemResponse{{
+			RID:        "rid-1",
+			Name:       "Item A",
+			Status:     model.ItemStatusDelivered,
+			StatusName: model.ItemStatusDelivered.String(),
+			History:    []model.StatusChange{model.NewStatusChange(model.ItemStatusDelivered, now)},
+		}},
+	}
+
+	tests := []testCase{
+		{
+			name: "found",
+			uid:  "order-1",
+			mockSetup: func(r *mocks.OrderRepository) {
+				r.On("GetByID", mock.Anything, "order-1").Return(order, nil)
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "not found",
+			uid:  "missing",
+			mockSetup: func(r *mocks.OrderRepository) {
+				r.On("GetByID", mock.Anything, "missing").Return(nil, errors.Wrap(apperr.ErrNotFound, "order not found"))
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "repository failure",
+			uid:  "order-1",
+			mockSetup: func(r *mocks.OrderRepository) {
+				r.On("GetByID", mock.Anything, "order-1").Return(nil, errors.New("db down"))
+			},
+			wantCode: codes.Internal,
+		},
+		{
+			name:     "empty uid",
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewOrderRepository(t)
+			if tc.mockSetup != nil {
+				tc.mockSetup(repo)
+			}
+			client := newTestClient(t, repo, mocks.NewQueryRepository(t), feed.NewHub(10))
+
+			got, err := client.GetOrder(context.Background(), &orderv1.GetOrderRequest{OrderUid: tc.uid})
+
+			assert.Equal(t, tc.wantCode, status.Code(err))
+			if tc.wantCode != codes.OK {
+				return
+			}
+			assert.Equal(t, "TRK123", got.GetTrackNumber())
+			assert.Equal(t, now, got.GetDateCreated().AsTime())
+			require.Len(t, got.GetItems(), 1)
+			assert.Equal(t, int32(model.ItemStatusDelivered), got.GetItems()[0].GetStatus())
+			assert.Equal(t, now, got.GetItems()[0].GetHistory()[0].GetChangedAt().AsTime())
+		})
+	}
+}
+
+func TestOrderService_ListOrders(t *testing.T) {
+	previews := make([]*model.OrderPreview, 5)
+	for i := range previews {
+		previews[i] = &model.OrderPreview{
+			OrderUID:    fmt.Sprintf("order-%d", i),
+			CustomerID:  "cust-1",
+			DateCreated: time.Date(2024, 1, 5-i, 0, 0, 0, 0, time.UTC),
+		}
+	}
+	filter := model.OrderFilter{CustomerID: "cust-1", From: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	after := func(i int) *model.OrderCursor {
+		c := model.CursorOf(previews[i])
+		return &c
+	}
+
+	query := mocks.NewQueryRepository(t)
+	// every page asks for one extra row to learn whether another page follows
+	query.On("ListOrderPage", mock.Anything, filter, (*model.OrderCursor)(nil), 3).Return(previews[0:3], nil).Once()
+	query.On("ListOrderPage", mock.Anything, filter, after(1), 3).Return(previews[2:5], nil).Once()
+	query.On("ListOrderPage", mock.Anything, filter, after(3), 3).Return(previews[4:5], nil).Once()
+	client := newTestClient(t, mocks.NewOrderRepository(t), query, feed.NewHub(10))
+
+	stream, err := client.ListOrders(context.Background(), &orderv1.ListOrdersRequest{
+		CustomerId: "cust-1",
+		From:       timestamppb.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+		PageSize:   2,
+	})
+	require.NoError(t, err)
+
+	var pages [][]string
+	var tokens []string
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		var uids []string
+		for _, o := range resp.GetOrders() {
+			uids = append(uids, o.GetOrderUid())
+		}
+		pages = append(pages, uids)
+		tokens = append(tokens, resp.GetNextPageToken())
+	}
+	assert.Equal(t, [][]string{{"order-0", "order-1"}, {"order-2", "order-3"}, {"order-4"}}, pages)
+	assert.Equal(t, []string{after(1).String(), after(3).String(), ""}, tokens)
+}
+
+func TestOrderService_ListOrdersResume(t *testing.T) {
+	cursor := model.OrderCursor{DateCreated: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), OrderUID: "order-2"}
+	query := mocks.NewQueryRepository(t)
+	query.On("ListOrderPage", mock.Anything, model.OrderFilter{}, &cursor, defaultPageSize+1).
+		Return([]*model.OrderPreview{{OrderUID: "order-3"}}, nil)
+	client := newTestClient(t, mocks.NewOrderRepository(t), query, feed.NewHub(10))
+
+	stream, err := client.ListOrders(context.Background(), &orderv1.ListOrdersRequest{PageToken: cursor.String()})
+	require.NoError(t, err)
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	require.Len(t, resp.GetOrders(), 1)
+	assert.Equal(t, "order-3", resp.GetOrders()[0].GetOrderUid())
+	assert.Empty(t, resp.GetNextPageToken())
+
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestOrderService_ListOrdersErrors(t *testing.T) {
+	query := mocks.NewQueryRepository(t)
+	query.On("ListOrderPage", mock.Anything, model.OrderFilter{CustomerID: "broken"}, (*model.OrderCursor)(nil), defaultPageSize+1).
+		Return(nil, apperr.New(apperr.ErrUnavailable, "storage is unavailable"))
+	client := newTestClient(t, mocks.NewOrderRepository(t), query, feed.NewHub(10))
+
+	tests := []struct {
+		name     string
+		req      *orderv1.ListOrdersRequest
+		wantCode codes.Code
+	}{
+		{name: "store unavailable", req: &orderv1.ListOrdersRequest{CustomerId: "broken"}, wantCode: codes.Unavailable},
+		{name: "page too large", req: &orderv1.ListOrdersRequest{PageSize: 101}, wantCode: codes.InvalidArgument},
+		{name: "malformed page token", req: &orderv1.ListOrdersRequest{PageToken: "not a token"}, wantCode: codes.InvalidArgument},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			stream, err := client.ListOrders(context.Background(), tc.req)
+			require.NoError(t, err)
+			_, err = stream.Recv()
+			assert.Equal(t, tc.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestOrderService_WatchOrders(t *testing.T) {
+	hub := feed.NewHub(10)
+	client := newTestClient(t, mocks.NewOrderRepository(t), mocks.NewQueryRepository(t), hub)
+
+	hub.OrderSaved(&model.OrderResponse{OrderUID: "before", CustomerID: "cust-1"})
+
+	// resume from the start of the buffer: the order saved before subscribing is replayed
+	var lastID uint64
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.WatchOrders(ctx, &orderv1.WatchOrdersRequest{CustomerId: "cust-1", LastEventId: &lastID})
+	require.NoError(t, err)
+
+	got, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "before", got.GetOrder().GetOrderUid())
+
+	hub.OrderSaved(&model.OrderResponse{OrderUID: "other", CustomerID: "cust-2"})
+	hub.OrderSaved(&model.OrderResponse{OrderUID: "live", CustomerID: "cust-1"})
+
+	got, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "live", got.GetOrder().GetOrderUid())
+
+	hub.Close()
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
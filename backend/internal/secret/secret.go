@@ -0,0 +1,83 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// sealedPrefix marks values sealed by a Box; values without it are stored as they are.
+const sealedPrefix = "v1:"
+
+// KeySize is the length of a Box key in bytes, AES-256.
+const KeySize = 32
+
+// Box encrypts secrets kept in the database with AES-GCM. A nil Box, or one made
+// without a key, leaves them in plaintext.
+type Box struct {
+	aead cipher.AEAD
+}
+
+// NewBox takes a hex encoded key of KeySize bytes; an empty key gives a Box that
+// does not encrypt.
+func NewBox(key string) (*Box, error) {
+	if key == "" {
+		return &Box{}, nil
+	}
+
+	raw, err := hex.DecodeString(key)
+	if err != nil || len(raw) != KeySize {
+		return nil, errors.Errorf("secret key must be %d hex encoded bytes", KeySize)
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, errors.Wrap(err, "new cipher")
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "new gcm")
+	}
+	return &Box{aead: aead}, nil
+}
+
+// Seal encrypts plain for storage.
+func (b *Box) Seal(plain string) (string, error) {
+	if b == nil || b.aead == nil {
+		return plain, nil
+	}
+
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", errors.Wrap(err, "generate nonce")
+	}
+	sealed := b.aead.Seal(nonce, nonce, []byte(plain), nil)
+	return sealedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a stored value. Values stored before a key was configured are
+// returned as they are.
+func (b *Box) Open(stored string) (string, error) {
+	encoded, ok := strings.CutPrefix(stored, sealedPrefix)
+	if !ok {
+		return stored, nil
+	}
+	if b == nil || b.aead == nil {
+		return "", errors.New("secret is encrypted but no key is configured")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < b.aead.NonceSize() {
+		return "", errors.New("malformed encrypted secret")
+	}
+	nonce, ciphertext := sealed[:b.aead.NonceSize()], sealed[b.aead.NonceSize():]
+	plain, err := b.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "decrypt secret")
+	}
+	return string(plain), nil
+}
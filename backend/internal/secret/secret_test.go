@@ -0,0 +1,52 @@
+package secret
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKey = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+
+func TestBox_RoundTrip(t *testing.T) {
+	box, err := NewBox(testKey)
+	require.NoError(t, err)
+
+	sealed, err := box.Seal("webhook-secret")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(sealed, sealedPrefix))
+	assert.NotContains(t, sealed, "webhook-secret")
+
+	plain, err := box.Open(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "webhook-secret", plain)
+
+	plain, err = box.Open("stored-before-the-key")
+	require.NoError(t, err)
+	assert.Equal(t, "stored-before-the-key", plain, "plaintext values are read as they are")
+}
+
+func TestBox_WithoutKey(t *testing.T) {
+	box, err := NewBox("")
+	require.NoError(t, err)
+
+	sealed, err := box.Seal("webhook-secret")
+	require.NoError(t, err)
+	assert.Equal(t, "webhook-secret", sealed)
+
+	keyed, err := NewBox(testKey)
+	require.NoError(t, err)
+	encrypted, err := keyed.Seal("webhook-secret")
+	require.NoError(t, err)
+	_, err = box.Open(encrypted)
+	assert.Error(t, err)
+}
+
+func TestNewBox_InvalidKey(t *testing.T) {
+	for _, key := range []string{"short", strings.Repeat("zz", KeySize), testKey[:60]} {
+		_, err := NewBox(key)
+		assert.Error(t, err, key)
+	}
+}
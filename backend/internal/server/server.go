@@ -2,32 +2,61 @@ package server
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"net"
 	"net/http"
 	"time"
 
+	"github.com/GkadyrG/L0/backend/internal/health"
 	"github.com/GkadyrG/L0/backend/internal/kafka/consumer"
+	"github.com/GkadyrG/L0/backend/internal/ws"
+	"google.golang.org/grpc"
 )
 
 type Server struct {
 	httpsrv  *http.Server
+	grpcsrv  *grpc.Server
+	grpcAddr string
 	consumer *consumer.Consumer
-	logger   *slog.Logger
+	live     *ws.Hub
+	health   *health.Checker
+	// drainDelay keeps the listeners open after readiness flips, so that probes
+	// notice before connections are refused.
+	drainDelay time.Duration
+	logger     *slog.Logger
 }
 
-func NewServer(httpsrv *http.Server, consumer *consumer.Consumer, logger *slog.Logger) *Server {
+func NewServer(httpsrv *http.Server, grpcsrv *grpc.Server, grpcAddr string, consumer *consumer.Consumer, live *ws.Hub, checker *health.Checker, drainDelay time.Duration, logger *slog.Logger) *Server {
 	return &Server{
-		httpsrv:  httpsrv,
-		consumer: consumer,
-		logger:   logger,
+		httpsrv:    httpsrv,
+		grpcsrv:    grpcsrv,
+		grpcAddr:   grpcAddr,
+		consumer:   consumer,
+		live:       live,
+		health:     checker,
+		drainDelay: drainDelay,
+		logger:     logger,
 	}
 }
 
 func (s *Server) Start(ctx context.Context, topics []string) error {
+	lis, err := net.Listen("tcp", s.grpcAddr)
+	if err != nil {
+		return err
+	}
+
 	go func() {
 		s.consumer.Run(ctx, topics)
 	}()
 
+	go func() {
+		s.logger.Info("starting grpc server", "addr", s.grpcAddr)
+		if err := s.grpcsrv.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			s.logger.Error("grpc server failed", "err", err)
+		}
+	}()
+
 	s.logger.Info("starting http server", "addr", s.httpsrv.Addr)
 	if err := s.httpsrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		return err
@@ -39,18 +68,46 @@ func (s *Server) Start(ctx context.Context, topics []string) error {
 func (s *Server) Stop() error {
 	s.logger.Info("stopping server")
 
+	s.health.Drain()
+	if s.drainDelay > 0 {
+		s.logger.Info("draining before shutdown", "delay", s.drainDelay)
+		time.Sleep(s.drainDelay)
+	}
+
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	if err := s.httpsrv.Shutdown(shutdownCtx); err != nil {
-		return err
-	}
+	shutdownErr := s.httpsrv.Shutdown(shutdownCtx)
+
+	// Shutdown does not wait for hijacked WebSocket connections, close them explicitly.
+	// This runs even if Shutdown timed out, otherwise the clients would hang on.
+	s.live.Close()
+
+	s.stopGRPC(shutdownCtx)
 
 	if err := s.consumer.Close(); err != nil {
-		return err
+		return errors.Join(shutdownErr, err)
+	}
+	if shutdownErr != nil {
+		return shutdownErr
 	}
 
 	s.logger.Info("server stopped")
 
 	return nil
 }
+
+// stopGRPC waits for in-flight calls and cuts them off once ctx expires.
+func (s *Server) stopGRPC(ctx context.Context) {
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcsrv.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.grpcsrv.Stop()
+	}
+}
@@ -3,16 +3,26 @@ package storage
 import (
 	"context"
 	"log/slog"
+	"time"
 
+	"github.com/GkadyrG/L0/backend/internal/tracing"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgconn/ctxwatch"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/pkg/errors"
 )
 
+// GetConnect opens a connection pool. Every statement gets a span, and a statement
+// whose context ends is cancelled on the server too, not only abandoned by the driver.
 func GetConnect(connStr string) (*pgxpool.Pool, error) {
 	poolConfig, err := pgxpool.ParseConfig(connStr)
 	if err != nil {
 		return nil, errors.Wrap(err, "config parse")
 	}
+	poolConfig.ConnConfig.Tracer = tracing.QueryTracer{}
+	poolConfig.ConnConfig.BuildContextWatcherHandler = func(conn *pgconn.PgConn) ctxwatch.Handler {
+		return &pgconn.CancelRequestContextWatcherHandler{Conn: conn, DeadlineDelay: time.Second}
+	}
 	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
 		return nil, errors.Wrap(err, "newWithCongig")
@@ -0,0 +1,23 @@
+package tracing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryName(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{sql: "SELECT 1", want: "db SELECT"},
+		{sql: "\n        insert into orders (order_uid)\n VALUES ($1)", want: "db INSERT"},
+		{sql: "begin", want: "db BEGIN"},
+		{sql: "  ", want: "db query"},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, queryName(tc.sql), tc.sql)
+	}
+}
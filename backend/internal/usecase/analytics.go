@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/repository"
+)
+
+type Analytics struct {
+	repo repository.AnalyticsRepository
+}
+
+func NewAnalytics(repo repository.AnalyticsRepository) *Analytics {
+	return &Analytics{
+		repo: repo,
+	}
+}
+
+func (a *Analytics) OrderStats(ctx context.Context, q model.StatsQuery) (*model.OrderStats, error) {
+	buckets, err := a.repo.OrderStats(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return &model.OrderStats{StatsQuery: q, Buckets: buckets}, nil
+}
+
+func (a *Analytics) BasketStats(ctx context.Context, tr model.TimeRange) (*model.BasketStats, error) {
+	return a.repo.BasketStats(ctx, tr)
+}
+
+func (a *Analytics) TopReport(ctx context.Context, q model.TopQuery) (*model.TopReport, error) {
+	brands, err := a.repo.TopBrands(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := a.repo.TopProducts(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TopReport{TopQuery: q, Brands: brands, Products: products}, nil
+}
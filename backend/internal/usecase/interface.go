@@ -7,8 +7,42 @@ import (
 )
 
 type OrderProvider interface {
-	Save(ctx context.Context, order *model.Order) error
+	Save(ctx context.Context, order *model.Order) (bool, error)
+	SaveBatch(ctx context.Context, orders []*model.Order) ([]string, error)
 	GetByID(ctx context.Context, id string) (*model.OrderResponse, error)
-	GetAll(ctx context.Context) ([]*model.OrderPreview, error)
+	GetByTrackNumber(ctx context.Context, track string) (*model.OrderResponse, error)
+	ListByCustomer(ctx context.Context, customerID string, limit, offset int) ([]*model.OrderPreview, error)
+	GetAll(ctx context.Context, filter model.OrderFilter) ([]*model.OrderPreview, error)
 	GetAllFull(ctx context.Context, limit int) ([]*model.OrderResponse, error)
+	UpdateItemStatus(ctx context.Context, event *model.ItemStatusEvent) error
+	ExportOrders(ctx context.Context, filter model.OrderFilter, fn func(*model.ExportRow) error) error
+	GetTimeline(ctx context.Context, id string) (*model.OrderTimeline, error)
+}
+
+type QueryProvider interface {
+	ListOrders(ctx context.Context, filter model.OrderFilter, after *model.OrderCursor, first int) (*model.OrderPage, error)
+	CustomerOrders(ctx context.Context, customerIDs []string, after *model.OrderCursor, first int) (map[string]*model.OrderPage, error)
+	OrdersByUIDs(ctx context.Context, orderUIDs []string) ([]*model.OrderResponse, error)
+	ItemsByOrderUIDs(ctx context.Context, orderUIDs []string) (map[string][]model.ItemResponse, error)
+	OrderDetails(ctx context.Context, orderUID string) (*model.OrderDetails, error)
+	OrderDetailsByTrack(ctx context.Context, track string) (*model.OrderDetails, error)
+}
+
+type AnalyticsProvider interface {
+	OrderStats(ctx context.Context, q model.StatsQuery) (*model.OrderStats, error)
+	BasketStats(ctx context.Context, tr model.TimeRange) (*model.BasketStats, error)
+	TopReport(ctx context.Context, q model.TopQuery) (*model.TopReport, error)
+}
+
+type OutboxProvider interface {
+	State(ctx context.Context) (*model.OutboxState, error)
+}
+
+type WebhookProvider interface {
+	CreateWebhook(ctx context.Context, in model.WebhookInput) (*model.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]*model.Webhook, error)
+	GetWebhook(ctx context.Context, id int64) (*model.Webhook, error)
+	UpdateWebhook(ctx context.Context, id int64, in model.WebhookInput) (*model.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	ListDeliveries(ctx context.Context, id int64, limit, offset int) ([]*model.WebhookDelivery, error)
 }
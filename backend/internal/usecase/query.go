@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/repository"
+)
+
+type Query struct {
+	repo repository.QueryRepository
+}
+
+func NewQuery(repo repository.QueryRepository) *Query {
+	return &Query{
+		repo: repo,
+	}
+}
+
+// ListOrders returns the first orders after the cursor. One extra row is requested
+// to tell whether another page follows.
+func (q *Query) ListOrders(ctx context.Context, filter model.OrderFilter, after *model.OrderCursor, first int) (*model.OrderPage, error) {
+	previews, err := q.repo.ListOrderPage(ctx, filter, after, first+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.OrderPage{Orders: previews}
+	if len(previews) > first {
+		page.Orders = previews[:first]
+		page.HasNextPage = true
+	}
+	return page, nil
+}
+
+// CustomerOrders returns one page of orders for each customer, all read in one query.
+// Customers without orders get an empty page.
+func (q *Query) CustomerOrders(ctx context.Context, customerIDs []string, after *model.OrderCursor, first int) (map[string]*model.OrderPage, error) {
+	previews, err := q.repo.ListCustomerOrderPages(ctx, customerIDs, after, first+1)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make(map[string]*model.OrderPage, len(customerIDs))
+	for _, id := range customerIDs {
+		pages[id] = &model.OrderPage{Orders: []*model.OrderPreview{}}
+	}
+	for _, p := range previews {
+		page := pages[p.CustomerID]
+		if len(page.Orders) == first {
+			page.HasNextPage = true
+			continue
+		}
+		page.Orders = append(page.Orders, p)
+	}
+	return pages, nil
+}
+
+func (q *Query) OrdersByUIDs(ctx context.Context, orderUIDs []string) ([]*model.OrderResponse, error) {
+	return q.repo.GetOrdersByUIDs(ctx, orderUIDs)
+}
+
+func (q *Query) ItemsByOrderUIDs(ctx context.Context, orderUIDs []string) (map[string][]model.ItemResponse, error) {
+	return q.repo.GetItemsByOrderUIDs(ctx, orderUIDs)
+}
+
+func (q *Query) OrderDetails(ctx context.Context, orderUID string) (*model.OrderDetails, error) {
+	return q.repo.GetOrderDetails(ctx, orderUID)
+}
+
+func (q *Query) OrderDetailsByTrack(ctx context.Context, track string) (*model.OrderDetails, error) {
+	return q.repo.GetOrderDetailsByTrack(ctx, track)
+}
@@ -17,18 +17,46 @@ func New(repo repository.OrderRepository) *UseCase {
 	}
 }
 
-func (u *UseCase) Save(ctx context.Context, order *model.Order) error {
+func (u *UseCase) Save(ctx context.Context, order *model.Order) (bool, error) {
 	return u.repo.Save(ctx, order)
 }
 
+func (u *UseCase) SaveBatch(ctx context.Context, orders []*model.Order) ([]string, error) {
+	return u.repo.SaveBatch(ctx, orders)
+}
+
 func (u *UseCase) GetByID(ctx context.Context, id string) (*model.OrderResponse, error) {
 	return u.repo.GetByID(ctx, id)
 }
 
-func (u *UseCase) GetAll(ctx context.Context) ([]*model.OrderPreview, error) {
-	return u.repo.GetAll(ctx)
+func (u *UseCase) GetByTrackNumber(ctx context.Context, track string) (*model.OrderResponse, error) {
+	return u.repo.GetByTrackNumber(ctx, track)
+}
+
+func (u *UseCase) ListByCustomer(ctx context.Context, customerID string, limit, offset int) ([]*model.OrderPreview, error) {
+	return u.repo.ListByCustomer(ctx, customerID, limit, offset)
+}
+
+func (u *UseCase) GetAll(ctx context.Context, filter model.OrderFilter) ([]*model.OrderPreview, error) {
+	return u.repo.GetAll(ctx, filter)
 }
 
 func (u *UseCase) GetAllFull(ctx context.Context, limit int) ([]*model.OrderResponse, error) {
 	return u.repo.GetAllFull(ctx, limit)
 }
+
+func (u *UseCase) UpdateItemStatus(ctx context.Context, event *model.ItemStatusEvent) error {
+	return u.repo.UpdateItemStatus(ctx, event)
+}
+
+func (u *UseCase) GetTimeline(ctx context.Context, id string) (*model.OrderTimeline, error) {
+	order, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return order.Timeline(), nil
+}
+
+func (u *UseCase) ExportOrders(ctx context.Context, filter model.OrderFilter, fn func(*model.ExportRow) error) error {
+	return u.repo.ExportOrders(ctx, filter, fn)
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/repository"
+	"github.com/GkadyrG/L0/backend/internal/secret"
+	"github.com/pkg/errors"
+)
+
+const minSecretLength = 16
+
+// Webhooks stores secrets sealed by box, so they are encrypted at rest when it has a key.
+type Webhooks struct {
+	repo repository.WebhookRepository
+	box  *secret.Box
+}
+
+func NewWebhooks(repo repository.WebhookRepository, box *secret.Box) *Webhooks {
+	return &Webhooks{repo: repo, box: box}
+}
+
+// CreateWebhook registers a subscription. Without a secret in the input a random one
+// is generated; either way it is returned only in this response.
+func (u *Webhooks) CreateWebhook(ctx context.Context, in model.WebhookInput) (*model.Webhook, error) {
+	if in.URL == nil {
+		return nil, apperr.New(apperr.ErrInvalidArgument, "url is required")
+	}
+	if err := validateWebhookInput(in); err != nil {
+		return nil, err
+	}
+
+	secret := ""
+	if in.Secret != nil {
+		secret = *in.Secret
+	} else {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return nil, errors.Wrap(err, "generate secret")
+		}
+		secret = hex.EncodeToString(b)
+	}
+
+	sealed, err := u.box.Seal(secret)
+	if err != nil {
+		return nil, err
+	}
+	w, err := u.repo.CreateWebhook(ctx, *in.URL, sealed, in.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+	w.Secret = secret
+	return w, nil
+}
+
+func (u *Webhooks) ListWebhooks(ctx context.Context) ([]*model.Webhook, error) {
+	return u.repo.ListWebhooks(ctx)
+}
+
+func (u *Webhooks) GetWebhook(ctx context.Context, id int64) (*model.Webhook, error) {
+	return u.repo.GetWebhook(ctx, id)
+}
+
+func (u *Webhooks) UpdateWebhook(ctx context.Context, id int64, in model.WebhookInput) (*model.Webhook, error) {
+	if err := validateWebhookInput(in); err != nil {
+		return nil, err
+	}
+	if in.Secret != nil {
+		sealed, err := u.box.Seal(*in.Secret)
+		if err != nil {
+			return nil, err
+		}
+		in.Secret = &sealed
+	}
+	return u.repo.UpdateWebhook(ctx, id, in)
+}
+
+func (u *Webhooks) DeleteWebhook(ctx context.Context, id int64) error {
+	return u.repo.DeleteWebhook(ctx, id)
+}
+
+func (u *Webhooks) ListDeliveries(ctx context.Context, id int64, limit, offset int) ([]*model.WebhookDelivery, error) {
+	if _, err := u.repo.GetWebhook(ctx, id); err != nil {
+		return nil, err
+	}
+	return u.repo.ListDeliveries(ctx, id, limit, offset)
+}
+
+func validateWebhookInput(in model.WebhookInput) error {
+	if in.URL != nil {
+		u, err := url.Parse(*in.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return apperr.New(apperr.ErrInvalidArgument, "url must be an absolute http or https URL")
+		}
+	}
+
+	for _, t := range in.EventTypes {
+		if !t.Valid() {
+			return apperr.New(apperr.ErrInvalidArgument, fmt.Sprintf("unknown event type %q", t))
+		}
+	}
+
+	if in.Secret != nil && len(*in.Secret) < minSecretLength {
+		return apperr.New(apperr.ErrInvalidArgument, fmt.Sprintf("secret must be at least %d characters", minSecretLength))
+	}
+
+	return nil
+}
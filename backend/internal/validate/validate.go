@@ -8,7 +8,9 @@ import (
 
 var validate = validator.New()
 
-func ValidateOrder(order model.Order) error {
+// Validations are registered once: RegisterValidation is not safe to call while
+// other goroutines are validating.
+func init() {
 	_ = validate.RegisterValidation("e164", func(fl validator.FieldLevel) bool {
 		phone := fl.Field().String()
 		if len(phone) < 4 || phone[0] != '+' {
@@ -16,8 +18,23 @@ func ValidateOrder(order model.Order) error {
 		}
 		return true
 	})
+	_ = validate.RegisterValidation("item_status", validItemStatus)
+}
+
+func ValidateOrder(order model.Order) error {
+	return validateStruct(order)
+}
+
+func ValidateStatusEvent(event model.ItemStatusEvent) error {
+	return validateStruct(event)
+}
+
+func validItemStatus(fl validator.FieldLevel) bool {
+	return model.ItemStatus(fl.Field().Int()).Valid()
+}
 
-	if err := validate.Struct(order); err != nil {
+func validateStruct(s any) error {
+	if err := validate.Struct(s); err != nil {
 		for _, verr := range err.(validator.ValidationErrors) {
 			return errors.Errorf("validation failed for field '%s', tag '%s'", verr.StructNamespace(), verr.Tag())
 		}
@@ -0,0 +1,186 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/config"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/repository"
+	"github.com/GkadyrG/L0/backend/internal/secret"
+	"github.com/pkg/errors"
+)
+
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderEventID   = "X-Webhook-Event-Id"
+)
+
+// Sign returns the value of the signature header: the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the webhook secret, prefixed with "sha256=".
+// Receivers recompute it and reject requests with a stale timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Dispatcher sends queued webhook deliveries. Any 2xx response counts as delivered;
+// other responses and transport errors are retried with exponential backoff.
+type Dispatcher struct {
+	repo   repository.WebhookRepository
+	box    *secret.Box
+	client *http.Client
+	cfg    config.WebhookConfig
+	logger *slog.Logger
+}
+
+func NewDispatcher(cfg *config.Config, repo repository.WebhookRepository, box *secret.Box, logger *slog.Logger) *Dispatcher {
+	wcfg := cfg.Webhook
+	wcfg.BatchSize = max(wcfg.BatchSize, 1)
+
+	return &Dispatcher{
+		repo:   repo,
+		box:    box,
+		client: &http.Client{Timeout: wcfg.Timeout},
+		cfg:    wcfg,
+		logger: logger,
+	}
+}
+
+func (d *Dispatcher) Run(ctx context.Context) {
+	d.logger.Info("webhook dispatcher started")
+
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// A full batch means more deliveries are due: send them back to back
+		// instead of one batch per tick, until none are left or we stop.
+		for ctx.Err() == nil {
+			if d.dispatchBatch(ctx) < d.cfg.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			d.logger.Info("webhook dispatcher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatchBatch sends the claimed deliveries concurrently, so each is done within
+// one send timeout of its claim.
+func (d *Dispatcher) dispatchBatch(ctx context.Context) int {
+	// The lease outlives a send, so a delivery is not picked up twice while in flight.
+	lease := 2*d.cfg.Timeout + d.cfg.PollInterval
+	deliveries, err := d.repo.ClaimDeliveries(ctx, d.cfg.BatchSize, lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			d.logger.Error("failed to claim webhook deliveries", "error", err)
+		}
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.deliver(ctx, delivery)
+		}()
+	}
+	wg.Wait()
+	return len(deliveries)
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery *model.PendingDelivery) {
+	code, err := d.send(ctx, delivery)
+	if err == nil {
+		if err := d.repo.CompleteDelivery(ctx, delivery.ID, delivery.WebhookID, code); err != nil {
+			d.logger.Error("failed to record webhook delivery", "error", err, "delivery_id", delivery.ID)
+		}
+		return
+	}
+
+	attempt := delivery.Attempts + 1
+	failure := model.DeliveryFailure{
+		DeliveryID:   delivery.ID,
+		WebhookID:    delivery.WebhookID,
+		StatusCode:   code,
+		Error:        err.Error(),
+		DisableAfter: d.cfg.DisableAfter,
+	}
+	if attempt < d.cfg.MaxAttempts {
+		retryAt := time.Now().Add(d.backoff(attempt))
+		failure.RetryAt = &retryAt
+	}
+
+	d.logger.Warn("webhook delivery failed",
+		"error", err, "delivery_id", delivery.ID, "webhook_id", delivery.WebhookID, "attempt", attempt)
+
+	disabled, err := d.repo.FailDelivery(ctx, failure)
+	if err != nil {
+		d.logger.Error("failed to record webhook failure", "error", err, "delivery_id", delivery.ID)
+		return
+	}
+	if disabled {
+		d.logger.Warn("webhook disabled after repeated failures", "webhook_id", delivery.WebhookID)
+	}
+}
+
+// backoff returns the delay before the attempt following the given one.
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	delay := d.cfg.Backoff
+	for i := 1; i < attempt && delay < d.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, d.cfg.MaxBackoff)
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery *model.PendingDelivery) (int, error) {
+	key, err := d.box.Open(delivery.Secret)
+	if err != nil {
+		return 0, errors.Wrap(err, "open webhook secret")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, errors.Wrap(err, "new request")
+	}
+
+	ts := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(HeaderSignature, Sign(key, ts, delivery.Payload))
+	req.Header.Set(HeaderEvent, string(delivery.EventType))
+	req.Header.Set(HeaderEventID, delivery.EventID)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, errors.Wrap(err, "send webhook")
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, errors.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
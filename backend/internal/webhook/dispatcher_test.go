@@ -0,0 +1,179 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/config"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/GkadyrG/L0/backend/internal/repository/mocks"
+	"github.com/GkadyrG/L0/backend/internal/secret"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "0123456789abcdef"
+
+func newTestDispatcher(repo *mocks.WebhookRepository) *Dispatcher {
+	cfg := &config.Config{Webhook: config.WebhookConfig{
+		PollInterval: time.Second,
+		BatchSize:    10,
+		Timeout:      time.Second,
+		MaxAttempts:  3,
+		Backoff:      time.Second,
+		MaxBackoff:   3 * time.Second,
+		DisableAfter: 5,
+	}}
+	return NewDispatcher(cfg, repo, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func pendingDelivery(url string, attempts int) *model.PendingDelivery {
+	return &model.PendingDelivery{
+		ID:        1,
+		WebhookID: 7,
+		URL:       url,
+		Secret:    testSecret,
+		EventID:   "order.stored:order-1",
+		EventType: model.EventOrderStored,
+		Payload:   []byte(`{"id":"order.stored:order-1","type":"order.stored"}`),
+		Attempts:  attempts,
+	}
+}
+
+func TestDispatcher_DeliversSignedPayload(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		ts, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		require.NoError(t, err)
+		if r.Header.Get(HeaderSignature) != Sign(testSecret, ts, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		received <- r
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	repo := mocks.NewWebhookRepository(t)
+	repo.On("ClaimDeliveries", mock.Anything, 10, mock.Anything).
+		Return([]*model.PendingDelivery{pendingDelivery(receiver.URL, 0)}, nil)
+	repo.On("CompleteDelivery", mock.Anything, int64(1), int64(7), http.StatusNoContent).Return(nil)
+
+	assert.Equal(t, 1, newTestDispatcher(repo).dispatchBatch(context.Background()))
+
+	r := <-received
+	assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+	assert.Equal(t, "order.stored", r.Header.Get(HeaderEvent))
+	assert.Equal(t, "order.stored:order-1", r.Header.Get(HeaderEventID))
+}
+
+func TestDispatcher_SendsBatchConcurrently(t *testing.T) {
+	// each request waits for the other, so sequential sends would time out
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		arrived.Done()
+		arrived.Wait()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	first, second := pendingDelivery(receiver.URL, 0), pendingDelivery(receiver.URL, 0)
+	second.ID = 2
+
+	repo := mocks.NewWebhookRepository(t)
+	repo.On("ClaimDeliveries", mock.Anything, 10, mock.Anything).Return([]*model.PendingDelivery{first, second}, nil)
+	repo.On("CompleteDelivery", mock.Anything, int64(1), int64(7), http.StatusNoContent).Return(nil).Once()
+	repo.On("CompleteDelivery", mock.Anything, int64(2), int64(7), http.StatusNoContent).Return(nil).Once()
+
+	assert.Equal(t, 2, newTestDispatcher(repo).dispatchBatch(context.Background()))
+}
+
+func TestDispatcher_OpensSealedSecret(t *testing.T) {
+	box, err := secret.NewBox("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")
+	require.NoError(t, err)
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		ts, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		require.NoError(t, err)
+		if r.Header.Get(HeaderSignature) != Sign(testSecret, ts, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	delivery := pendingDelivery(receiver.URL, 0)
+	delivery.Secret, err = box.Seal(testSecret)
+	require.NoError(t, err)
+
+	repo := mocks.NewWebhookRepository(t)
+	repo.On("ClaimDeliveries", mock.Anything, 10, mock.Anything).Return([]*model.PendingDelivery{delivery}, nil)
+	repo.On("CompleteDelivery", mock.Anything, int64(1), int64(7), http.StatusNoContent).Return(nil)
+
+	d := newTestDispatcher(repo)
+	d.box = box
+	d.dispatchBatch(context.Background())
+}
+
+func TestDispatcher_FailedDelivery(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	tests := []struct {
+		name      string
+		attempts  int
+		wantRetry bool
+	}{
+		{name: "retried with backoff", attempts: 1, wantRetry: true},
+		{name: "given up after max attempts", attempts: 2, wantRetry: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewWebhookRepository(t)
+			repo.On("ClaimDeliveries", mock.Anything, 10, mock.Anything).
+				Return([]*model.PendingDelivery{pendingDelivery(receiver.URL, tt.attempts)}, nil)
+
+			before := time.Now()
+			repo.On("FailDelivery", mock.Anything, mock.MatchedBy(func(f model.DeliveryFailure) bool {
+				if f.DeliveryID != 1 || f.WebhookID != 7 || f.StatusCode != http.StatusInternalServerError || f.DisableAfter != 5 {
+					return false
+				}
+				if !tt.wantRetry {
+					return f.RetryAt == nil
+				}
+				// second attempt failed, so the delay has doubled once
+				return f.RetryAt != nil && !f.RetryAt.Before(before.Add(2*time.Second))
+			})).Return(false, nil)
+
+			newTestDispatcher(repo).dispatchBatch(context.Background())
+		})
+	}
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	d := newTestDispatcher(mocks.NewWebhookRepository(t))
+
+	assert.Equal(t, time.Second, d.backoff(1))
+	assert.Equal(t, 2*time.Second, d.backoff(2))
+	assert.Equal(t, 3*time.Second, d.backoff(3))
+	assert.Equal(t, 3*time.Second, d.backoff(10))
+}
@@ -0,0 +1,135 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type client struct {
+	hub  *Hub
+	conn *websocket.Conn
+	ctx  context.Context
+
+	queue chan []byte
+	// subs is guarded by hub.mu.
+	subs map[string]struct{}
+
+	closeOnce sync.Once
+	done      chan struct{}
+	cancel    context.CancelFunc
+	closeCode int
+	closeText string
+}
+
+func newClient(h *Hub, conn *websocket.Conn) *client {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &client{
+		hub:    h,
+		conn:   conn,
+		ctx:    ctx,
+		cancel: cancel,
+		queue:  make(chan []byte, h.sendBuffer),
+		subs:   make(map[string]struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+func (c *client) send(msg serverMessage) {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		c.hub.logger.Error("failed to marshal websocket message", "error", err)
+		return
+	}
+	c.enqueue(b)
+}
+
+// enqueue never blocks: a full queue means the client cannot keep up, and it is
+// disconnected so it can reconnect and subscribe again.
+func (c *client) enqueue(msg []byte) {
+	select {
+	case <-c.done:
+	case c.queue <- msg:
+	default:
+		c.shutdown(websocket.ClosePolicyViolation, "send buffer overflow")
+	}
+}
+
+// shutdown asks the write pump to send a close frame with code and text and to close
+// the connection. Only the first call has an effect.
+func (c *client) shutdown(code int, text string) {
+	c.closeOnce.Do(func() {
+		c.closeCode = code
+		c.closeText = text
+		close(c.done)
+		c.cancel()
+	})
+}
+
+func (c *client) readPump() {
+	c.conn.SetReadLimit(maxMessageSize)
+	readWait := 2 * c.hub.pingInterval
+	_ = c.conn.SetReadDeadline(time.Now().Add(readWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(readWait))
+	})
+
+	for {
+		var msg clientMessage
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			var (
+				syntaxErr *json.SyntaxError
+				typeErr   *json.UnmarshalTypeError
+			)
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				c.send(serverMessage{Type: "error", Error: "invalid message"})
+				continue
+			}
+			c.shutdown(websocket.CloseNormalClosure, "")
+			return
+		}
+
+		switch msg.Action {
+		case "subscribe":
+			c.hub.subscribe(c, msg.OrderUIDs)
+		case "unsubscribe":
+			c.hub.unsubscribe(c, msg.OrderUIDs)
+		default:
+			c.send(serverMessage{Type: "error", Error: "unknown action"})
+		}
+	}
+}
+
+func (c *client) writePump() {
+	ticker := time.NewTicker(c.hub.pingInterval)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case msg := <-c.queue:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				c.shutdown(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				c.shutdown(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-c.done:
+			if c.closeCode != websocket.CloseAbnormalClosure {
+				msg := websocket.FormatCloseMessage(c.closeCode, c.closeText)
+				_ = c.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(writeWait))
+			}
+			return
+		}
+	}
+}
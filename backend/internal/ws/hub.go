@@ -0,0 +1,224 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/config"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait           = 10 * time.Second
+	maxMessageSize      = 4 << 10
+	defaultPingInterval = 30 * time.Second
+)
+
+// OrderLoader returns the current state of an order, sent when a client subscribes.
+type OrderLoader interface {
+	GetByID(ctx context.Context, id string) (*model.OrderResponse, error)
+}
+
+// Hub routes order changes to the clients subscribed to them. Every client has a
+// bounded send queue; a client that lets it fill up is disconnected rather than
+// slowing down the publisher.
+type Hub struct {
+	loader           OrderLoader
+	sendBuffer       int
+	maxSubscriptions int
+	pingInterval     time.Duration
+	logger           *slog.Logger
+
+	mu      sync.RWMutex
+	subs    map[string]map[*client]struct{}
+	clients map[*client]struct{}
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+func NewHub(cfg *config.Config, loader OrderLoader, logger *slog.Logger) *Hub {
+	pingInterval := cfg.WS.PingInterval
+	if pingInterval <= 0 {
+		pingInterval = defaultPingInterval
+	}
+
+	return &Hub{
+		loader:           loader,
+		sendBuffer:       max(cfg.WS.SendBuffer, 1),
+		maxSubscriptions: max(cfg.WS.MaxSubscriptions, 1),
+		pingInterval:     pingInterval,
+		logger:           logger,
+		subs:             make(map[string]map[*client]struct{}),
+		clients:          make(map[*client]struct{}),
+	}
+}
+
+type clientMessage struct {
+	Action    string   `json:"action"`
+	OrderUIDs []string `json:"order_uids"`
+}
+
+type serverMessage struct {
+	Type      string               `json:"type"`
+	OrderUIDs []string             `json:"order_uids,omitempty"`
+	OrderUID  string               `json:"order_uid,omitempty"`
+	Order     *model.OrderResponse `json:"order,omitempty"`
+	Error     string               `json:"error,omitempty"`
+}
+
+// OrderSaved is called by the cache only for orders the repository actually stored,
+// so a redelivered duplicate never pushes a stale payload over a newer state.
+func (h *Hub) OrderSaved(order *model.OrderResponse) {
+	h.broadcast(order)
+}
+
+func (h *Hub) OrderUpdated(order *model.OrderResponse) {
+	h.broadcast(order)
+}
+
+func (h *Hub) broadcast(order *model.OrderResponse) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	clients := h.subs[order.OrderUID]
+	if len(clients) == 0 {
+		return
+	}
+
+	msg, err := json.Marshal(serverMessage{Type: "order", Order: order})
+	if err != nil {
+		h.logger.Error("failed to marshal order", "error", err, "order_uid", order.OrderUID)
+		return
+	}
+	for c := range clients {
+		c.enqueue(msg)
+	}
+}
+
+// Serve runs the connection until the client goes away, falls behind or the hub is
+// closed. orderUIDs are subscribed right away.
+func (h *Hub) Serve(conn *websocket.Conn, orderUIDs []string) {
+	c := newClient(h, conn)
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		c.shutdown(websocket.CloseGoingAway, "server shutting down")
+		c.writePump()
+		return
+	}
+	h.clients[c] = struct{}{}
+	h.wg.Add(1)
+	h.mu.Unlock()
+
+	go func() {
+		defer h.wg.Done()
+		c.writePump()
+	}()
+
+	if len(orderUIDs) > 0 {
+		h.subscribe(c, orderUIDs)
+	}
+	c.readPump()
+
+	h.unregister(c)
+}
+
+func (h *Hub) subscribe(c *client, orderUIDs []string) {
+	orderUIDs = unique(orderUIDs)
+
+	h.mu.Lock()
+	added := 0
+	for _, uid := range orderUIDs {
+		if _, ok := c.subs[uid]; !ok {
+			added++
+		}
+	}
+	if len(c.subs)+added > h.maxSubscriptions {
+		h.mu.Unlock()
+		c.send(serverMessage{Type: "error", Error: "too many subscriptions"})
+		return
+	}
+	for _, uid := range orderUIDs {
+		c.subs[uid] = struct{}{}
+		if h.subs[uid] == nil {
+			h.subs[uid] = make(map[*client]struct{})
+		}
+		h.subs[uid][c] = struct{}{}
+	}
+	h.mu.Unlock()
+
+	c.send(serverMessage{Type: "subscribed", OrderUIDs: orderUIDs})
+
+	for _, uid := range orderUIDs {
+		order, err := h.loader.GetByID(c.ctx, uid)
+		if err != nil {
+			c.send(serverMessage{Type: "error", OrderUID: uid, Error: "order not found"})
+			continue
+		}
+		c.send(serverMessage{Type: "order", Order: order})
+	}
+}
+
+// unique drops repeated uids keeping the first occurrence order, so that a client
+// listing an order twice is not charged two subscriptions for it.
+func unique(orderUIDs []string) []string {
+	seen := make(map[string]struct{}, len(orderUIDs))
+	out := orderUIDs[:0:0]
+	for _, uid := range orderUIDs {
+		if _, ok := seen[uid]; ok {
+			continue
+		}
+		seen[uid] = struct{}{}
+		out = append(out, uid)
+	}
+	return out
+}
+
+func (h *Hub) unsubscribe(c *client, orderUIDs []string) {
+	h.mu.Lock()
+	for _, uid := range orderUIDs {
+		h.removeLocked(c, uid)
+	}
+	h.mu.Unlock()
+
+	c.send(serverMessage{Type: "unsubscribed", OrderUIDs: orderUIDs})
+}
+
+func (h *Hub) unregister(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for uid := range c.subs {
+		h.removeLocked(c, uid)
+	}
+	delete(h.clients, c)
+}
+
+func (h *Hub) removeLocked(c *client, uid string) {
+	delete(c.subs, uid)
+	if clients := h.subs[uid]; clients != nil {
+		delete(clients, c)
+		if len(clients) == 0 {
+			delete(h.subs, uid)
+		}
+	}
+}
+
+// Close sends a going-away close frame to every client and waits until the frames
+// are written. Hijacked connections are not tracked by http.Server.Shutdown, so this
+// is what ends them on shutdown.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	h.closed = true
+	for c := range h.clients {
+		c.shutdown(websocket.CloseGoingAway, "server shutting down")
+	}
+	h.mu.Unlock()
+
+	h.wg.Wait()
+}
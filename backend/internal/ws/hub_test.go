@@ -0,0 +1,131 @@
+package ws
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GkadyrG/L0/backend/config"
+	"github.com/GkadyrG/L0/backend/internal/apperr"
+	"github.com/GkadyrG/L0/backend/internal/model"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLoader map[string]*model.OrderResponse
+
+func (f fakeLoader) GetByID(_ context.Context, id string) (*model.OrderResponse, error) {
+	if o, ok := f[id]; ok {
+		return o, nil
+	}
+	return nil, apperr.ErrNotFound
+}
+
+func newTestHub(loader OrderLoader, sendBuffer int) *Hub {
+	cfg := &config.Config{WS: config.WSConfig{SendBuffer: sendBuffer, MaxSubscriptions: 2, PingInterval: time.Minute}}
+	return NewHub(cfg, loader, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func dial(t *testing.T, h *Hub, query string) *websocket.Conn {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		h.Serve(conn, r.URL.Query()["order_uid"])
+	}))
+	t.Cleanup(srv.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+query, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func read(t *testing.T, conn *websocket.Conn) serverMessage {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	var msg serverMessage
+	require.NoError(t, conn.ReadJSON(&msg))
+	return msg
+}
+
+func TestHub_SubscribeAndReceiveUpdates(t *testing.T) {
+	h := newTestHub(fakeLoader{"order-1": {OrderUID: "order-1", TrackNumber: "TRK1"}}, 8)
+	conn := dial(t, h, "?order_uid=order-1")
+
+	assert.Equal(t, serverMessage{Type: "subscribed", OrderUIDs: []string{"order-1"}}, read(t, conn))
+	snapshot := read(t, conn)
+	assert.Equal(t, "order", snapshot.Type)
+	assert.Equal(t, "TRK1", snapshot.Order.TrackNumber)
+
+	require.NoError(t, conn.WriteJSON(clientMessage{Action: "subscribe", OrderUIDs: []string{"order-2"}}))
+	assert.Equal(t, "subscribed", read(t, conn).Type)
+	assert.Equal(t, serverMessage{Type: "error", OrderUID: "order-2", Error: "order not found"}, read(t, conn))
+
+	require.NoError(t, conn.WriteJSON(clientMessage{Action: "subscribe", OrderUIDs: []string{"order-3"}}))
+	assert.Equal(t, "too many subscriptions", read(t, conn).Error)
+
+	h.OrderUpdated(&model.OrderResponse{OrderUID: "order-3"})
+	h.OrderUpdated(&model.OrderResponse{OrderUID: "order-1", TrackNumber: "TRK1-updated"})
+	h.OrderSaved(&model.OrderResponse{OrderUID: "order-2", TrackNumber: "TRK2"})
+
+	assert.Equal(t, "TRK1-updated", read(t, conn).Order.TrackNumber)
+	assert.Equal(t, "TRK2", read(t, conn).Order.TrackNumber)
+}
+
+func TestHub_CloseSendsGoingAway(t *testing.T) {
+	h := newTestHub(fakeLoader{}, 8)
+	conn := dial(t, h, "")
+
+	require.NoError(t, conn.WriteJSON(clientMessage{Action: "subscribe", OrderUIDs: []string{"order-1"}}))
+	assert.Equal(t, "subscribed", read(t, conn).Type)
+
+	h.Close()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	for {
+		_, _, err := conn.ReadMessage()
+		if err != nil {
+			assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), err)
+			break
+		}
+	}
+}
+
+func TestClient_OverflowDisconnects(t *testing.T) {
+	h := newTestHub(fakeLoader{}, 2)
+	c := newClient(h, nil)
+
+	c.enqueue([]byte("1"))
+	c.enqueue([]byte("2"))
+	select {
+	case <-c.done:
+		t.Fatal("client closed before its buffer was full")
+	default:
+	}
+
+	c.enqueue([]byte("3"))
+	<-c.done
+	assert.Equal(t, websocket.ClosePolicyViolation, c.closeCode)
+	assert.Len(t, c.queue, 2)
+}
+
+func TestHub_SubscribeCountsUniqueOrders(t *testing.T) {
+	h := newTestHub(fakeLoader{}, 8)
+	conn := dial(t, h, "?order_uid=order-1&order_uid=order-1&order_uid=order-2")
+
+	assert.Equal(t, serverMessage{Type: "subscribed", OrderUIDs: []string{"order-1", "order-2"}}, read(t, conn))
+	assert.Equal(t, "order-1", read(t, conn).OrderUID)
+	assert.Equal(t, "order-2", read(t, conn).OrderUID)
+
+	require.NoError(t, conn.WriteJSON(clientMessage{Action: "subscribe", OrderUIDs: []string{"order-2", "order-1"}}))
+	assert.Equal(t, serverMessage{Type: "subscribed", OrderUIDs: []string{"order-2", "order-1"}}, read(t, conn))
+}